@@ -160,6 +160,8 @@ func TestFilteredDynamicSharedInformerFactory(t *testing.T) {
 		trigger          func(gvr schema.GroupVersionResource, ns string, fakeClient *fake.FakeDynamicClient, testObject *unstructured.Unstructured) *unstructured.Unstructured
 		handler          func(rcvCh chan<- *unstructured.Unstructured) *cache.ResourceEventHandlerFuncs
 		tweakListOptions dynamicinformer.TweakListOptionsFunc
+		// filteredOut is set when the object does not match the tweaked list options.
+		filteredOut bool
 	}{
 		// scenario 1
 		{
@@ -189,6 +191,7 @@ func TestFilteredDynamicSharedInformerFactory(t *testing.T) {
 			tweakListOptions: func(opts *metav1.ListOptions) {
 				opts.FieldSelector = "metadata.name=name-bar"
 			},
+			filteredOut: true,
 		},
 		{
 			name:     "tweak options: test adding an object matching field selector should trigger AddFunc",
@@ -211,6 +214,7 @@ func TestFilteredDynamicSharedInformerFactory(t *testing.T) {
 			tweakListOptions: func(opts *metav1.ListOptions) {
 				opts.LabelSelector = "environment=production"
 			},
+			filteredOut: true,
 		},
 		{
 			name:     "tweak options: test adding an object matching label selector should trigger AddFunc",
@@ -243,7 +247,7 @@ func TestFilteredDynamicSharedInformerFactory(t *testing.T) {
 				{Group: "apps", Version: "v1", Resource: "deployments"}: "DeploymentList",
 			}
 			fakeClient := fake.NewSimpleDynamicClientWithCustomListKinds(scheme, gvrToListKind, objs...)
-			target := dynamicinformer.NewFilteredDynamicSharedInformerFactory(fakeClient, 0, ts.informNS, nil)
+			target := dynamicinformer.NewFilteredDynamicSharedInformerFactory(fakeClient, 0, ts.informNS, ts.tweakListOptions)
 
 			// act
 			informerListerForGvr := target.ForResource(ts.gvr)
@@ -254,16 +258,20 @@ func TestFilteredDynamicSharedInformerFactory(t *testing.T) {
 			}
 
 			testObject := ts.trigger(ts.gvr, ts.ns, fakeClient, ts.existingObj)
+			expectEvent := ts.ns == ts.informNS && !ts.filteredOut
 			select {
 			case objFromInformer := <-informerReciveObjectCh:
 				if ts.ns != ts.informNS {
 					t.Errorf("informer received an object for namespace %s when watching namespace %s", ts.ns, ts.informNS)
 				}
+				if ts.filteredOut {
+					t.Errorf("informer received an object which does not match the list options")
+				}
 				if !equality.Semantic.DeepEqual(testObject, objFromInformer) {
 					t.Fatalf("%v", cmp.Diff(testObject, objFromInformer))
 				}
 			case <-ctx.Done():
-				if ts.ns == ts.informNS {
+				if expectEvent {
 					t.Errorf("tested informer haven't received an object, waited %v", timeout)
 				}
 			}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/pkg/apis/clientauthentication"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/util/homedir"
+	"k8s.io/utils/dump"
+)
+
+// defaultCredentialCacheDir is where the credentials of exec plugins with
+// CacheCredentials set are cached.
+var defaultCredentialCacheDir = filepath.Join(homedir.HomeDir(), ".kube", "cache", "exec")
+
+// credentialCache stores the output of an exec plugin on disk, so that short
+// lived processes like kubectl can share the credentials instead of each
+// executing the plugin. Entries are written atomically, so concurrent
+// processes see either the old or the new output of the plugin.
+type credentialCache struct {
+	path string
+}
+
+func newCredentialCache(dir string, config *api.ExecConfig, cluster *clientauthentication.Cluster, environ []string) *credentialCache {
+	return &credentialCache{path: filepath.Join(dir, credentialCacheKey(config, cluster, environ)+".json")}
+}
+
+// credentialEnvVars are the inherited environment variables which commonly
+// select the identity that a plugin returns credentials for. The rest of the
+// environment, like the variables of the terminal session, is left out of the
+// key, so that processes started from different shells share the entry.
+var credentialEnvVars = sets.New(
+	"KUBECONFIG",
+	"AWS_PROFILE", "AWS_DEFAULT_PROFILE", "AWS_REGION", "AWS_DEFAULT_REGION", "AWS_ROLE_ARN", "AWS_CONFIG_FILE", "AWS_SHARED_CREDENTIALS_FILE",
+	"CLOUDSDK_CONFIG", "CLOUDSDK_CORE_ACCOUNT", "CLOUDSDK_CORE_PROJECT", "GOOGLE_APPLICATION_CREDENTIALS",
+	"AZURE_CONFIG_DIR", "AZURE_TENANT_ID", "AZURE_CLIENT_ID", "AAD_SERVICE_PRINCIPAL_CLIENT_ID",
+)
+
+// credentialCacheKey hashes the exec config and the inherited
+// credentialEnvVars, which determine the output of the plugin. Unlike
+// cacheKey, it leaves out the fields which describe how the plugin may
+// interact with the current process, so that processes with and without a
+// terminal share the entry.
+func credentialCacheKey(config *api.ExecConfig, cluster *clientauthentication.Cluster, environ []string) string {
+	var inherited []string
+	for _, env := range environ {
+		name, _, _ := strings.Cut(env, "=")
+		if credentialEnvVars.Has(name) {
+			inherited = append(inherited, env)
+		}
+	}
+	slices.Sort(inherited)
+	key := struct {
+		command            string
+		args               []string
+		env                []api.ExecEnvVar
+		inheritedEnv       []string
+		apiVersion         string
+		provideClusterInfo bool
+		cluster            *clientauthentication.Cluster
+	}{
+		command:            config.Command,
+		args:               config.Args,
+		env:                config.Env,
+		inheritedEnv:       inherited,
+		apiVersion:         config.APIVersion,
+		provideClusterInfo: config.ProvideClusterInfo,
+		cluster:            cluster,
+	}
+	hash := sha256.Sum256([]byte(dump.Pretty(key)))
+	return hex.EncodeToString(hash[:])
+}
+
+// read returns the cached output of the plugin, or nil if there is none.
+func (c *credentialCache) read() ([]byte, error) {
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// write replaces the cached output of the plugin. The file is created with
+// mode 0600 by os.CreateTemp, since it holds credentials.
+func (c *credentialCache) write(data []byte) (err error) {
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, filepath.Base(c.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+	if _, err = f.Write(data); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), c.path)
+}
+
+// remove invalidates the cached output of the plugin, for example because it
+// was rejected or expired.
+func (c *credentialCache) remove() error {
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/pkg/apis/clientauthentication"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestCredentialCacheKey(t *testing.T) {
+	config := &api.ExecConfig{
+		Command:    "foo",
+		Args:       []string{"bar"},
+		APIVersion: "client.authentication.k8s.io/v1",
+	}
+	cluster := &clientauthentication.Cluster{Server: "https://foo"}
+	environ := []string{"AWS_PROFILE=dev", "PWD=/home/gopher"}
+	key := credentialCacheKey(config, cluster, environ)
+
+	interactive := *config
+	interactive.InteractiveMode = api.AlwaysExecInteractiveMode
+	interactive.StdinUnavailable = true
+	if credentialCacheKey(&interactive, cluster, environ) != key {
+		t.Error("expected the interactive mode not to change the key")
+	}
+
+	otherArgs := *config
+	otherArgs.Args = []string{"baz"}
+	if credentialCacheKey(&otherArgs, cluster, environ) == key {
+		t.Error("expected the arguments to change the key")
+	}
+	if credentialCacheKey(config, &clientauthentication.Cluster{Server: "https://bar"}, environ) == key {
+		t.Error("expected the cluster to change the key")
+	}
+	if credentialCacheKey(config, cluster, []string{"AWS_PROFILE=prod", "PWD=/home/gopher"}) == key {
+		t.Error("expected the inherited environment to change the key")
+	}
+	if credentialCacheKey(config, cluster, []string{"PWD=/tmp", "TERM_SESSION_ID=w0t1p0", "AWS_PROFILE=dev", "SSH_CONNECTION=10.0.0.1 22"}) != key {
+		t.Error("expected the variables of the session not to change the key")
+	}
+}
+
+func TestCachedCredentials(t *testing.T) {
+	defer func(dir string) { defaultCredentialCacheDir = dir }(defaultCredentialCacheDir)
+	defaultCredentialCacheDir = t.TempDir()
+
+	n := time.Now()
+	now := func() time.Time { return n }
+	output := ""
+	newTestAuthenticator := func() *Authenticator {
+		c := api.ExecConfig{
+			Command:          "./testdata/test-plugin.sh",
+			APIVersion:       "client.authentication.k8s.io/v1beta1",
+			InteractiveMode:  api.IfAvailableExecInteractiveMode,
+			CacheCredentials: true,
+		}
+		// Each authenticator stands for a different process.
+		a, err := newAuthenticator(newCache(), func(_ int) bool { return false }, &c, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.environ = func() []string { return []string{"TEST_OUTPUT=" + output} }
+		a.now = now
+		a.stderr = io.Discard
+		return a
+	}
+	setToken := func(token string, exp time.Time) {
+		output = `{
+			"kind": "ExecCredential",
+			"apiVersion": "client.authentication.k8s.io/v1beta1",
+			"status": {
+				"token": "` + token + `"`
+		if !exp.IsZero() {
+			output += `,
+				"expirationTimestamp": "` + exp.Format(time.RFC3339Nano) + `"`
+		}
+		output += `
+			}
+		}`
+	}
+	getToken := func(t *testing.T, a *Authenticator) string {
+		t.Helper()
+		creds, err := a.getCreds()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return creds.token
+	}
+
+	// Credentials without an expiration are not cached.
+	setToken("token1", time.Time{})
+	if token := getToken(t, newTestAuthenticator()); token != "token1" {
+		t.Errorf("expected token1, got %q", token)
+	}
+	setToken("token2", n.Add(time.Hour))
+	if token := getToken(t, newTestAuthenticator()); token != "token2" {
+		t.Errorf("expected token2 from the plugin, got %q", token)
+	}
+
+	// Another process uses the cached credentials instead of the plugin.
+	setToken("token3", n.Add(time.Hour))
+	a := newTestAuthenticator()
+	if token := getToken(t, a); token != "token2" {
+		t.Errorf("expected the cached token2, got %q", token)
+	}
+	if runtime.GOOS != "windows" {
+		cachePath := a.credentialCache.path
+		info, err := os.Stat(cachePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("expected mode 0600 for the cached credentials, got %v", info.Mode().Perm())
+		}
+	}
+
+	// A rejected token is removed from the cache.
+	creds, err := a.getCreds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.maybeRefreshCreds(creds); err != nil {
+		t.Fatal(err)
+	}
+	if token := getToken(t, a); token != "token3" {
+		t.Errorf("expected token3 after the rotation, got %q", token)
+	}
+	data, err := os.ReadFile(a.credentialCache.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "token3") {
+		t.Errorf("expected token3 to be cached, got %s", data)
+	}
+
+	// Expired credentials in the cache are ignored and removed.
+	n = n.Add(2 * time.Hour)
+	a = newTestAuthenticator()
+	if a.loadCachedCredsLocked() {
+		t.Error("expected the expired token3 to be ignored")
+	}
+	if _, err := os.Stat(a.credentialCache.path); !os.IsNotExist(err) {
+		t.Errorf("expected the expired token3 to be removed, got %v", err)
+	}
+	setToken("token4", n.Add(time.Hour))
+	if token := getToken(t, a); token != "token4" {
+		t.Errorf("expected token4 from the plugin, got %q", token)
+	}
+
+	// So are undecodable credentials.
+	if err := os.WriteFile(a.credentialCache.path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if newTestAuthenticator().loadCachedCredsLocked() {
+		t.Error("expected the undecodable credentials to be ignored")
+	}
+	if _, err := os.Stat(a.credentialCache.path); !os.IsNotExist(err) {
+		t.Errorf("expected the undecodable credentials to be removed, got %v", err)
+	}
+}
@@ -223,6 +223,10 @@ func newAuthenticator(c *cache, isTerminalFunc func(int) bool, config *api.ExecC
 		a.env = append(a.env, env.Name+"="+env.Value)
 	}
 
+	if config.CacheCredentials {
+		a.credentialCache = newCredentialCache(defaultCredentialCacheDir, config, cluster, a.environ())
+	}
+
 	// these functions are made comparable and stored in the cache so that repeated clientset
 	// construction with the same rest.Config results in a single TLS cache and Authenticator
 	a.getCert = &transport.GetCertHolder{GetCert: a.cert}
@@ -272,6 +276,9 @@ type Authenticator struct {
 	allowlistLookup  sets.Set[string]
 	execPluginPolicy api.PluginPolicy
 
+	// credentialCache is set if the credentials are cached on disk.
+	credentialCache *credentialCache
+
 	// Used to avoid log spew by rate limiting install hint printing. We didn't do
 	// this by interval based rate limiting alone since that way may have prevented
 	// the install hint from showing up for kubectl users.
@@ -407,6 +414,10 @@ func (a *Authenticator) getCreds() (*credentials, error) {
 		return a.cachedCreds, nil
 	}
 
+	if a.loadCachedCredsLocked() {
+		return a.cachedCreds, nil
+	}
+
 	if err := a.refreshCredsLocked(); err != nil {
 		return nil, err
 	}
@@ -427,9 +438,57 @@ func (a *Authenticator) maybeRefreshCreds(creds *credentials) error {
 		return nil
 	}
 
+	// The cached credentials were rejected, so other processes must not use
+	// them either.
+	a.removeCachedCredsLocked()
+
 	return a.refreshCredsLocked()
 }
 
+// removeCachedCredsLocked removes the credentials cached on disk. It must be
+// called while holding the Authenticator's mutex.
+func (a *Authenticator) removeCachedCredsLocked() {
+	if a.credentialCache == nil {
+		return
+	}
+	if err := a.credentialCache.remove(); err != nil {
+		klog.V(2).Infof("exec plugin: removing cached credentials: %v", err)
+	}
+}
+
+// loadCachedCredsLocked uses the credentials cached on disk, if there are
+// any which did not expire. Expired and invalid credentials are removed from
+// the disk. It must be called while holding the Authenticator's mutex.
+func (a *Authenticator) loadCachedCredsLocked() bool {
+	if a.credentialCache == nil {
+		return false
+	}
+	data, err := a.credentialCache.read()
+	if err != nil {
+		klog.V(2).Infof("exec plugin: reading cached credentials: %v", err)
+		return false
+	}
+	if data == nil {
+		return false
+	}
+	cred, err := a.decodeCredLocked(data)
+	if err != nil {
+		klog.V(2).Infof("exec plugin: ignoring cached credentials: %v", err)
+		a.removeCachedCredsLocked()
+		return false
+	}
+	if cred.Status.ExpirationTimestamp == nil || !a.now().Before(cred.Status.ExpirationTimestamp.Time) {
+		a.removeCachedCredsLocked()
+		return false
+	}
+	if err := a.setCredsLocked(cred.Status); err != nil {
+		klog.V(2).Infof("exec plugin: ignoring cached credentials: %v", err)
+		a.removeCachedCredsLocked()
+		return false
+	}
+	return true
+}
+
 // refreshCredsLocked executes the plugin and reads the credentials from
 // stdout. It must be called while holding the Authenticator's mutex.
 func (a *Authenticator) refreshCredsLocked() error {
@@ -475,36 +534,63 @@ func (a *Authenticator) refreshCredsLocked() error {
 		return a.wrapCmdRunErrorLocked(err)
 	}
 
-	_, gvk, err := codecs.UniversalDecoder(a.group).Decode(stdout.Bytes(), nil, cred)
+	cred, err = a.decodeCredLocked(stdout.Bytes())
 	if err != nil {
-		return fmt.Errorf("decoding stdout: %v", err)
+		return err
+	}
+	if err := a.setCredsLocked(cred.Status); err != nil {
+		return err
+	}
+
+	// Credentials without an expiration are only valid until they are
+	// rejected, so they are not shared with other processes.
+	if a.credentialCache != nil && cred.Status.ExpirationTimestamp != nil {
+		if err := a.credentialCache.write(stdout.Bytes()); err != nil {
+			klog.V(2).Infof("exec plugin: caching credentials: %v", err)
+		}
+	}
+	return nil
+}
+
+// decodeCredLocked decodes and validates the output of the plugin. It must
+// be called while holding the Authenticator's mutex.
+func (a *Authenticator) decodeCredLocked(data []byte) (*clientauthentication.ExecCredential, error) {
+	cred := &clientauthentication.ExecCredential{}
+	_, gvk, err := codecs.UniversalDecoder(a.group).Decode(data, nil, cred)
+	if err != nil {
+		return nil, fmt.Errorf("decoding stdout: %v", err)
 	}
 	if gvk.Group != a.group.Group || gvk.Version != a.group.Version {
-		return fmt.Errorf("exec plugin is configured to use API version %s, plugin returned version %s",
+		return nil, fmt.Errorf("exec plugin is configured to use API version %s, plugin returned version %s",
 			a.group, schema.GroupVersion{Group: gvk.Group, Version: gvk.Version})
 	}
 
 	if cred.Status == nil {
-		return fmt.Errorf("exec plugin didn't return a status field")
+		return nil, fmt.Errorf("exec plugin didn't return a status field")
 	}
 	if cred.Status.Token == "" && cred.Status.ClientCertificateData == "" && cred.Status.ClientKeyData == "" {
-		return fmt.Errorf("exec plugin didn't return a token or cert/key pair")
+		return nil, fmt.Errorf("exec plugin didn't return a token or cert/key pair")
 	}
 	if (cred.Status.ClientCertificateData == "") != (cred.Status.ClientKeyData == "") {
-		return fmt.Errorf("exec plugin returned only certificate or key, not both")
+		return nil, fmt.Errorf("exec plugin returned only certificate or key, not both")
 	}
+	return cred, nil
+}
 
-	if cred.Status.ExpirationTimestamp != nil {
-		a.exp = cred.Status.ExpirationTimestamp.Time
+// setCredsLocked replaces the cached credentials. It must be called while
+// holding the Authenticator's mutex.
+func (a *Authenticator) setCredsLocked(status *clientauthentication.ExecCredentialStatus) error {
+	if status.ExpirationTimestamp != nil {
+		a.exp = status.ExpirationTimestamp.Time
 	} else {
 		a.exp = time.Time{}
 	}
 
 	newCreds := &credentials{
-		token: cred.Status.Token,
+		token: status.Token,
 	}
-	if cred.Status.ClientKeyData != "" && cred.Status.ClientCertificateData != "" {
-		cert, err := tls.X509KeyPair([]byte(cred.Status.ClientCertificateData), []byte(cred.Status.ClientKeyData))
+	if status.ClientKeyData != "" && status.ClientCertificateData != "" {
+		cert, err := tls.X509KeyPair([]byte(status.ClientCertificateData), []byte(status.ClientKeyData))
 		if err != nil {
 			return fmt.Errorf("failed parsing client key/certificate: %v", err)
 		}
@@ -0,0 +1,280 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// loginFlowDeviceCode is the device authorization grant (RFC 8628). The
+	// user enters a code on another device, which suits headless machines.
+	loginFlowDeviceCode = "device-code"
+	// loginFlowAuthorizationCode is the authorization code grant with PKCE
+	// (RFC 7636). The user logs in with a browser, which is redirected to a
+	// loopback address the client listens on (RFC 8252).
+	loginFlowAuthorizationCode = "authorization-code"
+
+	defaultRedirectURL  = "http://127.0.0.1:0/callback"
+	defaultLoginTimeout = 5 * time.Minute
+
+	grantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	tokenTypeIDToken       = "urn:ietf:params:oauth:token-type:id_token"
+)
+
+// loginLocked performs the configured login flow, persists the tokens and
+// returns the id token to use. It must be called while holding the mutex.
+func (p *oidcAuthProvider) loginLocked() (string, error) {
+	metadata, err := discover(p.client, p.cfg[cfgIssuerURL])
+	if err != nil {
+		return "", err
+	}
+	config := p.oauth2Config(metadata)
+	config.Scopes = loginScopes(p.cfg[cfgExtraScopes])
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.loginTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+
+	var token *oauth2.Token
+	switch flow := p.cfg[cfgLoginFlow]; flow {
+	case loginFlowDeviceCode:
+		token, err = p.deviceCodeLogin(ctx, config)
+	case loginFlowAuthorizationCode:
+		token, err = p.authorizationCodeLogin(ctx, config)
+	default:
+		return "", fmt.Errorf("unknown %s %q", cfgLoginFlow, flow)
+	}
+	if err != nil {
+		return "", fmt.Errorf("oidc: failed to log in: %w", err)
+	}
+	return p.persistTokenLocked(metadata, token)
+}
+
+// loginScopes returns the scopes requested during login, openid and the
+// comma separated extra scopes. Providers usually only issue a refresh token
+// if the extra scopes include offline_access.
+func loginScopes(extraScopes string) []string {
+	scopes := []string{"openid"}
+	for _, scope := range strings.Split(extraScopes, ",") {
+		scope = strings.TrimSpace(scope)
+		if len(scope) > 0 && scope != "openid" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+func (p *oidcAuthProvider) deviceCodeLogin(ctx context.Context, config oauth2.Config) (*oauth2.Token, error) {
+	if len(config.Endpoint.DeviceAuthURL) == 0 {
+		return nil, errors.New("discovery object doesn't contain a device_authorization_endpoint")
+	}
+	auth, err := config.DeviceAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(auth.VerificationURIComplete) > 0 {
+		fmt.Fprintf(p.out, "To log in, open %s and confirm the code %s\n", auth.VerificationURIComplete, auth.UserCode)
+	} else {
+		fmt.Fprintf(p.out, "To log in, open %s and enter the code %s\n", auth.VerificationURI, auth.UserCode)
+	}
+	return config.DeviceAccessToken(ctx, auth)
+}
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+func (p *oidcAuthProvider) authorizationCodeLogin(ctx context.Context, config oauth2.Config) (*oauth2.Token, error) {
+	if len(config.Endpoint.AuthURL) == 0 {
+		return nil, errors.New("discovery object doesn't contain an authorization_endpoint")
+	}
+	redirectURL := p.cfg[cfgRedirectURL]
+	if len(redirectURL) == 0 {
+		redirectURL = defaultRedirectURL
+	}
+	redirect, err := url.Parse(redirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", cfgRedirectURL, err)
+	}
+	if redirect.Scheme != "http" || !isLoopback(redirect.Hostname()) {
+		return nil, fmt.Errorf("invalid %s %q: only http loopback addresses are supported", cfgRedirectURL, redirectURL)
+	}
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the redirect: %w", err)
+	}
+	defer listener.Close()
+	// The port may have been chosen by the system.
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		return nil, err
+	}
+	redirect.Host = net.JoinHostPort(redirect.Hostname(), port)
+	if len(redirect.Path) == 0 {
+		redirect.Path = "/"
+	}
+	config.RedirectURL = redirect.String()
+
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	results := make(chan callbackResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(redirect.Path, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		// Ignore requests which are not the answer to this login.
+		if query.Get("state") != state {
+			http.Error(w, "Unexpected state", http.StatusBadRequest)
+			return
+		}
+		var result callbackResult
+		switch {
+		case len(query.Get("error")) > 0:
+			result.err = fmt.Errorf("authorization failed: %s %s", query.Get("error"), query.Get("error_description"))
+			http.Error(w, "Login failed, you can close this window.", http.StatusUnauthorized)
+		case len(query.Get("code")) == 0:
+			result.err = errors.New("authorization response doesn't contain a code")
+			http.Error(w, "Login failed, you can close this window.", http.StatusBadRequest)
+		default:
+			result.code = query.Get("code")
+			fmt.Fprintln(w, "Login succeeded, you can close this window.")
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux}
+	serveErrs := make(chan error, 1)
+	go func() {
+		serveErrs <- server.Serve(listener)
+	}()
+	defer server.Close()
+
+	authURL := config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+	if err := p.openURL(authURL); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for the redirect: %w", ctx.Err())
+	case err := <-serveErrs:
+		return nil, fmt.Errorf("failed to serve the redirect: %w", err)
+	case result := <-results:
+		if result.err != nil {
+			return nil, result.err
+		}
+		return config.Exchange(ctx, result.code, oauth2.VerifierOption(verifier))
+	}
+}
+
+// isLoopback returns true if host is localhost or a loopback IP address, so
+// that only the current machine can send the redirect.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// printURL is the default openURL, it asks the user to open the URL.
+func (p *oidcAuthProvider) printURL(authURL string) error {
+	_, err := fmt.Fprintf(p.out, "To log in, open the following URL in a browser:\n\n    %s\n\n", authURL)
+	return err
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// exchangeToken exchanges an id token for an id token of another audience.
+//
+// https://datatracker.ietf.org/doc/html/rfc8693
+func exchangeToken(client *http.Client, tokenURL, clientID, clientSecret, idToken, audience string) (string, error) {
+	form := url.Values{
+		"grant_type":           {grantTypeTokenExchange},
+		"subject_token":        {idToken},
+		"subject_token_type":   {tokenTypeIDToken},
+		"requested_token_type": {tokenTypeIDToken},
+		"audience":             {audience},
+	}
+	if len(clientSecret) == 0 {
+		form.Set("client_id", clientID)
+	}
+	req, err := http.NewRequest(http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if len(clientSecret) > 0 {
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Don't produce an error that's too huge (e.g. if we get HTML back for some reason).
+		const n = 80
+		if len(body) > n {
+			body = append(body[:n], []byte("...")...)
+		}
+		return "", fmt.Errorf("oidc: failed to exchange token %s: %q", resp.Status, body)
+	}
+
+	var response struct {
+		AccessToken     string `json:"access_token"`
+		IssuedTokenType string `json:"issued_token_type"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("oidc: failed to decode token exchange response: %v", err)
+	}
+	if response.IssuedTokenType != tokenTypeIDToken || len(response.AccessToken) == 0 {
+		return "", fmt.Errorf("oidc: token exchange didn't issue an id token, got token type %q", response.IssuedTokenType)
+	}
+	return response.AccessToken, nil
+}
@@ -0,0 +1,393 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// fakeIssuer is an OpenID Connect provider which issues an id token for
+// every grant it supports.
+type fakeIssuer struct {
+	server *httptest.Server
+
+	mu sync.Mutex
+	// challenges are the PKCE challenges of the issued authorization codes.
+	challenges map[string]string
+	grants     []string
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	i := &fakeIssuer{challenges: map[string]string{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{
+			"issuer": %[1]q,
+			"authorization_endpoint": "%[1]s/authorize",
+			"device_authorization_endpoint": "%[1]s/device",
+			"token_endpoint": "%[1]s/token"
+		}`, i.server.URL)
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("code_challenge_method") != "S256" || len(query.Get("code_challenge")) == 0 {
+			http.Error(w, "missing PKCE challenge", http.StatusBadRequest)
+			return
+		}
+		if query.Get("scope") != "openid offline_access" {
+			http.Error(w, "unexpected scope "+query.Get("scope"), http.StatusBadRequest)
+			return
+		}
+		i.mu.Lock()
+		i.challenges["auth-code"] = query.Get("code_challenge")
+		i.mu.Unlock()
+		redirect, err := url.Parse(query.Get("redirect_uri"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		redirect.RawQuery = url.Values{"code": {"auth-code"}, "state": {query.Get("state")}}.Encode()
+		http.Redirect(w, r, redirect.String(), http.StatusFound)
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{
+			"device_code": "device-code",
+			"user_code": "ABCD-EFGH",
+			"verification_uri": "%s/activate",
+			"expires_in": 60,
+			"interval": 1
+		}`, i.server.URL)
+	})
+	mux.HandleFunc("/token", i.token)
+	i.server = httptest.NewServer(mux)
+	t.Cleanup(i.server.Close)
+	return i
+}
+
+func (i *fakeIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	grantType := r.PostForm.Get("grant_type")
+	i.mu.Lock()
+	i.grants = append(i.grants, grantType)
+	i.mu.Unlock()
+
+	audience := "client"
+	switch grantType {
+	case "authorization_code":
+		i.mu.Lock()
+		challenge := i.challenges[r.PostForm.Get("code")]
+		i.mu.Unlock()
+		if oauth2.S256ChallengeFromVerifier(r.PostForm.Get("code_verifier")) != challenge {
+			tokenError(w, "invalid_grant")
+			return
+		}
+	case "urn:ietf:params:oauth:grant-type:device_code":
+		if r.PostForm.Get("device_code") != "device-code" {
+			tokenError(w, "invalid_grant")
+			return
+		}
+	case "refresh_token":
+		if r.PostForm.Get("refresh_token") != "refresh-token" {
+			tokenError(w, "invalid_grant")
+			return
+		}
+	case grantTypeTokenExchange:
+		if r.PostForm.Get("subject_token_type") != tokenTypeIDToken || len(r.PostForm.Get("subject_token")) == 0 {
+			tokenError(w, "invalid_request")
+			return
+		}
+		audience = r.PostForm.Get("audience")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": %q, "issued_token_type": %q, "token_type": "N_A"}`, i.idToken(audience), tokenTypeIDToken)
+		return
+	default:
+		tokenError(w, "unsupported_grant_type")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{
+		"access_token": "access-token",
+		"token_type": "Bearer",
+		"refresh_token": "refresh-token",
+		"id_token": %q
+	}`, i.idToken(audience))
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	fmt.Fprintf(w, `{"error": %q}`, code)
+}
+
+func (i *fakeIssuer) idToken(audience string) string {
+	return encodeJWT("{}", fmt.Sprintf(`{"aud":%q,"exp":%d}`, audience, time.Now().Add(time.Hour).Unix()), "sig")
+}
+
+func (i *fakeIssuer) grantTypes() []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]string(nil), i.grants...)
+}
+
+type fakePersister struct {
+	mu  sync.Mutex
+	cfg map[string]string
+}
+
+func (p *fakePersister) Persist(cfg map[string]string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cfg = cfg
+	return nil
+}
+
+func (p *fakePersister) get(key string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cfg[key]
+}
+
+func newTestProvider(issuer *fakeIssuer, cfg map[string]string) (*oidcAuthProvider, *fakePersister, *bytes.Buffer) {
+	cfg[cfgIssuerURL] = issuer.server.URL
+	cfg[cfgClientID] = "client"
+	persister := &fakePersister{}
+	out := &bytes.Buffer{}
+	p := &oidcAuthProvider{
+		client:       issuer.server.Client(),
+		now:          time.Now,
+		out:          out,
+		loginTimeout: 30 * time.Second,
+		cfg:          cfg,
+		persister:    persister,
+	}
+	// Act as the browser of the user, who approves the login.
+	p.openURL = func(authURL string) error {
+		resp, err := http.Get(authURL)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected response %s: %s", resp.Status, body)
+		}
+		return nil
+	}
+	return p, persister, out
+}
+
+func audienceOf(t *testing.T, idToken string) string {
+	t.Helper()
+	var claims struct {
+		Audience string `json:"aud"`
+	}
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		t.Fatalf("not a JWT: %q", idToken)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatal(err)
+	}
+	return claims.Audience
+}
+
+func TestLoginAuthorizationCode(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	p, persister, _ := newTestProvider(issuer, map[string]string{
+		cfgLoginFlow:   loginFlowAuthorizationCode,
+		cfgExtraScopes: "offline_access",
+	})
+
+	if err := p.Login(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	idToken := persister.get(cfgIDToken)
+	if audienceOf(t, idToken) != "client" {
+		t.Errorf("expected an id token for the client, got %q", idToken)
+	}
+	if rt := persister.get(cfgRefreshToken); rt != "refresh-token" {
+		t.Errorf("expected the refresh token to be persisted, got %q", rt)
+	}
+
+	// The persisted id token is used until it expires.
+	token, err := p.idToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != idToken {
+		t.Errorf("expected the persisted id token, got %q", token)
+	}
+	if grants := issuer.grantTypes(); len(grants) != 1 || grants[0] != "authorization_code" {
+		t.Errorf("expected a single authorization code grant, got %v", grants)
+	}
+}
+
+func TestLoginRedirectURL(t *testing.T) {
+	for redirectURL, valid := range map[string]bool{
+		"http://127.0.0.1:0/callback": true,
+		"http://[::1]:0/callback":     true,
+		"http://localhost:0/callback": true,
+		"http://0.0.0.0:0/callback":   false,
+		"http://:0/callback":          false,
+		"http://[::]:0/callback":      false,
+		"http://example.com/callback": false,
+		"https://127.0.0.1:0/":        false,
+	} {
+		t.Run(redirectURL, func(t *testing.T) {
+			issuer := newFakeIssuer(t)
+			p, _, _ := newTestProvider(issuer, map[string]string{
+				cfgLoginFlow:   loginFlowAuthorizationCode,
+				cfgExtraScopes: "offline_access",
+				cfgRedirectURL: redirectURL,
+			})
+			err := p.Login()
+			if valid && err != nil {
+				var opErr *net.OpError
+				if errors.As(err, &opErr) {
+					t.Skipf("loopback address not available: %v", err)
+				}
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !valid && (err == nil || !strings.Contains(err.Error(), "only http loopback addresses are supported")) {
+				t.Errorf("expected the redirect URL to be rejected, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoginDeviceCode(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	p, persister, out := newTestProvider(issuer, map[string]string{
+		cfgLoginFlow: loginFlowDeviceCode,
+	})
+
+	// Requests don't log in by themselves.
+	if _, err := p.idToken(); err == nil {
+		t.Fatal("expected an error without tokens")
+	}
+	if out.Len() > 0 {
+		t.Fatalf("expected no instructions for the user, got %q", out.String())
+	}
+
+	if err := p.Login(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	token, err := p.idToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != persister.get(cfgIDToken) {
+		t.Errorf("expected the id token to be persisted")
+	}
+	if !strings.Contains(out.String(), issuer.server.URL+"/activate") || !strings.Contains(out.String(), "ABCD-EFGH") {
+		t.Errorf("expected instructions for the user, got %q", out.String())
+	}
+}
+
+func TestRefreshFailureDoesNotLogIn(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	p, _, out := newTestProvider(issuer, map[string]string{
+		cfgLoginFlow:    loginFlowDeviceCode,
+		cfgRefreshToken: "revoked",
+	})
+
+	if _, err := p.idToken(); err == nil {
+		t.Fatal("expected an error for a revoked refresh token")
+	}
+	if out.Len() > 0 {
+		t.Errorf("expected no instructions for the user, got %q", out.String())
+	}
+	// The client may try the refresh with several authentication styles.
+	for _, grant := range issuer.grantTypes() {
+		if grant != "refresh_token" {
+			t.Errorf("expected only refresh token grants, got %v", issuer.grantTypes())
+		}
+	}
+}
+
+func TestTokenExchange(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	p, persister, _ := newTestProvider(issuer, map[string]string{
+		cfgRefreshToken:          "refresh-token",
+		cfgTokenExchangeAudience: "cluster",
+	})
+
+	token, err := p.idToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if audienceOf(t, token) != "cluster" {
+		t.Errorf("expected an id token for the cluster, got %q", token)
+	}
+	if persister.get(cfgIDToken) != token {
+		t.Errorf("expected the exchanged id token to be persisted")
+	}
+	expected := []string{"refresh_token", grantTypeTokenExchange}
+	if grants := issuer.grantTypes(); strings.Join(grants, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected grants %v, got %v", expected, grants)
+	}
+}
+
+func TestLoginWithoutFlow(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	p, _, _ := newTestProvider(issuer, map[string]string{})
+	if err := p.Login(); err == nil {
+		t.Error("expected an error without a login flow")
+	}
+	if _, err := p.idToken(); err == nil {
+		t.Error("expected an error without tokens and login flow")
+	}
+	if grants := issuer.grantTypes(); len(grants) != 0 {
+		t.Errorf("expected no requests to the token endpoint, got %v", grants)
+	}
+}
+
+func TestLoginScopes(t *testing.T) {
+	for extraScopes, expected := range map[string]string{
+		"":                       "openid",
+		"offline_access":         "openid offline_access",
+		"openid, email ,groups,": "openid email groups",
+	} {
+		if scopes := strings.Join(loginScopes(extraScopes), " "); scopes != expected {
+			t.Errorf("extra scopes %q: expected %q, got %q", extraScopes, expected, scopes)
+		}
+	}
+}
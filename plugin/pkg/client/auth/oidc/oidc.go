@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -43,8 +44,19 @@ const (
 	cfgIDToken                  = "id-token"
 	cfgRefreshToken             = "refresh-token"
 
-	// Unused. Scopes aren't sent during refreshing.
+	// Only used by the login flows. Scopes aren't sent during refreshing.
 	cfgExtraScopes = "extra-scopes"
+
+	// The flow used by Login to obtain an id-token and a refresh-token. One of
+	// loginFlowDeviceCode and loginFlowAuthorizationCode. Requests never log in
+	// by themselves, since the flows wait for the user.
+	cfgLoginFlow = "login-flow"
+	// The loopback URL the authorization code flow redirects to. It defaults
+	// to a random port of 127.0.0.1.
+	cfgRedirectURL = "redirect-url"
+	// If set, the id token of the issuer is exchanged for a token of this
+	// audience (RFC 8693), which is used instead.
+	cfgTokenExchangeAudience = "token-exchange-audience"
 )
 
 func init() {
@@ -124,7 +136,13 @@ func newOIDCAuthProvider(clusterAddress string, cfg map[string]string, persister
 		return provider, nil
 	}
 
-	if len(cfg[cfgExtraScopes]) > 0 {
+	switch cfg[cfgLoginFlow] {
+	case "", loginFlowDeviceCode, loginFlowAuthorizationCode:
+	default:
+		return nil, fmt.Errorf("%s must be %q or %q", cfgLoginFlow, loginFlowDeviceCode, loginFlowAuthorizationCode)
+	}
+
+	if len(cfg[cfgExtraScopes]) > 0 && len(cfg[cfgLoginFlow]) == 0 {
 		klog.V(2).Infof("%s auth provider field depricated, refresh request don't send scopes",
 			cfgExtraScopes)
 	}
@@ -152,11 +170,14 @@ func newOIDCAuthProvider(clusterAddress string, cfg map[string]string, persister
 	hc := &http.Client{Transport: trans}
 
 	provider := &oidcAuthProvider{
-		client:    hc,
-		now:       time.Now,
-		cfg:       cfg,
-		persister: persister,
+		client:       hc,
+		now:          time.Now,
+		out:          os.Stderr,
+		loginTimeout: defaultLoginTimeout,
+		cfg:          cfg,
+		persister:    persister,
 	}
+	provider.openURL = provider.printURL
 
 	return cache.setClient(clusterAddress, issuer, clientID, provider), nil
 }
@@ -167,6 +188,14 @@ type oidcAuthProvider struct {
 	// Method for determining the current time.
 	now func() time.Time
 
+	// out is where the login flows print instructions for the user.
+	out io.Writer
+	// openURL shows the authorization URL of the authorization code flow
+	// to the user.
+	openURL func(authURL string) error
+	// loginTimeout is how long the user has to complete a login flow.
+	loginTimeout time.Duration
+
 	// Mutex guards persisting to the kubeconfig file and allows synchronized
 	// updates to the in-memory config. It also ensures concurrent calls to
 	// the RoundTripper only trigger a single refresh request.
@@ -182,8 +211,18 @@ func (p *oidcAuthProvider) WrapTransport(rt http.RoundTripper) http.RoundTripper
 	}
 }
 
+// Login performs the configured login flow and persists the resulting tokens.
+// The flow waits for the user, so it is only run by Login and never by the
+// requests of the transport.
 func (p *oidcAuthProvider) Login() error {
-	return errors.New("not yet implemented")
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.cfg[cfgLoginFlow]) == 0 {
+		return fmt.Errorf("cannot log in without %s", cfgLoginFlow)
+	}
+	_, err := p.loginLocked()
+	return err
 }
 
 type roundTripper struct {
@@ -236,27 +275,50 @@ func (p *oidcAuthProvider) idToken() (string, error) {
 	// Try to request a new token using the refresh token.
 	rt, ok := p.cfg[cfgRefreshToken]
 	if !ok || len(rt) == 0 {
+		if len(p.cfg[cfgLoginFlow]) > 0 {
+			return "", fmt.Errorf("No valid id-token, and cannot refresh without refresh-token, log in with the %s flow", p.cfg[cfgLoginFlow])
+		}
 		return "", errors.New("No valid id-token, and cannot refresh without refresh-token")
 	}
 
-	// Determine provider's OAuth2 token endpoint.
-	tokenURL, err := tokenEndpoint(p.client, p.cfg[cfgIssuerURL])
+	// Determine provider's OAuth2 endpoints.
+	metadata, err := discover(p.client, p.cfg[cfgIssuerURL])
 	if err != nil {
 		return "", err
 	}
 
-	config := oauth2.Config{
-		ClientID:     p.cfg[cfgClientID],
-		ClientSecret: p.cfg[cfgClientSecret],
-		Endpoint:     oauth2.Endpoint{TokenURL: tokenURL},
-	}
-
+	config := p.oauth2Config(metadata)
 	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, p.client)
 	token, err := config.TokenSource(ctx, &oauth2.Token{RefreshToken: rt}).Token()
 	if err != nil {
+		if len(p.cfg[cfgLoginFlow]) > 0 {
+			// The refresh token may have expired or been revoked.
+			return "", fmt.Errorf("failed to refresh token, log in with the %s flow: %v", p.cfg[cfgLoginFlow], err)
+		}
 		return "", fmt.Errorf("failed to refresh token: %v", err)
 	}
 
+	return p.persistTokenLocked(metadata, token)
+}
+
+// oauth2Config returns the OAuth2 client configuration for the endpoints of
+// the provider.
+func (p *oidcAuthProvider) oauth2Config(metadata *providerMetadata) oauth2.Config {
+	return oauth2.Config{
+		ClientID:     p.cfg[cfgClientID],
+		ClientSecret: p.cfg[cfgClientSecret],
+		Endpoint: oauth2.Endpoint{
+			AuthURL:       metadata.AuthURL,
+			DeviceAuthURL: metadata.DeviceAuthURL,
+			TokenURL:      metadata.TokenURL,
+		},
+	}
+}
+
+// persistTokenLocked persists the id token and refresh token of a token
+// response, after exchanging the id token if the config asks for it, and
+// returns the id token to use. It must be called while holding the mutex.
+func (p *oidcAuthProvider) persistTokenLocked(metadata *providerMetadata, token *oauth2.Token) (string, error) {
 	idToken, ok := token.Extra("id_token").(string)
 	if !ok {
 		// id_token isn't a required part of a refresh token response, so some
@@ -266,6 +328,14 @@ func (p *oidcAuthProvider) idToken() (string, error) {
 		return "", fmt.Errorf("token response did not contain an id_token, either the scope \"openid\" wasn't requested upon login, or the provider doesn't support id_tokens as part of the refresh response")
 	}
 
+	if audience := p.cfg[cfgTokenExchangeAudience]; len(audience) > 0 {
+		exchanged, err := exchangeToken(p.client, metadata.TokenURL, p.cfg[cfgClientID], p.cfg[cfgClientSecret], idToken, audience)
+		if err != nil {
+			return "", err
+		}
+		idToken = exchanged
+	}
+
 	// Create a new config to persist.
 	newCfg := make(map[string]string)
 	for key, val := range p.cfg {
@@ -273,13 +343,13 @@ func (p *oidcAuthProvider) idToken() (string, error) {
 	}
 
 	// Update the refresh token if the server returned another one.
-	if token.RefreshToken != "" && token.RefreshToken != rt {
+	if token.RefreshToken != "" && token.RefreshToken != p.cfg[cfgRefreshToken] {
 		newCfg[cfgRefreshToken] = token.RefreshToken
 	}
 	newCfg[cfgIDToken] = idToken
 
 	// Persist new config and if successful, update the in memory config.
-	if err = p.persister.Persist(newCfg); err != nil {
+	if err := p.persister.Persist(newCfg); err != nil {
 		return "", fmt.Errorf("could not persist new tokens: %v", err)
 	}
 	p.cfg = newCfg
@@ -287,23 +357,34 @@ func (p *oidcAuthProvider) idToken() (string, error) {
 	return idToken, nil
 }
 
-// tokenEndpoint uses OpenID Connect discovery to determine the OAuth2 token
-// endpoint for the provider, the endpoint the client will use the refresh
-// token against.
-func tokenEndpoint(client *http.Client, issuer string) (string, error) {
+// providerMetadata holds the OAuth2 endpoints of a provider.
+//
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type providerMetadata struct {
+	// TokenURL is the endpoint the client will use the refresh token against.
+	TokenURL string `json:"token_endpoint"`
+	// AuthURL is the endpoint of the authorization code flow.
+	AuthURL string `json:"authorization_endpoint"`
+	// DeviceAuthURL is the endpoint of the device authorization flow (RFC 8628).
+	DeviceAuthURL string `json:"device_authorization_endpoint"`
+}
+
+// discover uses OpenID Connect discovery to determine the OAuth2 endpoints
+// for the provider.
+func discover(client *http.Client, issuer string) (*providerMetadata, error) {
 	// Well known URL for getting OpenID Connect metadata.
 	//
 	// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderConfig
 	wellKnown := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
 	resp, err := client.Get(wellKnown)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
 		// Don't produce an error that's too huge (e.g. if we get HTML back for some reason).
@@ -311,23 +392,17 @@ func tokenEndpoint(client *http.Client, issuer string) (string, error) {
 		if len(body) > n {
 			body = append(body[:n], []byte("...")...)
 		}
-		return "", fmt.Errorf("oidc: failed to query metadata endpoint %s: %q", resp.Status, body)
+		return nil, fmt.Errorf("oidc: failed to query metadata endpoint %s: %q", resp.Status, body)
 	}
 
-	// Metadata object. We only care about the token_endpoint, the thing endpoint
-	// we'll be refreshing against.
-	//
-	// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
-	var metadata struct {
-		TokenURL string `json:"token_endpoint"`
-	}
+	var metadata providerMetadata
 	if err := json.Unmarshal(body, &metadata); err != nil {
-		return "", fmt.Errorf("oidc: failed to decode provider discovery object: %v", err)
+		return nil, fmt.Errorf("oidc: failed to decode provider discovery object: %v", err)
 	}
 	if metadata.TokenURL == "" {
-		return "", fmt.Errorf("oidc: discovery object doesn't contain a token_endpoint")
+		return nil, fmt.Errorf("oidc: discovery object doesn't contain a token_endpoint")
 	}
-	return metadata.TokenURL, nil
+	return &metadata, nil
 }
 
 func idTokenExpired(now func() time.Time, idToken string) (bool, error) {
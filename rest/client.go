@@ -104,6 +104,14 @@ type RESTClient struct {
 	// If not set, defaultWarningHandler is used.
 	warningHandler WarningHandlerWithContext
 
+	// hedging and adaptiveTimeout are only in effect if latencies is set.
+	hedging         HedgingConfig
+	adaptiveTimeout AdaptiveTimeoutConfig
+	// latencies tracks the latencies of the requests created by this client.
+	latencies *latencyTracker
+	// telemetry is nil unless OpenTelemetry tracing or metrics are enabled.
+	telemetry *requestTelemetry
+
 	// Set specific behavior of the client.  If not set http.DefaultClient will be used.
 	Client *http.Client
 }
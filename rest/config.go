@@ -126,7 +126,10 @@ type Config struct {
 	// If it's zero, the created RESTClient will use DefaultBurst: 10.
 	Burst int
 
-	// Rate limiter for limiting connections to the master from this client. If present overwrites QPS/Burst
+	// Rate limiter for limiting connections to the master from this client. If present overwrites QPS/Burst.
+	// If it is a flowcontrol.AdaptiveRateLimiter, for example one created by
+	// flowcontrol.NewAdaptiveRateLimiter, it receives the priority and fairness
+	// feedback of the server for each response.
 	RateLimiter flowcontrol.RateLimiter
 
 	// WarningHandler handles warnings in server responses.
@@ -150,6 +153,27 @@ type Config struct {
 	// The maximum length of time to wait before giving up on a server request. A value of zero means no timeout.
 	Timeout time.Duration
 
+	// Hedging configures hedged requests for gets. It is disabled by default.
+	Hedging HedgingConfig
+
+	// AdaptiveTimeout configures per-verb and per-resource timeouts derived from
+	// observed latencies.
+	// It is disabled by default.
+	AdaptiveTimeout AdaptiveTimeoutConfig
+
+	// CircuitBreaker configures a circuit breaker which fails requests fast
+	// while the server or an aggregated API is unavailable. It is disabled
+	// by default.
+	CircuitBreaker transport.CircuitBreakerConfig
+
+	// Audit configures a client-side audit log, which records every request
+	// of the client with its outcome. It is disabled by default.
+	Audit transport.AuditConfig
+
+	// Telemetry configures OpenTelemetry tracing and metrics of requests. It
+	// is disabled by default.
+	Telemetry TelemetryConfig
+
 	// Dial specifies the dial function for creating unencrypted TCP connections.
 	Dial func(ctx context.Context, network, address string) (net.Conn, error)
 
@@ -385,6 +409,10 @@ func RESTClientForConfigAndClient(config *Config, httpClient *http.Client) (*RES
 		}
 	}
 
+	if err := validateLatencyConfig(config.Hedging, config.AdaptiveTimeout); err != nil {
+		return nil, err
+	}
+
 	var gv schema.GroupVersion
 	if config.GroupVersion != nil {
 		gv = *config.GroupVersion
@@ -398,6 +426,8 @@ func RESTClientForConfigAndClient(config *Config, httpClient *http.Client) (*RES
 
 	restClient, err := NewRESTClient(baseURL, versionedAPIPath, clientContent, rateLimiter, httpClient)
 	maybeSetWarningHandler(restClient, config.WarningHandler, config.WarningHandlerWithContext)
+	maybeSetLatencyTracking(restClient, config.Hedging, config.AdaptiveTimeout)
+	maybeSetTelemetry(restClient, config.Telemetry)
 	return restClient, err
 }
 
@@ -467,6 +497,10 @@ func UnversionedRESTClientForConfigAndClient(config *Config, httpClient *http.Cl
 		}
 	}
 
+	if err := validateLatencyConfig(config.Hedging, config.AdaptiveTimeout); err != nil {
+		return nil, err
+	}
+
 	gv := metav1.SchemeGroupVersion
 	if config.GroupVersion != nil {
 		gv = *config.GroupVersion
@@ -480,6 +514,8 @@ func UnversionedRESTClientForConfigAndClient(config *Config, httpClient *http.Cl
 
 	restClient, err := NewRESTClient(baseURL, versionedAPIPath, clientContent, rateLimiter, httpClient)
 	maybeSetWarningHandler(restClient, config.WarningHandler, config.WarningHandlerWithContext)
+	maybeSetLatencyTracking(restClient, config.Hedging, config.AdaptiveTimeout)
+	maybeSetTelemetry(restClient, config.Telemetry)
 	return restClient, err
 }
 
@@ -654,6 +690,11 @@ func AnonymousClientConfig(config *Config) *Config {
 		QPS:                       config.QPS,
 		Burst:                     config.Burst,
 		Timeout:                   config.Timeout,
+		Hedging:                   config.Hedging,
+		AdaptiveTimeout:           config.AdaptiveTimeout,
+		CircuitBreaker:            config.CircuitBreaker,
+		Audit:                     config.Audit,
+		Telemetry:                 config.Telemetry,
 		Dial:                      config.Dial,
 		Proxy:                     config.Proxy,
 	}
@@ -699,6 +740,11 @@ func CopyConfig(config *Config) *Config {
 		WarningHandler:            config.WarningHandler,
 		WarningHandlerWithContext: config.WarningHandlerWithContext,
 		Timeout:                   config.Timeout,
+		Hedging:                   config.Hedging,
+		AdaptiveTimeout:           config.AdaptiveTimeout,
+		CircuitBreaker:            config.CircuitBreaker,
+		Audit:                     config.Audit,
+		Telemetry:                 config.Telemetry,
 		Dial:                      config.Dial,
 		Proxy:                     config.Proxy,
 	}
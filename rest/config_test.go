@@ -41,6 +41,11 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
 	"sigs.k8s.io/randfill"
 )
 
@@ -312,6 +317,16 @@ func (fakeAuthProviderConfigPersister) Persist(map[string]string) error {
 
 var fakeAuthProviderConfigPersisterError = errors.New("fakeAuthProviderConfigPersisterError")
 
+type fakeAuditSink struct{}
+
+func (fakeAuditSink) Audit(context.Context, *transport.AuditEvent) error {
+	return nil
+}
+
+func fakeAuditBodyRedactor(*transport.AuditEvent, []byte) []byte {
+	return []byte("fakeAuditBodyRedactor")
+}
+
 func TestAnonymousAuthConfig(t *testing.T) {
 	f := randfill.New().NilChance(0.0).NumElements(1, 1)
 	f.Funcs(
@@ -358,6 +373,21 @@ func TestAnonymousAuthConfig(t *testing.T) {
 		func(r *func(*http.Request) (*url.URL, error), f randfill.Continue) {
 			*r = fakeProxyFunc
 		},
+		func(r *transport.AuditSink, f randfill.Continue) {
+			*r = fakeAuditSink{}
+		},
+		func(r *func(*transport.AuditEvent, []byte) []byte, f randfill.Continue) {
+			*r = fakeAuditBodyRedactor
+		},
+		func(r *trace.TracerProvider, f randfill.Continue) {
+			*r = tracenoop.NewTracerProvider()
+		},
+		func(r *propagation.TextMapPropagator, f randfill.Continue) {
+			*r = propagation.TraceContext{}
+		},
+		func(r *metric.MeterProvider, f randfill.Continue) {
+			*r = metricnoop.NewMeterProvider()
+		},
 		func(r *runtime.Object, f randfill.Continue) {
 			unknown := &runtime.Unknown{}
 			f.Fill(unknown)
@@ -407,6 +437,12 @@ func TestAnonymousAuthConfig(t *testing.T) {
 		actual.Proxy = nil
 		expected.Proxy = nil
 
+		if actual.Audit.RedactBody == nil || string(actual.Audit.RedactBody(nil, nil)) != "fakeAuditBodyRedactor" {
+			t.Fatalf("AnonymousClientConfig dropped the Audit.RedactBody field")
+		}
+		actual.Audit.RedactBody = nil
+		expected.Audit.RedactBody = nil
+
 		if diff := cmp.Diff(*actual, expected); diff != "" {
 			t.Fatalf("AnonymousClientConfig dropped unexpected fields, identify whether they are security related or not (-got, +want): %s", diff)
 		}
@@ -457,6 +493,21 @@ func TestCopyConfig(t *testing.T) {
 		func(r *func(*http.Request) (*url.URL, error), f randfill.Continue) {
 			*r = fakeProxyFunc
 		},
+		func(r *transport.AuditSink, f randfill.Continue) {
+			*r = fakeAuditSink{}
+		},
+		func(r *func(*transport.AuditEvent, []byte) []byte, f randfill.Continue) {
+			*r = fakeAuditBodyRedactor
+		},
+		func(r *trace.TracerProvider, f randfill.Continue) {
+			*r = tracenoop.NewTracerProvider()
+		},
+		func(r *propagation.TextMapPropagator, f randfill.Continue) {
+			*r = propagation.TraceContext{}
+		},
+		func(r *metric.MeterProvider, f randfill.Continue) {
+			*r = metricnoop.NewMeterProvider()
+		},
 		func(r *runtime.Object, f randfill.Continue) {
 			unknown := &runtime.Unknown{}
 			f.Fill(unknown)
@@ -510,6 +561,12 @@ func TestCopyConfig(t *testing.T) {
 		actual.Proxy = nil
 		expected.Proxy = nil
 
+		if actual.Audit.RedactBody == nil || string(actual.Audit.RedactBody(nil, nil)) != "fakeAuditBodyRedactor" {
+			t.Fatalf("CopyConfig dropped the Audit.RedactBody field")
+		}
+		actual.Audit.RedactBody = nil
+		expected.Audit.RedactBody = nil
+
 		if diff := cmp.Diff(*actual, expected); diff != "" {
 			t.Fatalf("CopyConfig  dropped unexpected fields, identify whether they are security related or not (-got, +want): %s", diff)
 		}
@@ -652,7 +709,7 @@ func TestConfigSprint(t *testing.T) {
 		Proxy:                     fakeProxyFunc,
 	}
 	want := fmt.Sprintf(
-		`&rest.Config{Host:"localhost:8080", APIPath:"v1", ContentConfig:rest.ContentConfig{AcceptContentTypes:"application/json", ContentType:"application/json", GroupVersion:(*schema.GroupVersion)(nil), NegotiatedSerializer:runtime.NegotiatedSerializer(nil)}, Username:"gopher", Password:"--- REDACTED ---", BearerToken:"--- REDACTED ---", BearerTokenFile:"", Impersonate:rest.ImpersonationConfig{UserName:"gopher2", UID:"uid123", Groups:[]string(nil), Extra:map[string][]string(nil)}, AuthProvider:api.AuthProviderConfig{Name: "gopher", Config: map[string]string{--- REDACTED ---}}, AuthConfigPersister:rest.AuthProviderConfigPersister(--- REDACTED ---), ExecProvider:api.ExecConfig{Command: "sudo", Args: []string{"--- REDACTED ---"}, Env: []ExecEnvVar{--- REDACTED ---}, APIVersion: "", ProvideClusterInfo: true, Config: runtime.Object(--- REDACTED ---), StdinUnavailable: false}, TLSClientConfig:rest.sanitizedTLSClientConfig{Insecure:false, ServerName:"", CertFile:"a.crt", KeyFile:"a.key", CAFile:"", CertData:[]uint8{0x2d, 0x2d, 0x2d, 0x20, 0x54, 0x52, 0x55, 0x4e, 0x43, 0x41, 0x54, 0x45, 0x44, 0x20, 0x2d, 0x2d, 0x2d}, KeyData:[]uint8{0x2d, 0x2d, 0x2d, 0x20, 0x52, 0x45, 0x44, 0x41, 0x43, 0x54, 0x45, 0x44, 0x20, 0x2d, 0x2d, 0x2d}, CAData:[]uint8(nil), NextProtos:[]string{"h2", "http/1.1"}}, UserAgent:"gobot", DisableCompression:false, Transport:(*rest.fakeRoundTripper)(%p), WrapTransport:(transport.WrapperFunc)(%p), QPS:1, Burst:2, RateLimiter:(*rest.fakeLimiter)(%p), WarningHandler:rest.fakeWarningHandler{}, WarningHandlerWithContext:rest.fakeWarningHandlerWithContext{}, Timeout:3000000000, Dial:(func(context.Context, string, string) (net.Conn, error))(%p), Proxy:(func(*http.Request) (*url.URL, error))(%p)}`,
+		`&rest.Config{Host:"localhost:8080", APIPath:"v1", ContentConfig:rest.ContentConfig{AcceptContentTypes:"application/json", ContentType:"application/json", GroupVersion:(*schema.GroupVersion)(nil), NegotiatedSerializer:runtime.NegotiatedSerializer(nil)}, Username:"gopher", Password:"--- REDACTED ---", BearerToken:"--- REDACTED ---", BearerTokenFile:"", Impersonate:rest.ImpersonationConfig{UserName:"gopher2", UID:"uid123", Groups:[]string(nil), Extra:map[string][]string(nil)}, AuthProvider:api.AuthProviderConfig{Name: "gopher", Config: map[string]string{--- REDACTED ---}}, AuthConfigPersister:rest.AuthProviderConfigPersister(--- REDACTED ---), ExecProvider:api.ExecConfig{Command: "sudo", Args: []string{"--- REDACTED ---"}, Env: []ExecEnvVar{--- REDACTED ---}, APIVersion: "", ProvideClusterInfo: true, Config: runtime.Object(--- REDACTED ---), CacheCredentials: false, StdinUnavailable: false}, TLSClientConfig:rest.sanitizedTLSClientConfig{Insecure:false, ServerName:"", CertFile:"a.crt", KeyFile:"a.key", CAFile:"", CertData:[]uint8{0x2d, 0x2d, 0x2d, 0x20, 0x54, 0x52, 0x55, 0x4e, 0x43, 0x41, 0x54, 0x45, 0x44, 0x20, 0x2d, 0x2d, 0x2d}, KeyData:[]uint8{0x2d, 0x2d, 0x2d, 0x20, 0x52, 0x45, 0x44, 0x41, 0x43, 0x54, 0x45, 0x44, 0x20, 0x2d, 0x2d, 0x2d}, CAData:[]uint8(nil), NextProtos:[]string{"h2", "http/1.1"}}, UserAgent:"gobot", DisableCompression:false, Transport:(*rest.fakeRoundTripper)(%p), WrapTransport:(transport.WrapperFunc)(%p), QPS:1, Burst:2, RateLimiter:(*rest.fakeLimiter)(%p), WarningHandler:rest.fakeWarningHandler{}, WarningHandlerWithContext:rest.fakeWarningHandlerWithContext{}, Timeout:3000000000, Hedging:rest.HedgingConfig{Percentile:0, MinDelay:0, MaxHedgedRequests:0}, AdaptiveTimeout:rest.AdaptiveTimeoutConfig{Percentile:0, Multiplier:0, MinTimeout:0, MaxTimeout:0}, CircuitBreaker:transport.CircuitBreakerConfig{FailureThreshold:0, OpenDuration:0, HalfOpenMaxRequests:0}, Audit:transport.AuditConfig{Sink:transport.AuditSink(nil), IncludeRequestBody:false, MaxBodyBytes:0, RedactBody:(func(*transport.AuditEvent, []uint8) []uint8)(nil)}, Telemetry:rest.TelemetryConfig{TracerProvider:trace.TracerProvider(nil), Propagator:propagation.TextMapPropagator(nil), MeterProvider:metric.MeterProvider(nil)}, Dial:(func(context.Context, string, string) (net.Conn, error))(%p), Proxy:(func(*http.Request) (*url.URL, error))(%p)}`,
 		c.Transport, fakeWrapperFunc, c.RateLimiter, fakeDialFunc, fakeProxyFunc,
 	)
 
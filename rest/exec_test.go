@@ -26,6 +26,11 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientauthenticationapi "k8s.io/client-go/pkg/apis/clientauthentication"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
@@ -255,6 +260,21 @@ func TestConfigToExecClusterRoundtrip(t *testing.T) {
 		func(r *func(*http.Request) (*url.URL, error), f randfill.Continue) {
 			*r = fakeProxyFunc
 		},
+		func(r *transport.AuditSink, f randfill.Continue) {
+			*r = fakeAuditSink{}
+		},
+		func(r *func(*transport.AuditEvent, []byte) []byte, f randfill.Continue) {
+			*r = fakeAuditBodyRedactor
+		},
+		func(r *trace.TracerProvider, f randfill.Continue) {
+			*r = tracenoop.NewTracerProvider()
+		},
+		func(r *propagation.TextMapPropagator, f randfill.Continue) {
+			*r = propagation.TraceContext{}
+		},
+		func(r *metric.MeterProvider, f randfill.Continue) {
+			*r = metricnoop.NewMeterProvider()
+		},
 		func(r *runtime.Object, f randfill.Continue) {
 			unknown := &runtime.Unknown{}
 			f.Fill(unknown)
@@ -293,6 +313,11 @@ func TestConfigToExecClusterRoundtrip(t *testing.T) {
 		expected.WarningHandler = nil
 		expected.WarningHandlerWithContext = nil
 		expected.Timeout = 0
+		expected.Hedging = HedgingConfig{}
+		expected.AdaptiveTimeout = AdaptiveTimeoutConfig{}
+		expected.CircuitBreaker = transport.CircuitBreakerConfig{}
+		expected.Audit = transport.AuditConfig{}
+		expected.Telemetry = TelemetryConfig{}
 		expected.Dial = nil
 
 		// Manually set URLs so we don't get an error when parsing these during the roundtrip.
@@ -0,0 +1,359 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/metrics"
+)
+
+const (
+	// latencyWindowSize is the number of latencies per verb and resource
+	// which are kept to compute percentiles.
+	latencyWindowSize = 200
+	// minLatencySamples is the number of latencies which must have been
+	// observed for a verb and resource before hedging or adaptive timeouts kick in.
+	minLatencySamples = 20
+
+	defaultAdaptiveTimeoutMultiplier = 2
+	defaultAdaptiveTimeoutMin        = time.Second
+)
+
+// HedgingConfig configures hedged requests. When a get request of a single
+// object or a non-resource URL did not receive a response within the given
+// percentile of the latencies observed for its resource, a duplicate of the
+// request is sent and the first response wins. The other requests are
+// canceled. List and watch requests are never hedged.
+type HedgingConfig struct {
+	// Percentile of the observed latencies after which a hedged request is
+	// sent, between 0 and 1, for example 0.95. Zero disables hedging.
+	Percentile float64
+	// MinDelay is the minimum time to wait before a hedged request is sent.
+	MinDelay time.Duration
+	// MaxHedgedRequests is the maximum number of hedged requests sent for
+	// a single request. If zero, one hedged request is sent at most.
+	MaxHedgedRequests int
+}
+
+// AdaptiveTimeoutConfig configures timeouts which adapt to the latencies
+// observed for each verb and resource, so that for example the gets and lists
+// of pods have different timeouts. A request times out after the given percentile of
+// the observed latencies multiplied by Multiplier, within the bounds of
+// MinTimeout and MaxTimeout. Config.Timeout and Request.Timeout take
+// precedence if they are shorter or set explicitly, respectively.
+type AdaptiveTimeoutConfig struct {
+	// Percentile of the observed latencies which the timeout is derived
+	// from, between 0 and 1, for example 0.99. Zero disables adaptive timeouts.
+	Percentile float64
+	// Multiplier is applied to the latency at Percentile. If zero, 2 is used.
+	Multiplier float64
+	// MinTimeout is the lower bound of the timeout. If zero, 1s is used.
+	MinTimeout time.Duration
+	// MaxTimeout is the upper bound of the timeout. Zero means no upper bound.
+	MaxTimeout time.Duration
+}
+
+func validateLatencyConfig(hedging HedgingConfig, adaptiveTimeout AdaptiveTimeoutConfig) error {
+	if hedging.Percentile < 0 || hedging.Percentile >= 1 {
+		return fmt.Errorf("hedging percentile must be between 0 and 1, got %v", hedging.Percentile)
+	}
+	if hedging.MaxHedgedRequests < 0 {
+		return fmt.Errorf("the maximum number of hedged requests must not be negative, got %d", hedging.MaxHedgedRequests)
+	}
+	if adaptiveTimeout.Percentile < 0 || adaptiveTimeout.Percentile >= 1 {
+		return fmt.Errorf("adaptive timeout percentile must be between 0 and 1, got %v", adaptiveTimeout.Percentile)
+	}
+	if adaptiveTimeout.Multiplier < 0 {
+		return fmt.Errorf("adaptive timeout multiplier must not be negative, got %v", adaptiveTimeout.Multiplier)
+	}
+	if adaptiveTimeout.MaxTimeout > 0 && adaptiveTimeout.MaxTimeout < adaptiveTimeout.MinTimeout {
+		return fmt.Errorf("adaptive timeout maximum %v is less than the minimum %v", adaptiveTimeout.MaxTimeout, adaptiveTimeout.MinTimeout)
+	}
+	return nil
+}
+
+// maybeSetLatencyTracking enables hedging and adaptive timeouts if configured.
+//
+// May be called for a nil client.
+func maybeSetLatencyTracking(c *RESTClient, hedging HedgingConfig, adaptiveTimeout AdaptiveTimeoutConfig) {
+	if c == nil || (hedging.Percentile == 0 && adaptiveTimeout.Percentile == 0) {
+		return
+	}
+	if hedging.MaxHedgedRequests == 0 {
+		hedging.MaxHedgedRequests = 1
+	}
+	if adaptiveTimeout.Multiplier == 0 {
+		adaptiveTimeout.Multiplier = defaultAdaptiveTimeoutMultiplier
+	}
+	if adaptiveTimeout.MinTimeout == 0 {
+		adaptiveTimeout.MinTimeout = defaultAdaptiveTimeoutMin
+	}
+	c.hedging = hedging
+	c.adaptiveTimeout = adaptiveTimeout
+	c.latencies = newLatencyTracker()
+}
+
+// latencyTracker keeps the most recent latencies of requests per verb and
+// resource.
+type latencyTracker struct {
+	lock    sync.Mutex
+	windows map[latencyKey]*latencyWindow
+}
+
+// latencyKey identifies the requests which share their latencies. The verb is
+// the Kubernetes verb, so that gets and lists of a resource are kept apart.
+type latencyKey struct {
+	verb        string
+	group       string
+	resource    string
+	subresource string
+}
+
+type latencyWindow struct {
+	samples []time.Duration
+	// next is the index in samples which is overwritten next once the
+	// window is full.
+	next int
+}
+
+func newLatencyTracker() *latencyTracker {
+	return &latencyTracker{windows: map[latencyKey]*latencyWindow{}}
+}
+
+func (t *latencyTracker) observe(key latencyKey, latency time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	w, ok := t.windows[key]
+	if !ok {
+		w = &latencyWindow{samples: make([]time.Duration, 0, latencyWindowSize)}
+		t.windows[key] = w
+	}
+	if len(w.samples) < latencyWindowSize {
+		w.samples = append(w.samples, latency)
+		return
+	}
+	w.samples[w.next] = latency
+	w.next = (w.next + 1) % latencyWindowSize
+}
+
+// percentile returns the latency at the given percentile of the key. It
+// returns false if not enough latencies have been observed.
+func (t *latencyTracker) percentile(key latencyKey, p float64) (time.Duration, bool) {
+	t.lock.Lock()
+	w, ok := t.windows[key]
+	if !ok || len(w.samples) < minLatencySamples {
+		t.lock.Unlock()
+		return 0, false
+	}
+	samples := slices.Clone(w.samples)
+	t.lock.Unlock()
+
+	slices.Sort(samples)
+	i := int(math.Ceil(p*float64(len(samples)))) - 1
+	i = max(0, min(i, len(samples)-1))
+	return samples[i], true
+}
+
+// adaptiveTimeout returns the timeout of a single attempt of the request, or
+// false if the request has no adaptive timeout.
+func (r *Request) adaptiveTimeout() (time.Duration, bool) {
+	config := r.c.adaptiveTimeout
+	if r.c.latencies == nil || config.Percentile == 0 || r.timeoutSet {
+		return 0, false
+	}
+	latency, ok := r.c.latencies.percentile(r.latencyKey(), config.Percentile)
+	if !ok {
+		return 0, false
+	}
+	timeout := max(time.Duration(float64(latency)*config.Multiplier), config.MinTimeout)
+	if config.MaxTimeout > 0 {
+		timeout = min(timeout, config.MaxTimeout)
+	}
+	if r.timeout > 0 && r.timeout <= timeout {
+		return 0, false
+	}
+	return timeout, true
+}
+
+// hedgingDelay returns the time after which a hedged request is sent, or
+// false if the request must not be hedged.
+func (r *Request) hedgingDelay() (time.Duration, bool) {
+	config := r.c.hedging
+	if r.c.latencies == nil || config.Percentile == 0 {
+		return 0, false
+	}
+	// Only gets whose body can be replayed are hedged. Lists and watches
+	// are idempotent as well, but duplicating them is expensive for the
+	// server and their latencies vary with the size of the collection.
+	key := r.latencyKey()
+	if key.verb != "get" || r.body != nil {
+		return 0, false
+	}
+	latency, ok := r.c.latencies.percentile(key, config.Percentile)
+	if !ok {
+		return 0, false
+	}
+	return max(latency, config.MinDelay), true
+}
+
+// observeLatency records the latency of an attempt of the request which
+// received a response or ran into its adaptive timeout.
+func (r *Request) observeLatency(latency time.Duration) {
+	if r.c.latencies != nil {
+		r.c.latencies.observe(r.latencyKey(), latency)
+	}
+}
+
+// latencyKey returns the key of the latencies of the request.
+func (r *Request) latencyKey() latencyKey {
+	return latencyKey{
+		verb:        r.kubernetesVerb(),
+		group:       r.contentConfig.GroupVersion.Group,
+		resource:    r.resource,
+		subresource: r.subresource,
+	}
+}
+
+// roundTrip sends the request and returns the request which the response
+// belongs to. If the request is hedged, that is not req itself but a copy of it.
+func (r *Request) roundTrip(client *http.Client, req *http.Request) (*http.Request, *http.Response, error) {
+	delay, ok := r.hedgingDelay()
+	if !ok {
+		resp, err := client.Do(req)
+		return req, resp, err
+	}
+	return r.hedgedRoundTrip(client, req, delay)
+}
+
+type hedgedResponse struct {
+	index int
+	req   *http.Request
+	resp  *http.Response
+	err   error
+}
+
+// hedgedRoundTrip sends req and, each time no response arrived within delay,
+// a copy of it, up to the configured maximum. The first response wins, all
+// other requests are canceled. Errors are only returned once no request is
+// in flight anymore.
+func (r *Request) hedgedRoundTrip(client *http.Client, req *http.Request, delay time.Duration) (*http.Request, *http.Response, error) {
+	ctx := req.Context()
+	maxRequests := 1 + r.c.hedging.MaxHedgedRequests
+	responses := make(chan hedgedResponse, maxRequests)
+	var cancels []context.CancelFunc
+	send := func() error {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		attempt := req.Clone(attemptCtx)
+		if len(cancels) > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				cancel()
+				return err
+			}
+			attempt.Body = body
+		}
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			resp, err := client.Do(attempt)
+			responses <- hedgedResponse{index: index, req: attempt, resp: resp, err: err}
+		}()
+		return nil
+	}
+
+	if err := send(); err != nil {
+		return req, nil, err
+	}
+	inFlight := 1
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			// Hedged requests are not worth waiting for the rate limiter.
+			if r.rateLimiter == nil || r.rateLimiter.TryAccept() {
+				if err := send(); err == nil {
+					inFlight++
+				}
+			}
+			if len(cancels) < maxRequests {
+				timer.Reset(delay)
+			}
+		case res := <-responses:
+			inFlight--
+			if res.err != nil && inFlight > 0 {
+				cancels[res.index]()
+				continue
+			}
+			r.finishHedgedRoundTrip(ctx, res.index, len(cancels)-1, cancels, responses, inFlight)
+			if res.err != nil {
+				cancels[res.index]()
+				return res.req, nil, res.err
+			}
+			res.resp.Body = &cancelOnCloseBody{ReadCloser: res.resp.Body, cancel: cancels[res.index]}
+			return res.req, res.resp, nil
+		}
+	}
+}
+
+// finishHedgedRoundTrip cancels all requests except the winner, releases
+// the responses which are still in flight and records the outcome of the
+// hedged requests.
+func (r *Request) finishHedgedRoundTrip(ctx context.Context, winner, hedges int, cancels []context.CancelFunc, responses <-chan hedgedResponse, inFlight int) {
+	for i, cancel := range cancels {
+		if i != winner {
+			cancel()
+		}
+	}
+	if inFlight > 0 {
+		go func() {
+			for range inFlight {
+				readAndCloseResponseBody((<-responses).resp)
+			}
+		}()
+	}
+	host := r.URL().Host
+	for i := 1; i <= hedges; i++ {
+		result := "lost"
+		if i == winner {
+			result = "won"
+		}
+		metrics.RequestHedges.Increment(ctx, result, r.verb, host)
+	}
+}
+
+// cancelOnCloseBody cancels the context of a request once its response body
+// is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/metrics"
+)
+
+func TestLatencyTrackerPercentile(t *testing.T) {
+	get := latencyKey{verb: "get", resource: "pods"}
+	tracker := newLatencyTracker()
+	for i := 1; i < minLatencySamples; i++ {
+		tracker.observe(get, time.Duration(i)*time.Millisecond)
+	}
+	if _, ok := tracker.percentile(get, 0.5); ok {
+		t.Errorf("expected no percentile before %d latencies were observed", minLatencySamples)
+	}
+	for i := minLatencySamples; i <= 100; i++ {
+		tracker.observe(get, time.Duration(i)*time.Millisecond)
+	}
+	if latency, ok := tracker.percentile(get, 0.95); !ok || latency != 95*time.Millisecond {
+		t.Errorf("expected a p95 latency of 95ms, got %v, %v", latency, ok)
+	}
+	for _, key := range []latencyKey{
+		{verb: "list", resource: "pods"},
+		{verb: "get", resource: "nodes"},
+		{verb: "get", resource: "pods", subresource: "status"},
+	} {
+		if _, ok := tracker.percentile(key, 0.95); ok {
+			t.Errorf("expected latencies to be tracked per verb and resource, got a percentile for %+v", key)
+		}
+	}
+
+	// Only the most recent latencies are kept.
+	for i := 0; i < latencyWindowSize; i++ {
+		tracker.observe(get, time.Second)
+	}
+	if latency, ok := tracker.percentile(get, 0.5); !ok || latency != time.Second {
+		t.Errorf("expected a p50 latency of 1s, got %v, %v", latency, ok)
+	}
+}
+
+func TestValidateLatencyConfig(t *testing.T) {
+	testCases := []struct {
+		name            string
+		hedging         HedgingConfig
+		adaptiveTimeout AdaptiveTimeoutConfig
+		expectErr       bool
+	}{
+		{name: "disabled"},
+		{name: "valid", hedging: HedgingConfig{Percentile: 0.95}, adaptiveTimeout: AdaptiveTimeoutConfig{Percentile: 0.99, MaxTimeout: time.Minute}},
+		{name: "hedging percentile too large", hedging: HedgingConfig{Percentile: 1}, expectErr: true},
+		{name: "negative hedged requests", hedging: HedgingConfig{Percentile: 0.9, MaxHedgedRequests: -1}, expectErr: true},
+		{name: "negative timeout percentile", adaptiveTimeout: AdaptiveTimeoutConfig{Percentile: -0.5}, expectErr: true},
+		{name: "maximum below minimum", adaptiveTimeout: AdaptiveTimeoutConfig{Percentile: 0.9, MinTimeout: time.Minute, MaxTimeout: time.Second}, expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := UnversionedRESTClientForConfigAndClient(&Config{
+				Host:            "localhost",
+				ContentConfig:   ContentConfig{NegotiatedSerializer: serializer.NewCodecFactory(runtime.NewScheme())},
+				Hedging:         tc.hedging,
+				AdaptiveTimeout: tc.adaptiveTimeout,
+			}, http.DefaultClient)
+			if tc.expectErr != (err != nil) {
+				t.Errorf("expected error: %v, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
+
+// newLatencyTestClient returns a client whose latencies are primed with
+// minLatencySamples observations of latency for the gets and creates of
+// non-resource URLs and the lists of pods.
+func newLatencyTestClient(t *testing.T, server *httptest.Server, hedging HedgingConfig, adaptiveTimeout AdaptiveTimeoutConfig, latency time.Duration) *RESTClient {
+	t.Helper()
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewRESTClient(u, "", ClientContentConfig{}, nil, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	maybeSetLatencyTracking(c, hedging, adaptiveTimeout)
+	for _, key := range []latencyKey{{verb: "get"}, {verb: "create"}, {verb: "list", resource: "pods"}} {
+		for i := 0; i < minLatencySamples; i++ {
+			c.latencies.observe(key, latency)
+		}
+	}
+	return c
+}
+
+type fakeHedgeMetric struct {
+	lock    sync.Mutex
+	results []string
+}
+
+func (m *fakeHedgeMetric) Increment(ctx context.Context, result, method, host string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.results = append(m.results, method+" "+result)
+}
+
+type fakeTimeoutMetric struct {
+	lock     sync.Mutex
+	timeouts []time.Duration
+}
+
+func (m *fakeTimeoutMetric) Observe(ctx context.Context, method, host string, timeout time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.timeouts = append(m.timeouts, timeout)
+}
+
+func TestHedgedRequest(t *testing.T) {
+	hedgeMetric := &fakeHedgeMetric{}
+	oldRequestHedges := metrics.RequestHedges
+	metrics.RequestHedges = hedgeMetric
+	defer func() {
+		metrics.RequestHedges = oldRequestHedges
+	}()
+
+	var requests atomic.Int32
+	firstCanceled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if requests.Add(1) == 1 {
+			// The first request hangs until it gets canceled.
+			select {
+			case <-req.Context().Done():
+				close(firstCanceled)
+			case <-time.After(wait.ForeverTestTimeout):
+			}
+			return
+		}
+		_, _ = w.Write([]byte(req.Method))
+	}))
+	defer server.Close()
+
+	c := newLatencyTestClient(t, server, HedgingConfig{Percentile: 0.9}, AdaptiveTimeoutConfig{}, 10*time.Millisecond)
+
+	body, err := c.Get().AbsPath("/hedged").DoRaw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "GET" {
+		t.Errorf("unexpected body %q", body)
+	}
+	select {
+	case <-firstCanceled:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatalf("the losing request was not canceled")
+	}
+	if e, a := int32(2), requests.Load(); e != a {
+		t.Errorf("expected %d requests, got %d", e, a)
+	}
+	hedgeMetric.lock.Lock()
+	defer hedgeMetric.lock.Unlock()
+	if len(hedgeMetric.results) != 1 || hedgeMetric.results[0] != "GET won" {
+		t.Errorf("unexpected hedge metrics %v", hedgeMetric.results)
+	}
+}
+
+func TestHedgingSkipsNonIdempotentVerbs(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	c := newLatencyTestClient(t, server, HedgingConfig{Percentile: 0.9}, AdaptiveTimeoutConfig{}, time.Millisecond)
+
+	if _, err := c.Post().AbsPath("/create").Body([]byte("{}")).DoRaw(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if e, a := int32(1), requests.Load(); e != a {
+		t.Errorf("expected %d requests, got %d", e, a)
+	}
+}
+
+func TestHedgingSkipsListsAndWatches(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	c := newLatencyTestClient(t, server, HedgingConfig{Percentile: 0.9}, AdaptiveTimeoutConfig{}, time.Millisecond)
+
+	if _, err := c.Get().Resource("pods").DoRaw(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get().Resource("pods").Param("watch", "true").DoRaw(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if e, a := int32(2), requests.Load(); e != a {
+		t.Errorf("expected %d requests, got %d", e, a)
+	}
+}
+
+func TestAdaptiveTimeout(t *testing.T) {
+	timeoutMetric := &fakeTimeoutMetric{}
+	oldRequestAdaptiveTimeout := metrics.RequestAdaptiveTimeout
+	metrics.RequestAdaptiveTimeout = timeoutMetric
+	defer func() {
+		metrics.RequestAdaptiveTimeout = oldRequestAdaptiveTimeout
+	}()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-req.Context().Done():
+		case <-time.After(500 * time.Millisecond):
+		}
+	}))
+	defer server.Close()
+
+	c := newLatencyTestClient(t, server, HedgingConfig{}, AdaptiveTimeoutConfig{Percentile: 0.99, MinTimeout: 50 * time.Millisecond}, 10*time.Millisecond)
+
+	start := time.Now()
+	_, err := c.Get().AbsPath("/slow").DoRaw(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the adaptive timeout to expire, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 500*time.Millisecond {
+		t.Errorf("expected the request to time out early, took %v", elapsed)
+	}
+	timeoutMetric.lock.Lock()
+	if len(timeoutMetric.timeouts) != 1 || timeoutMetric.timeouts[0] != 50*time.Millisecond {
+		t.Errorf("unexpected timeout metrics %v", timeoutMetric.timeouts)
+	}
+	timeoutMetric.lock.Unlock()
+
+	// An explicit timeout takes precedence.
+	if _, err := c.Get().AbsPath("/slow").Timeout(5 * time.Second).DoRaw(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
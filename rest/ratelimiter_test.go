@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+type fakeAdaptiveRateLimiter struct {
+	flowcontrol.RateLimiter
+
+	lock     sync.Mutex
+	waits    []string
+	feedback map[string][]flowcontrol.RequestFeedback
+}
+
+func (l *fakeAdaptiveRateLimiter) WaitFor(ctx context.Context, key string) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.waits = append(l.waits, key)
+	return nil
+}
+
+func (l *fakeAdaptiveRateLimiter) Observe(key string, feedback flowcontrol.RequestFeedback) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.feedback[key] = append(l.feedback[key], feedback)
+}
+
+func TestAdaptiveRateLimiterFeedback(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		w.Header().Set("X-Kubernetes-PF-PriorityLevel-UID", "workload-low")
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limiter := &fakeAdaptiveRateLimiter{
+		RateLimiter: flowcontrol.NewFakeAlwaysRateLimiter(),
+		feedback:    map[string][]flowcontrol.RequestFeedback{},
+	}
+	c, err := NewRESTClient(u, "", ClientContentConfig{}, limiter, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Get().Resource("pods").SubResource("status").Name("foo").DoRaw(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	const key = "GET pods/status"
+	if len(limiter.waits) != 2 || limiter.waits[0] != key || limiter.waits[1] != key {
+		t.Errorf("expected two waits for %q, got %v", key, limiter.waits)
+	}
+	feedback := limiter.feedback[key]
+	if len(feedback) != 2 {
+		t.Fatalf("expected feedback for two responses, got %v", limiter.feedback)
+	}
+	if !feedback[0].Throttled || feedback[1].Throttled {
+		t.Errorf("expected only the first response to be throttled, got %+v", feedback)
+	}
+	for _, f := range feedback {
+		if f.PriorityLevelUID != "workload-low" {
+			t.Errorf("expected the priority level from the response header, got %+v", f)
+		}
+	}
+}
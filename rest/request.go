@@ -105,7 +105,10 @@ type Request struct {
 	rateLimiter flowcontrol.RateLimiter
 	backoff     BackoffManagerWithContext
 	timeout     time.Duration
-	maxRetries  int
+	// timeoutSet is true if the timeout was set by Timeout and takes
+	// precedence over adaptive timeouts.
+	timeoutSet bool
+	maxRetries int
 
 	// generic components accessible via method setters
 	verb       string
@@ -477,6 +480,7 @@ func (r *Request) Timeout(d time.Duration) *Request {
 		return r
 	}
 	r.timeout = d
+	r.timeoutSet = true
 	return r
 }
 
@@ -671,7 +675,12 @@ func (r *Request) tryThrottleWithInfo(ctx context.Context, retryInfo string) err
 
 	now := time.Now()
 
-	err := r.rateLimiter.Wait(ctx)
+	var err error
+	if adaptive, ok := r.rateLimiter.(flowcontrol.AdaptiveRateLimiter); ok {
+		err = adaptive.WaitFor(ctx, r.rateLimiterKey())
+	} else {
+		err = r.rateLimiter.Wait(ctx)
+	}
 	// Don't wrap context errors as caller initiated ctx cancellations is not a rate limiter error.
 	if err != nil && !stderrors.Is(err, context.Canceled) && !stderrors.Is(err, context.DeadlineExceeded) {
 		err = fmt.Errorf("client rate limiter Wait returned an error: %w", err)
@@ -699,6 +708,40 @@ func (r *Request) tryThrottle(ctx context.Context) error {
 	return r.tryThrottleWithInfo(ctx, "")
 }
 
+// rateLimiterKey identifies the request for an AdaptiveRateLimiter. Priority
+// and fairness usually assigns requests with the same verb and resource to
+// the same priority level.
+func (r *Request) rateLimiterKey() string {
+	key := r.verb + " " + r.resource
+	if len(r.subresource) > 0 {
+		key += "/" + r.subresource
+	}
+	return key
+}
+
+// observeRateLimiterFeedback reports the response of the server to an
+// AdaptiveRateLimiter.
+func (r *Request) observeRateLimiterFeedback(resp *http.Response, latency time.Duration) {
+	adaptive, ok := r.rateLimiter.(flowcontrol.AdaptiveRateLimiter)
+	if !ok || resp == nil {
+		return
+	}
+	// priority and fairness sets the UID of the PriorityLevelConfiguration
+	// associated with a request in the following response Header.
+	const responseHeaderMatchedPriorityLevelConfigurationUID = "X-Kubernetes-PF-PriorityLevel-UID"
+	feedback := flowcontrol.RequestFeedback{
+		PriorityLevelUID: resp.Header.Get(responseHeaderMatchedPriorityLevelConfigurationUID),
+		Throttled:        resp.StatusCode == http.StatusTooManyRequests,
+		Latency:          latency,
+	}
+	if feedback.Throttled {
+		if seconds, ok := retryAfterSeconds(resp); ok {
+			feedback.RetryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+	adaptive.Observe(r.rateLimiterKey(), feedback)
+}
+
 type throttleSettings struct {
 	logLevel       int
 	minLogInterval time.Duration
@@ -757,7 +800,7 @@ func (b *throttledLogger) info(logger klog.Logger, message string, kv ...any) {
 
 // Watch attempts to begin watching the requested location.
 // Returns a watch.Interface, or an error.
-func (r *Request) Watch(ctx context.Context) (watch.Interface, error) {
+func (r *Request) Watch(ctx context.Context) (w watch.Interface, err error) {
 	if r.body == nil {
 		logBody(klog.FromContext(ctx), 2, "Request Body", r.bodyBytes)
 	}
@@ -781,19 +824,33 @@ func (r *Request) Watch(ctx context.Context) (watch.Interface, error) {
 		}
 		return false
 	}
+	ctx, span := r.c.telemetry.startRequest(ctx, r, "watch")
+	statusCode := 0
+	defer func() {
+		if err != nil {
+			span.end(statusCode, err)
+			return
+		}
+		w = span.watch(ctx, w, statusCode)
+	}()
+
 	retry := r.retryFn(r.maxRetries)
 	url := r.URL().String()
-	for {
+	for attempt := 0; ; attempt++ {
 		if err := retry.Before(ctx, r); err != nil {
 			return nil, retry.WrapPreviousError(err)
 		}
 
-		req, err := r.newHTTPRequest(ctx)
+		attemptCtx, attemptSpan := span.startAttempt(ctx, attempt)
+		req, err := r.newHTTPRequest(attemptCtx)
 		if err != nil {
+			endSpan(attemptSpan, 0, err)
 			return nil, err
 		}
 
 		resp, err := client.Do(req)
+		statusCode = statusCodeOf(resp)
+		endSpan(attemptSpan, statusCode, err)
 		retry.After(ctx, r, resp, err)
 		if err == nil && resp.StatusCode == http.StatusOK {
 			return r.newStreamWatcher(ctx, resp)
@@ -861,6 +918,7 @@ func (r *Request) newStreamWatcher(ctx context.Context, resp *http.Response) (wa
 func updateRequestResultMetric(ctx context.Context, req *Request, resp *http.Response, err error) {
 	code, host := sanitize(req, resp, err)
 	metrics.RequestResult.Increment(ctx, code, req.verb, host)
+	req.c.telemetry.countRequest(ctx, req, resp, err)
 }
 
 // updateRequestRetryMetric increments the RequestRetry metric counter,
@@ -869,6 +927,7 @@ func updateRequestResultMetric(ctx context.Context, req *Request, resp *http.Res
 func updateRequestRetryMetric(ctx context.Context, req *Request, resp *http.Response, err error) {
 	code, host := sanitize(req, resp, err)
 	metrics.RequestRetry.IncrementRetry(ctx, code, req.verb, host)
+	req.c.telemetry.countRetry(ctx, req, resp, err)
 }
 
 func sanitize(req *Request, resp *http.Response, err error) (string, string) {
@@ -891,7 +950,7 @@ func sanitize(req *Request, resp *http.Response, err error) (string, string) {
 // Returns io.ReadCloser which could be used for streaming of the response, or an error
 // Any non-2xx http status code causes an error.  If we get a non-2xx code, we try to convert the body into an APIStatus object.
 // If we can, we return that as an error.  Otherwise, we create an error that lists the http status and the content of the response.
-func (r *Request) Stream(ctx context.Context) (io.ReadCloser, error) {
+func (r *Request) Stream(ctx context.Context) (stream io.ReadCloser, err error) {
 	if r.body == nil {
 		logBody(klog.FromContext(ctx), 2, "Request Body", r.bodyBytes)
 	}
@@ -909,18 +968,32 @@ func (r *Request) Stream(ctx context.Context) (io.ReadCloser, error) {
 		client = http.DefaultClient
 	}
 
+	ctx, span := r.c.telemetry.startRequest(ctx, r, r.kubernetesVerb())
+	statusCode := 0
+	defer func() {
+		if err != nil {
+			span.end(statusCode, err)
+			return
+		}
+		stream = span.endOnClose(stream, statusCode)
+	}()
+
 	retry := r.retryFn(r.maxRetries)
 	url := r.URL().String()
-	for {
+	for attempt := 0; ; attempt++ {
 		if err := retry.Before(ctx, r); err != nil {
 			return nil, err
 		}
 
-		req, err := r.newHTTPRequest(ctx)
+		attemptCtx, attemptSpan := span.startAttempt(ctx, attempt)
+		req, err := r.newHTTPRequest(attemptCtx)
 		if err != nil {
+			endSpan(attemptSpan, 0, err)
 			return nil, err
 		}
 		resp, err := client.Do(req)
+		statusCode = statusCodeOf(resp)
+		endSpan(attemptSpan, statusCode, err)
 		retry.After(ctx, r, resp, err)
 		if err != nil {
 			// we only retry on an HTTP response with 'Retry-After' header
@@ -998,6 +1071,7 @@ func (r *Request) newHTTPRequest(ctx context.Context) (*http.Request, error) {
 		return nil, err
 	}
 	req.Header = r.headers
+	r.c.telemetry.injectTraceContext(ctx, req)
 	return req, nil
 }
 
@@ -1029,11 +1103,16 @@ func newDNSMetricsTrace(ctx context.Context) *httptrace.ClientTrace {
 // received. It handles retry behavior and up front validation of requests. It will invoke
 // fn at most once. It will return an error if a problem occurred prior to connecting to the
 // server - the provided function is responsible for handling server errors.
-func (r *Request) request(ctx context.Context, fn func(*http.Request, *http.Response)) error {
+func (r *Request) request(ctx context.Context, fn func(*http.Request, *http.Response)) (err error) {
 	// Metrics for total request latency
 	start := time.Now()
+	ctx, span := r.c.telemetry.startRequest(ctx, r, r.kubernetesVerb())
+	statusCode := 0
 	defer func() {
-		metrics.RequestLatency.Observe(ctx, r.verb, r.finalURLTemplate(), time.Since(start))
+		latency := time.Since(start)
+		metrics.RequestLatency.Observe(ctx, r.verb, r.finalURLTemplate(), latency)
+		span.observeDuration(ctx, latency, statusCode, err)
+		span.end(statusCode, err)
 	}()
 
 	if r.err != nil {
@@ -1079,15 +1158,29 @@ func (r *Request) request(ctx context.Context, fn func(*http.Request, *http.Resp
 
 	// Right now we make about ten retry attempts if we get a Retry-After response.
 	retry := r.retryFn(r.maxRetries)
-	for {
+	for attempt := 0; ; attempt++ {
 		if err := retry.Before(ctx, r); err != nil {
 			return retry.WrapPreviousError(err)
 		}
-		req, err := r.newHTTPRequest(ctx)
+		attemptCtx := ctx
+		attemptCancel := func() {}
+		timeout, adaptive := r.adaptiveTimeout()
+		if adaptive {
+			metrics.RequestAdaptiveTimeout.Observe(ctx, r.verb, r.URL().Host, timeout)
+			attemptCtx, attemptCancel = context.WithTimeout(ctx, timeout)
+		}
+		attemptCtx, attemptSpan := span.startAttempt(attemptCtx, attempt)
+		attemptStart := time.Now()
+		req, err := r.newHTTPRequest(attemptCtx)
 		if err != nil {
+			endSpan(attemptSpan, 0, err)
+			attemptCancel()
 			return err
 		}
-		resp, err := client.Do(req)
+		req, resp, err := r.roundTrip(client, req)
+		statusCode = statusCodeOf(resp)
+		endSpan(attemptSpan, statusCode, err)
+		r.observeRateLimiterFeedback(resp, time.Since(attemptStart))
 		// The value -1 or a value of 0 with a non-nil Body indicates that the length is unknown.
 		// https://pkg.go.dev/net/http#Request
 		if req.ContentLength >= 0 && !(req.Body != nil && req.ContentLength == 0) {
@@ -1116,6 +1209,12 @@ func (r *Request) request(ctx context.Context, fn func(*http.Request, *http.Resp
 			f(req, resp)
 			return true
 		}()
+		// Attempts which ran into their adaptive timeout are recorded, too,
+		// so that the timeout grows if the server got slower.
+		if resp != nil || (adaptive && attemptCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil) {
+			r.observeLatency(time.Since(attemptStart))
+		}
+		attemptCancel()
 		if done {
 			return retry.WrapPreviousError(err)
 		}
@@ -0,0 +1,439 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog/v2"
+)
+
+// TelemetryConfig configures OpenTelemetry tracing and metrics of the requests
+// of a client. Spans are created for every request, for each of its attempts
+// and for every watch stream, with the verb and resource of the request as
+// attributes. The metrics are recorded in addition to the ones of
+// k8s.io/client-go/tools/metrics.
+type TelemetryConfig struct {
+	// TracerProvider creates the spans of requests. If nil, no spans are
+	// created and no trace context is sent.
+	TracerProvider trace.TracerProvider
+	// Propagator injects the trace context into requests. If nil, the W3C
+	// trace context is used.
+	Propagator propagation.TextMapPropagator
+	// MeterProvider creates the instruments which record the metrics of
+	// requests. If nil, no OpenTelemetry metrics are recorded.
+	MeterProvider metric.MeterProvider
+}
+
+// telemetryScope is the name of the tracer and meter of the requests.
+const telemetryScope = "k8s.io/client-go/rest"
+
+// Attributes of the spans and metrics of requests. The HTTP attributes follow
+// the OpenTelemetry semantic conventions.
+const (
+	attrHTTPMethod      = attribute.Key("http.request.method")
+	attrHTTPStatusCode  = attribute.Key("http.response.status_code")
+	attrHTTPResendCount = attribute.Key("http.request.resend_count")
+	attrServerAddress   = attribute.Key("server.address")
+	attrURLFull         = attribute.Key("url.full")
+	attrErrorType       = attribute.Key("error.type")
+
+	attrVerb         = attribute.Key("k8s.verb")
+	attrAPIGroup     = attribute.Key("k8s.api.group")
+	attrAPIVersion   = attribute.Key("k8s.api.version")
+	attrResource     = attribute.Key("k8s.resource")
+	attrSubresource  = attribute.Key("k8s.subresource")
+	attrNamespace    = attribute.Key("k8s.namespace.name")
+	attrName         = attribute.Key("k8s.resource.name")
+	attrWatchEvents  = attribute.Key("k8s.watch.events")
+	attrRetryAttempt = attribute.Key("k8s.retry.attempt")
+	attrRetryReason  = attribute.Key("k8s.retry.reason")
+	attrRetryWait    = attribute.Key("k8s.retry.wait")
+)
+
+// requestDurationBuckets are the buckets of rest_client_request_duration_seconds.
+var requestDurationBuckets = []float64{0.005, 0.025, 0.1, 0.25, 0.5, 1.0, 2.0, 4.0, 8.0, 15.0, 30.0, 60.0}
+
+// requestTelemetry records OpenTelemetry spans and metrics of the requests of
+// a client, in addition to the metrics of k8s.io/client-go/tools/metrics.
+type requestTelemetry struct {
+	// tracer is nil if tracing is disabled.
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+
+	duration      metric.Float64Histogram
+	requests      metric.Int64Counter
+	retries       metric.Int64Counter
+	activeWatches metric.Int64UpDownCounter
+}
+
+// maybeSetTelemetry enables OpenTelemetry tracing and metrics if configured.
+//
+// May be called for a nil client.
+func maybeSetTelemetry(c *RESTClient, config TelemetryConfig) {
+	if c == nil || (config.TracerProvider == nil && config.MeterProvider == nil) {
+		return
+	}
+	t := &requestTelemetry{}
+	if config.TracerProvider != nil {
+		t.tracer = config.TracerProvider.Tracer(telemetryScope)
+		t.propagator = config.Propagator
+		if t.propagator == nil {
+			t.propagator = propagation.TraceContext{}
+		}
+	}
+	meterProvider := config.MeterProvider
+	if meterProvider == nil {
+		meterProvider = metricnoop.NewMeterProvider()
+	}
+	meter := meterProvider.Meter(telemetryScope)
+
+	// The instruments are usable even if their creation failed.
+	var errs []error
+	var err error
+	t.duration, err = meter.Float64Histogram("rest_client.request.duration",
+		metric.WithDescription("Duration of requests including their retries."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(requestDurationBuckets...))
+	errs = append(errs, err)
+	t.requests, err = meter.Int64Counter("rest_client.requests",
+		metric.WithDescription("Number of HTTP requests, one per attempt of a request."),
+		metric.WithUnit("{request}"))
+	errs = append(errs, err)
+	t.retries, err = meter.Int64Counter("rest_client.request.retries",
+		metric.WithDescription("Number of retries of requests."),
+		metric.WithUnit("{retry}"))
+	errs = append(errs, err)
+	t.activeWatches, err = meter.Int64UpDownCounter("rest_client.watches.active",
+		metric.WithDescription("Number of open watch streams."),
+		metric.WithUnit("{watch}"))
+	errs = append(errs, err)
+	if err := errors.Join(errs...); err != nil {
+		klog.Background().Error(err, "Unable to create the OpenTelemetry instruments of the client")
+	}
+	c.telemetry = t
+}
+
+// kubernetesVerb returns the verb of the request like the API server
+// determines it, for example list for a GET request of a collection.
+func (r *Request) kubernetesVerb() string {
+	switch r.verb {
+	case http.MethodGet, http.MethodHead:
+		switch {
+		case r.params.Get("watch") == "true" || r.params.Get("watch") == "1":
+			return "watch"
+		case len(r.resource) > 0 && len(r.resourceName) == 0:
+			return "list"
+		default:
+			return "get"
+		}
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		if len(r.resource) > 0 && len(r.resourceName) == 0 {
+			return "deletecollection"
+		}
+		return "delete"
+	}
+	return strings.ToLower(r.verb)
+}
+
+// telemetryAttributes returns the attributes which the spans and metrics of
+// the request share. Their cardinality is bounded, so that they are suitable
+// for metrics.
+func (r *Request) telemetryAttributes(verb string) []attribute.KeyValue {
+	host := "none"
+	if r.c.base != nil {
+		host = r.c.base.Host
+	}
+	attrs := []attribute.KeyValue{
+		attrVerb.String(verb),
+		attrHTTPMethod.String(r.verb),
+		attrServerAddress.String(host),
+	}
+	if len(r.resource) > 0 {
+		attrs = append(attrs,
+			attrAPIGroup.String(r.contentConfig.GroupVersion.Group),
+			attrAPIVersion.String(r.contentConfig.GroupVersion.Version),
+			attrResource.String(r.resource),
+		)
+		if len(r.subresource) > 0 {
+			attrs = append(attrs, attrSubresource.String(r.subresource))
+		}
+	}
+	return attrs
+}
+
+// resultAttribute describes the outcome of a request for metrics.
+func resultAttribute(statusCode int, err error) attribute.KeyValue {
+	switch {
+	case statusCode > 0:
+		return attrHTTPStatusCode.Int(statusCode)
+	case errors.Is(err, context.DeadlineExceeded):
+		return attrErrorType.String("timeout")
+	case errors.Is(err, context.Canceled):
+		return attrErrorType.String("canceled")
+	default:
+		return attrErrorType.String("_OTHER")
+	}
+}
+
+func statusCodeOf(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+// startRequest starts the span of a request. The returned span is nil if the
+// client has no telemetry, all its methods may be called regardless.
+func (t *requestTelemetry) startRequest(ctx context.Context, r *Request, verb string) (context.Context, *requestSpan) {
+	if t == nil {
+		return ctx, nil
+	}
+	s := &requestSpan{
+		telemetry: t,
+		req:       r,
+		attrs:     r.telemetryAttributes(verb),
+		span:      tracenoop.Span{},
+	}
+	if t.tracer == nil {
+		return ctx, s
+	}
+	name := verb
+	if len(r.resource) > 0 {
+		name += " " + path.Join(r.resource, r.subresource)
+	}
+	attrs := s.attrs[:len(s.attrs):len(s.attrs)]
+	if len(r.namespace) > 0 {
+		attrs = append(attrs, attrNamespace.String(r.namespace))
+	}
+	if len(r.resourceName) > 0 {
+		attrs = append(attrs, attrName.String(r.resourceName))
+	}
+	ctx, s.span = t.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	return ctx, s
+}
+
+// injectTraceContext adds the trace context of ctx to the headers of req.
+func (t *requestTelemetry) injectTraceContext(ctx context.Context, req *http.Request) {
+	if t == nil || t.tracer == nil {
+		return
+	}
+	// The headers are shared by all attempts of the request.
+	header := req.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+	req.Header = header
+}
+
+// countRequest counts an attempt of a request, like metrics.RequestResult.
+func (t *requestTelemetry) countRequest(ctx context.Context, r *Request, resp *http.Response, err error) {
+	if t == nil {
+		return
+	}
+	attrs := append(r.telemetryAttributes(r.kubernetesVerb()), resultAttribute(statusCodeOf(resp), err))
+	t.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
+// countRetry counts a retry of a request, like metrics.RequestRetry.
+func (t *requestTelemetry) countRetry(ctx context.Context, r *Request, resp *http.Response, err error) {
+	if t == nil {
+		return
+	}
+	attrs := append(r.telemetryAttributes(r.kubernetesVerb()), resultAttribute(statusCodeOf(resp), err))
+	t.retries.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
+// retryEvent records on the span of a request that it is retried.
+func (t *requestTelemetry) retryEvent(ctx context.Context, retryAfter *RetryAfter) {
+	if t == nil || t.tracer == nil {
+		return
+	}
+	trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+		attrRetryAttempt.Int(retryAfter.Attempt),
+		attrRetryReason.String(retryAfter.Reason),
+		attrRetryWait.Float64(retryAfter.Wait.Seconds()),
+	))
+}
+
+// requestSpan traces a request, from its first attempt until its response or
+// watch stream ended.
+type requestSpan struct {
+	telemetry *requestTelemetry
+	req       *Request
+	attrs     []attribute.KeyValue
+	span      trace.Span
+}
+
+// startAttempt starts the span of an attempt of the request, which is
+// retried if attempt is greater than zero.
+func (s *requestSpan) startAttempt(ctx context.Context, attempt int) (context.Context, trace.Span) {
+	if s == nil || s.telemetry.tracer == nil {
+		return ctx, tracenoop.Span{}
+	}
+	attrs := []attribute.KeyValue{
+		attrHTTPMethod.String(s.req.verb),
+		attrURLFull.String(s.req.URL().String()),
+	}
+	if s.req.c.base != nil {
+		attrs = append(attrs, attrServerAddress.String(s.req.c.base.Host))
+	}
+	if attempt > 0 {
+		attrs = append(attrs, attrHTTPResendCount.Int(attempt))
+	}
+	return s.telemetry.tracer.Start(ctx, s.req.verb, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// observeDuration records the duration of the request, like
+// metrics.RequestLatency.
+func (s *requestSpan) observeDuration(ctx context.Context, duration time.Duration, statusCode int, err error) {
+	if s == nil {
+		return
+	}
+	attrs := append(s.attrs[:len(s.attrs):len(s.attrs)], resultAttribute(statusCode, err))
+	s.telemetry.duration.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
+}
+
+// end ends the span of the request.
+func (s *requestSpan) end(statusCode int, err error) {
+	if s == nil {
+		return
+	}
+	endSpan(s.span, statusCode, err)
+}
+
+// endOnClose ends the span of the request once its response body is closed.
+func (s *requestSpan) endOnClose(body io.ReadCloser, statusCode int) io.ReadCloser {
+	if s == nil {
+		return body
+	}
+	return &spanEndingBody{ReadCloser: body, end: func() { s.end(statusCode, nil) }}
+}
+
+// watch ends the span of the request once the watch stream ends, and
+// counts the stream as active until then.
+func (s *requestSpan) watch(ctx context.Context, w watch.Interface, statusCode int) watch.Interface {
+	if s == nil {
+		return w
+	}
+	attrs := metric.WithAttributes(s.attrs...)
+	s.telemetry.activeWatches.Add(ctx, 1, attrs)
+	tw := &spanEndingWatcher{
+		Interface: w,
+		result:    make(chan watch.Event),
+		stopped:   make(chan struct{}),
+	}
+	go tw.run(func(events int64) {
+		s.telemetry.activeWatches.Add(context.WithoutCancel(ctx), -1, attrs)
+		s.span.SetAttributes(attrWatchEvents.Int64(events))
+		s.end(statusCode, nil)
+	})
+	return tw
+}
+
+// endSpan records the outcome of a request or of an attempt and ends its span.
+func endSpan(span trace.Span, statusCode int, err error) {
+	if statusCode > 0 {
+		span.SetAttributes(attrHTTPStatusCode.Int(statusCode))
+	}
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case statusCode >= http.StatusBadRequest:
+		span.SetStatus(codes.Error, http.StatusText(statusCode))
+	}
+	span.End()
+}
+
+type spanEndingBody struct {
+	io.ReadCloser
+	once sync.Once
+	end  func()
+}
+
+func (b *spanEndingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.end)
+	return err
+}
+
+// spanEndingWatcher forwards the events of a watch stream and calls a
+// function with the number of events once the stream ended, before its
+// result channel is closed.
+type spanEndingWatcher struct {
+	watch.Interface
+	result   chan watch.Event
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func (w *spanEndingWatcher) run(end func(events int64)) {
+	var events int64
+	defer func() {
+		end(events)
+		close(w.result)
+	}()
+	for {
+		select {
+		case event, ok := <-w.Interface.ResultChan():
+			if !ok {
+				return
+			}
+			select {
+			case w.result <- event:
+				events++
+			case <-w.stopped:
+				return
+			}
+		case <-w.stopped:
+			return
+		}
+	}
+}
+
+func (w *spanEndingWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+func (w *spanEndingWatcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stopped) })
+	w.Interface.Stop()
+}
@@ -0,0 +1,420 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer/streaming"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclientwatch "k8s.io/client-go/rest/watch"
+)
+
+// testTracerProvider records the spans which its tracers start, so that the
+// tests don't depend on the OpenTelemetry SDK.
+type testTracerProvider struct {
+	tracenoop.TracerProvider
+
+	lock   sync.Mutex
+	lastID uint64
+	ended  []*testSpan
+}
+
+func (p *testTracerProvider) Tracer(name string, options ...trace.TracerOption) trace.Tracer {
+	return &testTracer{provider: p}
+}
+
+// Ended returns the ended spans in the order in which they ended.
+func (p *testTracerProvider) Ended() []*testSpan {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return append([]*testSpan(nil), p.ended...)
+}
+
+func (p *testTracerProvider) newID() uint64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.lastID++
+	return p.lastID
+}
+
+type testTracer struct {
+	tracenoop.Tracer
+	provider *testTracerProvider
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+	config := trace.NewSpanStartConfig(options...)
+	parent := trace.SpanContextFromContext(ctx)
+	traceID := parent.TraceID()
+	if !parent.IsValid() {
+		binary.BigEndian.PutUint64(traceID[8:], t.provider.newID())
+	}
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(spanID[:], t.provider.newID())
+	span := &testSpan{
+		provider: t.provider,
+		name:     name,
+		kind:     config.SpanKind(),
+		parent:   parent,
+		spanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+		}),
+		attributes: map[attribute.Key]attribute.Value{},
+	}
+	span.SetAttributes(config.Attributes()...)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type testSpan struct {
+	tracenoop.Span
+	provider    *testTracerProvider
+	name        string
+	kind        trace.SpanKind
+	parent      trace.SpanContext
+	spanContext trace.SpanContext
+
+	lock       sync.Mutex
+	attributes map[attribute.Key]attribute.Value
+	events     []string
+	status     codes.Code
+}
+
+func (s *testSpan) SpanContext() trace.SpanContext { return s.spanContext }
+
+func (s *testSpan) IsRecording() bool { return true }
+
+func (s *testSpan) SetAttributes(kv ...attribute.KeyValue) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, attr := range kv {
+		s.attributes[attr.Key] = attr.Value
+	}
+}
+
+func (s *testSpan) AddEvent(name string, options ...trace.EventOption) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.events = append(s.events, name)
+}
+
+func (s *testSpan) SetStatus(code codes.Code, description string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.status = code
+}
+
+func (s *testSpan) End(options ...trace.SpanEndOption) {
+	s.provider.lock.Lock()
+	defer s.provider.lock.Unlock()
+	s.provider.ended = append(s.provider.ended, s)
+}
+
+func (s *testSpan) Attributes() map[attribute.Key]attribute.Value {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.attributes
+}
+
+// testMeterProvider records the values of the instruments which its meters
+// create. For each instrument, it keeps a value per attribute set: the sum of
+// counters and the number of observations of histograms.
+type testMeterProvider struct {
+	metricnoop.MeterProvider
+
+	lock   sync.Mutex
+	points map[string]map[attribute.Distinct]int64
+}
+
+func newTestMeterProvider() *testMeterProvider {
+	return &testMeterProvider{points: map[string]map[attribute.Distinct]int64{}}
+}
+
+func (p *testMeterProvider) Meter(name string, options ...metric.MeterOption) metric.Meter {
+	return &testMeter{provider: p}
+}
+
+func (p *testMeterProvider) add(name string, attrs attribute.Set, value int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.points[name] == nil {
+		p.points[name] = map[attribute.Distinct]int64{}
+	}
+	p.points[name][attrs.Equivalent()] += value
+}
+
+// collect returns the values of the instruments by name.
+func (p *testMeterProvider) collect() map[string][]int64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	points := map[string][]int64{}
+	for name, values := range p.points {
+		for _, value := range values {
+			points[name] = append(points[name], value)
+		}
+	}
+	return points
+}
+
+type testMeter struct {
+	metricnoop.Meter
+	provider *testMeterProvider
+}
+
+func (m *testMeter) Int64Counter(name string, options ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return &testInt64Counter{provider: m.provider, name: name}, nil
+}
+
+func (m *testMeter) Int64UpDownCounter(name string, options ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	return &testInt64UpDownCounter{provider: m.provider, name: name}, nil
+}
+
+func (m *testMeter) Float64Histogram(name string, options ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return &testFloat64Histogram{provider: m.provider, name: name}, nil
+}
+
+type testInt64Counter struct {
+	metricnoop.Int64Counter
+	provider *testMeterProvider
+	name     string
+}
+
+func (c *testInt64Counter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	c.provider.add(c.name, metric.NewAddConfig(options).Attributes(), incr)
+}
+
+type testInt64UpDownCounter struct {
+	metricnoop.Int64UpDownCounter
+	provider *testMeterProvider
+	name     string
+}
+
+func (c *testInt64UpDownCounter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	c.provider.add(c.name, metric.NewAddConfig(options).Attributes(), incr)
+}
+
+type testFloat64Histogram struct {
+	metricnoop.Float64Histogram
+	provider *testMeterProvider
+	name     string
+}
+
+func (h *testFloat64Histogram) Record(ctx context.Context, value float64, options ...metric.RecordOption) {
+	h.provider.add(h.name, metric.NewRecordConfig(options).Attributes(), 1)
+}
+
+func newTelemetryTestClient(t *testing.T, server *httptest.Server) (*RESTClient, *testTracerProvider, *testMeterProvider) {
+	t.Helper()
+	tracerProvider := &testTracerProvider{}
+	meterProvider := newTestMeterProvider()
+	c := testRESTClient(t, server)
+	maybeSetTelemetry(c, TelemetryConfig{
+		TracerProvider: tracerProvider,
+		MeterProvider:  meterProvider,
+	})
+	return c, tracerProvider, meterProvider
+}
+
+func TestTelemetryRequest(t *testing.T) {
+	var lock sync.Mutex
+	var traceParents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		traceParents = append(traceParents, r.Header.Get("traceparent"))
+		if len(traceParents) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"Pod","apiVersion":"v1","metadata":{"name":"foo"}}`))
+	}))
+	defer server.Close()
+	c, spans, meters := newTelemetryTestClient(t, server)
+
+	if err := c.Get().Namespace("default").Resource("pods").Name("foo").Do(context.Background()).Error(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 3 {
+		t.Fatalf("expected a span per attempt and one for the request, got %d", len(ended))
+	}
+	request := ended[2]
+	if request.name != "get pods" {
+		t.Errorf("expected the span of the request to be named after the verb and resource, got %q", request.name)
+	}
+	attrs := request.Attributes()
+	for key, expected := range map[attribute.Key]string{
+		attrVerb:          "get",
+		attrHTTPMethod:    "GET",
+		attrAPIGroup:      "",
+		attrAPIVersion:    "v1",
+		attrResource:      "pods",
+		attrNamespace:     "default",
+		attrName:          "foo",
+		attrServerAddress: server.Listener.Addr().String(),
+	} {
+		if actual := attrs[key].AsString(); actual != expected {
+			t.Errorf("expected %s %q, got %q", key, expected, actual)
+		}
+	}
+	if code := attrs[attrHTTPStatusCode].AsInt64(); code != http.StatusOK {
+		t.Errorf("expected status code 200, got %d", code)
+	}
+	if events := request.events; len(events) != 1 || events[0] != "retry" {
+		t.Errorf("expected a retry event, got %v", events)
+	}
+
+	for i, attempt := range ended[:2] {
+		if attempt.parent.SpanID() != request.SpanContext().SpanID() {
+			t.Errorf("attempt %d: expected the request as parent", i)
+		}
+		if attempt.kind != trace.SpanKindClient {
+			t.Errorf("attempt %d: expected a client span, got %v", i, attempt.kind)
+		}
+		if resendCount := attempt.Attributes()[attrHTTPResendCount].AsInt64(); resendCount != int64(i) {
+			t.Errorf("attempt %d: expected resend count %d, got %d", i, i, resendCount)
+		}
+		expected := "00-" + attempt.SpanContext().TraceID().String() + "-" + attempt.SpanContext().SpanID().String() + "-01"
+		if traceParents[i] != expected {
+			t.Errorf("attempt %d: expected traceparent %q, got %q", i, expected, traceParents[i])
+		}
+	}
+	if status := ended[0].status; status != codes.Error {
+		t.Errorf("expected the throttled attempt to fail, got %v", status)
+	}
+
+	points := meters.collect()
+	if requests := points["rest_client.requests"]; len(requests) != 2 || requests[0]+requests[1] != 2 {
+		t.Errorf("expected a request with status 429 and one with 200, got %v", requests)
+	}
+	if retries := points["rest_client.request.retries"]; len(retries) != 1 || retries[0] != 1 {
+		t.Errorf("expected one retry, got %v", retries)
+	}
+	if durations := points["rest_client.request.duration"]; len(durations) != 1 || durations[0] != 1 {
+		t.Errorf("expected the duration of one request, got %v", durations)
+	}
+}
+
+func TestTelemetryWatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		codec := scheme.Codecs.LegacyCodec(v1.SchemeGroupVersion)
+		encoder := restclientwatch.NewEncoder(streaming.NewEncoder(w, codec), codec)
+		for _, name := range []string{"foo", "bar"} {
+			if err := encoder.Encode(&watch.Event{Type: watch.Added, Object: &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}}); err != nil {
+				t.Error(err)
+			}
+		}
+	}))
+	defer server.Close()
+	c, spans, meters := newTelemetryTestClient(t, server)
+
+	w, err := c.Get().Resource("pods").Param("watch", "true").Watch(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if active := meters.collect()["rest_client.watches.active"]; len(active) != 1 || active[0] != 1 {
+		t.Errorf("expected one active watch, got %v", active)
+	}
+	events := 0
+	for range w.ResultChan() {
+		events++
+	}
+	w.Stop()
+	if events != 2 {
+		t.Errorf("expected two events, got %d", events)
+	}
+
+	var stream *testSpan
+	for _, span := range spans.Ended() {
+		if span.name == "watch pods" {
+			stream = span
+		}
+	}
+	if stream == nil {
+		t.Fatal("expected the span of the watch stream to end with the stream")
+	}
+	if watchEvents := stream.Attributes()[attrWatchEvents].AsInt64(); watchEvents != 2 {
+		t.Errorf("expected two events on the span, got %d", watchEvents)
+	}
+	if active := meters.collect()["rest_client.watches.active"]; len(active) != 1 || active[0] != 0 {
+		t.Errorf("expected no active watch, got %v", active)
+	}
+}
+
+func TestTelemetryDisabled(t *testing.T) {
+	var traceParent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+	c := testRESTClient(t, server)
+
+	// The trace context of the caller is not sent unless tracing is enabled.
+	tp := &testTracerProvider{}
+	ctx, span := tp.Tracer("test").Start(context.Background(), "caller")
+	defer span.End()
+	if _, err := c.Get().AbsPath("/version").DoRaw(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(traceParent) > 0 {
+		t.Errorf("expected no traceparent, got %q", traceParent)
+	}
+}
+
+func TestKubernetesVerb(t *testing.T) {
+	c := testRESTClient(t, nil)
+	testCases := map[string]*Request{
+		"get":              c.Get().Resource("pods").Name("foo"),
+		"list":             c.Get().Resource("pods"),
+		"watch":            c.Get().Resource("pods").Param("watch", "true"),
+		"create":           c.Post().Resource("pods"),
+		"update":           c.Put().Resource("pods").Name("foo").SubResource("status"),
+		"patch":            c.Patch("application/merge-patch+json").Resource("pods").Name("foo"),
+		"delete":           c.Delete().Resource("pods").Name("foo"),
+		"deletecollection": c.Delete().Resource("pods"),
+	}
+	for expected, r := range testCases {
+		if verb := r.kubernetesVerb(); verb != expected {
+			t.Errorf("expected %q, got %q", expected, verb)
+		}
+	}
+	if verb := c.Get().AbsPath("/version").kubernetesVerb(); verb != "get" {
+		t.Errorf("expected get for a non-resource request, got %q", verb)
+	}
+}
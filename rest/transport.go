@@ -110,7 +110,9 @@ func (c *Config) TransportConfig() (*transport.Config, error) {
 			Groups:   c.Impersonate.Groups,
 			Extra:    c.Impersonate.Extra,
 		},
-		Proxy: c.Proxy,
+		Proxy:          c.Proxy,
+		CircuitBreaker: c.CircuitBreaker,
+		Audit:          c.Audit,
 	}
 
 	if c.Dial != nil {
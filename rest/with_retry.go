@@ -232,6 +232,7 @@ func (r *withRetry) Before(ctx context.Context, request *Request) error {
 	}
 
 	klog.FromContext(ctx).V(4).Info("Got a Retry-After response", "delay", r.retryAfter.Wait, "attempt", r.retryAfter.Attempt, "url", request.URL())
+	request.c.telemetry.retryEvent(ctx, r.retryAfter)
 	return nil
 }
 
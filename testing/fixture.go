@@ -100,6 +100,9 @@ type ObjectScheme interface {
 // root resource are not automatically updated when a scale resource is updated, for example).
 func ObjectReaction(tracker ObjectTracker) ReactionFunc {
 	reactor := objectTrackerReact{tracker: tracker}
+	if strict, ok := tracker.(interface{ strictResourceVersions() bool }); ok {
+		reactor.strictResourceVersions = strict.strictResourceVersions()
+	}
 	return func(action Action) (bool, runtime.Object, error) {
 		// Here and below we need to switch on implementation types,
 		// not on interfaces, as some interfaces are identical
@@ -136,6 +139,9 @@ func ObjectReaction(tracker ObjectTracker) ReactionFunc {
 
 type objectTrackerReact struct {
 	tracker ObjectTracker
+	// strictResourceVersions is true if the tracker assigns resource
+	// versions to the stored objects, see ObjectTrackerOptions.
+	strictResourceVersions bool
 }
 
 func (o objectTrackerReact) List(action ListActionImpl) (runtime.Object, error) {
@@ -282,6 +288,23 @@ func (o objectTrackerReact) Patch(action PatchActionImpl) (runtime.Object, error
 		return nil, err
 	}
 
+	if o.strictResourceVersions {
+		// The tracker assigned a new resource version to the stored object.
+		stored, err := o.tracker.Get(gvr, ns, action.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		storedMeta, err := meta.Accessor(stored)
+		if err != nil {
+			return nil, err
+		}
+		objMeta, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		objMeta.SetResourceVersion(storedMeta.GetResourceVersion())
+	}
+
 	return obj, nil
 }
 
@@ -291,11 +314,12 @@ type tracker struct {
 	lock    sync.RWMutex
 	objects map[schema.GroupVersionResource]map[types.NamespacedName]versionedObject
 	// The value type of watchers is a map of which the key is either a namespace or
-	// all/non namespace aka "" and its value is list of fake watchers.
+	// all/non namespace aka "" and its value is list of fake watchers, which only
+	// receive events for objects matching the selectors they were created with.
 	// Manipulations on resources will broadcast the notification events into the
 	// watchers' channel. Note that too many unhandled events (currently 100,
 	// see apimachinery/pkg/watch.DefaultChanSize) will cause a panic.
-	watchers map[schema.GroupVersionResource]map[string][]*watch.RaceFreeFakeWatcher
+	watchers map[schema.GroupVersionResource]map[string][]*selectingWatcher
 	// resourceVersions is the highest resource version of any tracked object with
 	// a certain gvr. Conceptually it starts at 1 when no objects are stored (0 is
 	// special in queries) but the map contains no entries in that case.
@@ -304,9 +328,10 @@ type tracker struct {
 	//
 	// Object content does not get changed to preserve the traditional behavior
 	// (hence also the versionedObject type instead of storing a runtime.Object
-	// with modified ResourceVersion).
+	// with modified ResourceVersion), unless StrictResourceVersions is enabled
+	// in the options.
 	//
-	// Resource version support (https://kubernetes.io/docs/reference/using-api/api-concepts/#resource-versions)
+	// Without StrictResourceVersions, resource version support (https://kubernetes.io/docs/reference/using-api/api-concepts/#resource-versions)
 	// is very limited. It only supports one particular use case:
 	// List (no resource version check, returned ListMeta has ResourceVersion set) +
 	// Watch (Exact match for the ResourceVersion returned by List).
@@ -318,12 +343,18 @@ type tracker struct {
 	// also tracked by GroupVersionResource instead of GroupVersion, so the
 	// same is done here to match how List is implemented.
 	resourceVersions map[schema.GroupVersionResource]int64
+	// selectableFields are the field labels registered through
+	// RegisterSelectableFields, see selectableFieldsFor.
+	selectableFields map[schema.GroupVersionResource]map[string]string
+	options          ObjectTrackerOptions
+	// histories holds the recent events per resource if StrictResourceVersions is enabled.
+	histories map[schema.GroupVersionResource]*eventHistory
 }
 
 // versionedObject stores an object together with the resource version that was
 // assigned to it by the tracker. The version could be stored inline in the object,
 // but this is not how fake client-go has traditionally worked and starting to do
-// that now might break tests.
+// that now might break tests. It is only done with StrictResourceVersions.
 type versionedObject struct {
 	// resourceVersion is always > 1 for a stored object because 1
 	// is the initial value for an empty set of objects.
@@ -336,17 +367,28 @@ var _ ObjectTracker = &tracker{}
 // NewObjectTracker returns an ObjectTracker that can be used to keep track
 // of objects for the fake clientset. Mostly useful for unit tests.
 func NewObjectTracker(scheme ObjectScheme, decoder runtime.Decoder) ObjectTracker {
+	return NewObjectTrackerWithOptions(scheme, decoder, ObjectTrackerOptions{})
+}
+
+// NewObjectTrackerWithOptions is like NewObjectTracker, with additional options.
+func NewObjectTrackerWithOptions(scheme ObjectScheme, decoder runtime.Decoder, options ObjectTrackerOptions) ObjectTracker {
+	if options.WatchHistorySize <= 0 {
+		options.WatchHistorySize = DefaultWatchHistorySize
+	}
 	return &tracker{
 		scheme:           scheme,
 		decoder:          decoder,
 		objects:          make(map[schema.GroupVersionResource]map[types.NamespacedName]versionedObject),
-		watchers:         make(map[schema.GroupVersionResource]map[string][]*watch.RaceFreeFakeWatcher),
+		watchers:         make(map[schema.GroupVersionResource]map[string][]*selectingWatcher),
 		resourceVersions: make(map[schema.GroupVersionResource]int64),
+		selectableFields: make(map[schema.GroupVersionResource]map[string]string),
+		options:          options,
+		histories:        make(map[schema.GroupVersionResource]*eventHistory),
 	}
 }
 
 func (t *tracker) List(gvr schema.GroupVersionResource, gvk schema.GroupVersionKind, ns string, opts ...metav1.ListOptions) (runtime.Object, error) {
-	_, err := assertOptionalSingleArgument(opts)
+	listOpts, err := assertOptionalSingleArgument(opts)
 	if err != nil {
 		return nil, err
 	}
@@ -373,16 +415,35 @@ func (t *tracker) List(gvr schema.GroupVersionResource, gvk schema.GroupVersionK
 	t.lock.RLock()
 	defer t.lock.RUnlock()
 
+	selectableFields, complete := t.selectableFieldsFor(gvr)
+	predicate, err := newSelectionPredicate(gvr.GroupResource(), listOpts, selectableFields, complete)
+	if err != nil {
+		return nil, err
+	}
+
+	exactResourceVersion, err := t.listResourceVersion(gvr, listOpts)
+	if err != nil {
+		return nil, err
+	}
+
 	if listMeta, err := meta.ListAccessor(list); err == nil {
 		resourceVersion, ok := t.resourceVersions[gvr]
 		if !ok {
 			resourceVersion = 1
 		}
+		if exactResourceVersion > 0 {
+			resourceVersion = exactResourceVersion
+		}
 		listMeta.SetResourceVersion(fmt.Sprintf("%d", resourceVersion))
 	}
 
 	objs, ok := t.objects[gvr]
-	if !ok {
+	if exactResourceVersion > 0 {
+		objs, err = t.objectsAt(gvr, exactResourceVersion)
+		if err != nil {
+			return nil, err
+		}
+	} else if !ok {
 		return list, nil
 	}
 
@@ -390,9 +451,15 @@ func (t *tracker) List(gvr schema.GroupVersionResource, gvk schema.GroupVersionK
 	if err != nil {
 		return nil, err
 	}
-	matchingObjs := make([]runtime.Object, len(matchingVersionedObjs))
-	for i, obj := range matchingVersionedObjs {
-		matchingObjs[i] = obj.Object
+	matchingObjs := make([]runtime.Object, 0, len(matchingVersionedObjs))
+	for _, obj := range matchingVersionedObjs {
+		matches, err := predicate.matches(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			matchingObjs = append(matchingObjs, obj.Object)
+		}
 	}
 	if err := meta.SetList(list, matchingObjs); err != nil {
 		return nil, err
@@ -401,7 +468,7 @@ func (t *tracker) List(gvr schema.GroupVersionResource, gvk schema.GroupVersionK
 }
 
 func (t *tracker) Watch(gvr schema.GroupVersionResource, ns string, opts ...metav1.ListOptions) (watch.Interface, error) {
-	_, err := assertOptionalSingleArgument(opts)
+	listOpts, err := assertOptionalSingleArgument(opts)
 	if err != nil {
 		return nil, err
 	}
@@ -427,15 +494,58 @@ func (t *tracker) Watch(gvr schema.GroupVersionResource, ns string, opts ...meta
 		}
 	}
 
+	sendInitialEvents, err := validateSendInitialEvents(listOpts)
+	if err != nil {
+		return nil, err
+	}
+
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
-	fakewatcher := watch.NewRaceFreeFake()
+	selectableFields, complete := t.selectableFieldsFor(gvr)
+	predicate, err := newSelectionPredicate(gvr.GroupResource(), listOpts, selectableFields, complete)
+	if err != nil {
+		return nil, err
+	}
+	fakewatcher := &selectingWatcher{
+		RaceFreeFakeWatcher: watch.NewRaceFreeFake(),
+		predicate:           predicate,
+	}
 
-	if _, exists := t.watchers[gvr]; !exists {
-		t.watchers[gvr] = make(map[string][]*watch.RaceFreeFakeWatcher)
+	switch {
+	case sendInitialEvents:
+		// The initial events are the current state, which is never older
+		// than the requested resource version, followed by a bookmark.
+		if current := max(t.resourceVersions[gvr], 1); addFromRV > current {
+			return nil, newTooLargeResourceVersionError(addFromRV, current)
+		}
+		addFromRV = 0
+	case t.options.StrictResourceVersions && addFromRV > 0:
+		// With strict resource versions, a watch from a resource version receives
+		// the events which happened after it.
+		events, err := t.eventsSince(gvr, addFromRV)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			if ns != metav1.NamespaceAll && event.key.Namespace != ns {
+				continue
+			}
+			switch event.eventType {
+			case watch.Added:
+				if fakewatcher.matches(event.object) {
+					fakewatcher.Add(event.object.DeepCopyObject())
+				}
+			case watch.Modified:
+				fakewatcher.modify(event.oldObject, event.object.DeepCopyObject())
+			case watch.Deleted:
+				if fakewatcher.matches(event.oldObject) {
+					fakewatcher.Delete(event.object.DeepCopyObject())
+				}
+			}
+		}
+		addExisting = false
 	}
-	t.watchers[gvr][ns] = append(t.watchers[gvr][ns], fakewatcher)
 
 	// Deliver all objects that match the list options, for example
 	// between the initial List and the following Watch.
@@ -446,12 +556,26 @@ func (t *tracker) Watch(gvr schema.GroupVersionResource, ns string, opts ...meta
 			return nil, err
 		}
 		for _, obj := range matchingObjs {
-			if addFromRV < obj.resourceVersion {
+			if addFromRV < obj.resourceVersion && fakewatcher.matches(obj.Object) {
 				fakewatcher.Add(obj.Object)
 			}
 		}
 	}
 
+	if sendInitialEvents {
+		bookmark, err := t.newInitialEventsEndBookmark(gvr)
+		if err != nil {
+			return nil, err
+		}
+		fakewatcher.Action(watch.Bookmark, bookmark)
+	}
+
+	if _, exists := t.watchers[gvr]; !exists {
+		t.watchers[gvr] = make(map[string][]*selectingWatcher)
+	}
+	t.watchers[gvr][ns] = append(t.watchers[gvr][ns], fakewatcher)
+	fakewatcher.start()
+
 	return fakewatcher, nil
 }
 
@@ -595,19 +719,23 @@ func (t *tracker) Apply(gvr schema.GroupVersionResource, applyConfiguration runt
 	return t.add(gvr, obj, ns, true)
 }
 
-// IsWatchListSemanticsUnSupported informs the reflector that this client
-// doesn't support WatchList semantics.
+// IsWatchListSemanticsUnSupported informs the reflector whether this client
+// supports WatchList semantics.
 //
-// This is a synthetic method whose sole purpose is to satisfy the optional
-// interface check performed by the reflector.
-// Returning true signals that WatchList can NOT be used.
-// No additional logic is implemented here.
+// Watch always honors SendInitialEvents, but the resource versions of the
+// initial events are only meaningful with StrictResourceVersions. Without
+// it, the tracker signals that WatchList can NOT be used, which keeps
+// informers on top of it using List and Watch like they always did.
 func (t *tracker) IsWatchListSemanticsUnSupported() bool {
-	return true
+	return !t.options.StrictResourceVersions
 }
 
-func (t *tracker) getWatches(gvr schema.GroupVersionResource, ns string) []*watch.RaceFreeFakeWatcher {
-	watches := []*watch.RaceFreeFakeWatcher{}
+func (t *tracker) strictResourceVersions() bool {
+	return t.options.StrictResourceVersions
+}
+
+func (t *tracker) getWatches(gvr schema.GroupVersionResource, ns string) []*selectingWatcher {
+	watches := []*selectingWatcher{}
 	if t.watchers[gvr] != nil {
 		if w := t.watchers[gvr][ns]; w != nil {
 			watches = append(watches, w...)
@@ -660,14 +788,27 @@ func (t *tracker) add(gvr schema.GroupVersionResource, obj runtime.Object, ns st
 	resourceVersion++
 
 	namespacedName := types.NamespacedName{Namespace: newMeta.GetNamespace(), Name: newMeta.GetName()}
-	if _, ok = t.objects[gvr][namespacedName]; ok {
+	if oldObj, ok := t.objects[gvr][namespacedName]; ok {
 		if replaceExisting {
+			if t.options.StrictResourceVersions {
+				if err := checkPrecondition(gr, newMeta.GetName(), newMeta.GetResourceVersion(), oldObj); err != nil {
+					return err
+				}
+				newMeta.SetResourceVersion(strconv.FormatInt(resourceVersion, 10))
+			}
 			t.resourceVersions[gvr] = resourceVersion
 			t.objects[gvr][namespacedName] = versionedObject{resourceVersion, obj}
+			t.recordEvent(gvr, trackerEvent{
+				resourceVersion: resourceVersion,
+				eventType:       watch.Modified,
+				key:             namespacedName,
+				object:          obj,
+				oldObject:       oldObj.Object,
+			})
 
 			for _, w := range t.getWatches(gvr, ns) {
 				// To avoid the object from being accidentally modified by watcher
-				w.Modify(obj.DeepCopyObject())
+				w.modify(oldObj.Object, obj.DeepCopyObject())
 			}
 			return nil
 		}
@@ -679,12 +820,23 @@ func (t *tracker) add(gvr schema.GroupVersionResource, obj runtime.Object, ns st
 		return apierrors.NewNotFound(gr, newMeta.GetName())
 	}
 
+	if t.options.StrictResourceVersions {
+		newMeta.SetResourceVersion(strconv.FormatInt(resourceVersion, 10))
+	}
 	t.resourceVersions[gvr] = resourceVersion
 	t.objects[gvr][namespacedName] = versionedObject{resourceVersion, obj}
+	t.recordEvent(gvr, trackerEvent{
+		resourceVersion: resourceVersion,
+		eventType:       watch.Added,
+		key:             namespacedName,
+		object:          obj,
+	})
 
 	for _, w := range t.getWatches(gvr, ns) {
-		// To avoid the object from being accidentally modified by watcher
-		w.Add(obj.DeepCopyObject())
+		if w.matches(obj) {
+			// To avoid the object from being accidentally modified by watcher
+			w.Add(obj.DeepCopyObject())
+		}
 	}
 
 	return nil
@@ -708,7 +860,7 @@ func (t *tracker) addList(obj runtime.Object, replaceExisting bool) error {
 }
 
 func (t *tracker) Delete(gvr schema.GroupVersionResource, ns, name string, opts ...metav1.DeleteOptions) error {
-	_, err := assertOptionalSingleArgument(opts)
+	deleteOpts, err := assertOptionalSingleArgument(opts)
 	if err != nil {
 		return err
 	}
@@ -726,9 +878,34 @@ func (t *tracker) Delete(gvr schema.GroupVersionResource, ns, name string, opts
 		return apierrors.NewNotFound(gvr.GroupResource(), name)
 	}
 
+	deletedObj := obj.Object
+	if t.options.StrictResourceVersions {
+		if preconditions := deleteOpts.Preconditions; preconditions != nil && preconditions.ResourceVersion != nil {
+			if err := checkPrecondition(gvr.GroupResource(), name, *preconditions.ResourceVersion, obj); err != nil {
+				return err
+			}
+		}
+		// Like the API server, the deletion gets its own resource version.
+		resourceVersion := t.resourceVersions[gvr] + 1
+		t.resourceVersions[gvr] = resourceVersion
+		deletedObj, err = stampResourceVersion(obj.DeepCopyObject(), resourceVersion)
+		if err != nil {
+			return err
+		}
+		t.recordEvent(gvr, trackerEvent{
+			resourceVersion: resourceVersion,
+			eventType:       watch.Deleted,
+			key:             namespacedName,
+			object:          deletedObj,
+			oldObject:       obj.Object,
+		})
+	}
+
 	delete(objs, namespacedName)
 	for _, w := range t.getWatches(gvr, ns) {
-		w.Delete(obj.DeepCopyObject())
+		if w.matches(obj.Object) {
+			w.Delete(deletedObj.DeepCopyObject())
+		}
 	}
 	return nil
 }
@@ -746,8 +923,13 @@ var _ ObjectTracker = &managedFieldObjectTracker{}
 // NewFieldManagedObjectTracker returns an ObjectTracker that can be used to keep track
 // of objects and managed fields for the fake clientset. Mostly useful for unit tests.
 func NewFieldManagedObjectTracker(scheme *runtime.Scheme, decoder runtime.Decoder, typeConverter managedfields.TypeConverter) ObjectTracker {
+	return NewFieldManagedObjectTrackerWithOptions(scheme, decoder, typeConverter, ObjectTrackerOptions{})
+}
+
+// NewFieldManagedObjectTrackerWithOptions is like NewFieldManagedObjectTracker, with additional options.
+func NewFieldManagedObjectTrackerWithOptions(scheme *runtime.Scheme, decoder runtime.Decoder, typeConverter managedfields.TypeConverter, options ObjectTrackerOptions) ObjectTracker {
 	return &managedFieldObjectTracker{
-		ObjectTracker:   NewObjectTracker(scheme, decoder),
+		ObjectTracker:   NewObjectTrackerWithOptions(scheme, decoder, options),
 		scheme:          scheme,
 		objectConverter: scheme,
 		mapper: func() meta.RESTMapper {
@@ -914,6 +1096,21 @@ func (t *managedFieldObjectTracker) Apply(gvr schema.GroupVersionResource, apply
 	}
 }
 
+// IsWatchListSemanticsUnSupported forwards to the underlying ObjectTracker.
+func (t *managedFieldObjectTracker) IsWatchListSemanticsUnSupported() bool {
+	if unsupported, ok := t.ObjectTracker.(interface{ IsWatchListSemanticsUnSupported() bool }); ok {
+		return unsupported.IsWatchListSemanticsUnSupported()
+	}
+	return true
+}
+
+func (t *managedFieldObjectTracker) strictResourceVersions() bool {
+	if strict, ok := t.ObjectTracker.(interface{ strictResourceVersions() bool }); ok {
+		return strict.strictResourceVersions()
+	}
+	return false
+}
+
 func (t *managedFieldObjectTracker) fieldManagerFor(gvk schema.GroupVersionKind) (*managedfields.FieldManager, error) {
 	return managedfields.NewDefaultFieldManager(
 		t.typeConverter,
@@ -0,0 +1,254 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// DefaultWatchHistorySize is the number of events per resource which an
+// ObjectTracker with StrictResourceVersions retains if
+// ObjectTrackerOptions.WatchHistorySize is unset.
+const DefaultWatchHistorySize = 100
+
+// optimisticLockErrorMsg is the message of the Conflict error which the API
+// server returns for updates of outdated objects.
+const optimisticLockErrorMsg = "the object has been modified; please apply your changes to the latest version and try again"
+
+// ObjectTrackerOptions configures an ObjectTracker created by
+// NewObjectTrackerWithOptions or NewFieldManagedObjectTrackerWithOptions.
+type ObjectTrackerOptions struct {
+	// StrictResourceVersions makes the tracker handle resource versions like
+	// the API server does, which allows testing optimistic concurrency, for
+	// example code using retry.RetryOnConflict:
+	//
+	//   - Every write assigns a new, monotonically increasing
+	//     metadata.resourceVersion to the stored object, overwriting the one
+	//     that was passed in.
+	//   - Update and Patch fail with a Conflict error if the object carries a
+	//     resourceVersion which is not the one of the stored object. Delete does
+	//     the same for a resourceVersion precondition.
+	//   - List honors ResourceVersion and ResourceVersionMatch, including lists
+	//     of an exact, older resource version.
+	//   - Watch replays the events after a non-zero ResourceVersion and fails
+	//     with an Expired error once that resource version is no longer retained.
+	//
+	// By default, the content of tracked objects is never changed by the tracker.
+	StrictResourceVersions bool
+
+	// WatchHistorySize is the number of events per resource which are retained
+	// for watches and lists from older resource versions. Defaults to
+	// DefaultWatchHistorySize. Only used with StrictResourceVersions.
+	WatchHistorySize int
+}
+
+// trackerEvent is a change of a tracked object, retained for replaying watches.
+type trackerEvent struct {
+	resourceVersion int64
+	eventType       watch.EventType
+	key             types.NamespacedName
+	// object is the new object, or the final state of a deleted object.
+	object runtime.Object
+	// oldObject is the object before a modification or deletion.
+	oldObject runtime.Object
+}
+
+// eventHistory is the bounded list of the most recent events for a resource.
+type eventHistory struct {
+	events []trackerEvent
+	// oldestResourceVersion is the oldest resource version from which the
+	// retained events are complete.
+	oldestResourceVersion int64
+}
+
+// recordEvent adds an event to the history of gvr. The caller must hold the
+// tracker lock.
+func (t *tracker) recordEvent(gvr schema.GroupVersionResource, event trackerEvent) {
+	if !t.options.StrictResourceVersions {
+		return
+	}
+	history, ok := t.histories[gvr]
+	if !ok {
+		history = &eventHistory{oldestResourceVersion: 1}
+		t.histories[gvr] = history
+	}
+	history.events = append(history.events, event)
+	if overflow := len(history.events) - t.options.WatchHistorySize; overflow > 0 {
+		history.oldestResourceVersion = history.events[overflow-1].resourceVersion
+		history.events = append([]trackerEvent(nil), history.events[overflow:]...)
+	}
+}
+
+// eventsSince returns the events of gvr after resourceVersion. The caller must
+// hold the tracker lock.
+func (t *tracker) eventsSince(gvr schema.GroupVersionResource, resourceVersion int64) ([]trackerEvent, error) {
+	if err := t.checkResourceVersionRange(gvr, resourceVersion); err != nil {
+		return nil, err
+	}
+	history := t.histories[gvr]
+	if history == nil {
+		return nil, nil
+	}
+	for i, event := range history.events {
+		if event.resourceVersion > resourceVersion {
+			return history.events[i:], nil
+		}
+	}
+	return nil, nil
+}
+
+// checkResourceVersionRange returns the errors of the API server for resource
+// versions which are not retained anymore or which are not reached yet.
+// The caller must hold the tracker lock.
+func (t *tracker) checkResourceVersionRange(gvr schema.GroupVersionResource, resourceVersion int64) error {
+	current, ok := t.resourceVersions[gvr]
+	if !ok {
+		current = 1
+	}
+	if resourceVersion > current {
+		return newTooLargeResourceVersionError(resourceVersion, current)
+	}
+	oldest := int64(1)
+	if history := t.histories[gvr]; history != nil {
+		oldest = history.oldestResourceVersion
+	}
+	if resourceVersion < oldest {
+		return apierrors.NewResourceExpired(fmt.Sprintf("too old resource version: %d (%d)", resourceVersion, oldest))
+	}
+	return nil
+}
+
+// objectsAt returns the objects of gvr as they were at resourceVersion by
+// rolling back the events which happened after it. The caller must hold the
+// tracker lock.
+func (t *tracker) objectsAt(gvr schema.GroupVersionResource, resourceVersion int64) (map[types.NamespacedName]versionedObject, error) {
+	events, err := t.eventsSince(gvr, resourceVersion)
+	if err != nil {
+		return nil, err
+	}
+	objs := make(map[types.NamespacedName]versionedObject, len(t.objects[gvr]))
+	for key, obj := range t.objects[gvr] {
+		objs[key] = obj
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		switch event.eventType {
+		case watch.Added:
+			delete(objs, event.key)
+		case watch.Modified, watch.Deleted:
+			oldResourceVersion, err := objectResourceVersion(event.oldObject)
+			if err != nil {
+				return nil, err
+			}
+			objs[event.key] = versionedObject{oldResourceVersion, event.oldObject}
+		}
+	}
+	return objs, nil
+}
+
+// listResourceVersion determines from opts which resource version a List has
+// to return. Zero means the most recent one.
+func (t *tracker) listResourceVersion(gvr schema.GroupVersionResource, opts metav1.ListOptions) (int64, error) {
+	if !t.options.StrictResourceVersions {
+		return 0, nil
+	}
+	if opts.ResourceVersionMatch != "" && opts.ResourceVersion == "" {
+		return 0, apierrors.NewBadRequest("resourceVersionMatch is forbidden unless resourceVersion is provided")
+	}
+	if opts.ResourceVersion == "" || opts.ResourceVersion == "0" {
+		if opts.ResourceVersionMatch == metav1.ResourceVersionMatchExact {
+			return 0, apierrors.NewBadRequest("resourceVersionMatch \"exact\" is forbidden for resourceVersion \"0\"")
+		}
+		return 0, nil
+	}
+	resourceVersion, err := parseResourceVersion(opts.ResourceVersion)
+	if err != nil {
+		return 0, err
+	}
+	switch opts.ResourceVersionMatch {
+	case "", metav1.ResourceVersionMatchNotOlderThan:
+		// The most recent state is never older than any resource version,
+		// it just must have been reached already.
+		if current := t.resourceVersions[gvr]; resourceVersion > max(current, 1) {
+			return 0, newTooLargeResourceVersionError(resourceVersion, max(current, 1))
+		}
+		return 0, nil
+	case metav1.ResourceVersionMatchExact:
+		return resourceVersion, nil
+	default:
+		return 0, apierrors.NewBadRequest(fmt.Sprintf("unsupported resourceVersionMatch %q", opts.ResourceVersionMatch))
+	}
+}
+
+// checkPrecondition fails with a Conflict error if the resource version which
+// the client has seen does not match the one of the stored object. An empty
+// resource version is an unconditional write.
+func checkPrecondition(gr schema.GroupResource, name, resourceVersion string, stored versionedObject) error {
+	if resourceVersion == "" || resourceVersion == strconv.FormatInt(stored.resourceVersion, 10) {
+		return nil
+	}
+	return apierrors.NewConflict(gr, name, errors.New(optimisticLockErrorMsg))
+}
+
+// stampResourceVersion sets the resource version assigned by the tracker in obj.
+func stampResourceVersion(obj runtime.Object, resourceVersion int64) (runtime.Object, error) {
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	objMeta.SetResourceVersion(strconv.FormatInt(resourceVersion, 10))
+	return obj, nil
+}
+
+func objectResourceVersion(obj runtime.Object) (int64, error) {
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return 0, err
+	}
+	return parseResourceVersion(objMeta.GetResourceVersion())
+}
+
+func parseResourceVersion(resourceVersion string) (int64, error) {
+	rv, err := strconv.ParseInt(resourceVersion, 10, 64)
+	if err != nil {
+		return 0, apierrors.NewBadRequest(fmt.Sprintf("invalid resource version %q: %v", resourceVersion, err))
+	}
+	return rv, nil
+}
+
+// newTooLargeResourceVersionError returns the error of the API server for
+// requests of a resource version which it has not reached yet.
+func newTooLargeResourceVersionError(resourceVersion, current int64) error {
+	err := apierrors.NewTimeoutError(fmt.Sprintf("Too large resource version: %d, current: %d", resourceVersion, current), 1)
+	err.ErrStatus.Details.Causes = []metav1.StatusCause{
+		{
+			Type:    metav1.CauseTypeResourceVersionTooLarge,
+			Message: "Too large resource version",
+		},
+	}
+	return err
+}
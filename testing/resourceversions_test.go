@@ -0,0 +1,195 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	serializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/utils/ptr"
+)
+
+func newStrictTestTracker(historySize int) ObjectTracker {
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(podGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(podGVK.GroupVersion().WithKind("PodList"), &unstructured.UnstructuredList{})
+	codecs := serializer.NewCodecFactory(scheme)
+	return NewObjectTrackerWithOptions(scheme, codecs.UniversalDecoder(), ObjectTrackerOptions{
+		StrictResourceVersions: true,
+		WatchHistorySize:       historySize,
+	})
+}
+
+func getPod(t *testing.T, o ObjectTracker, name string) *unstructured.Unstructured {
+	t.Helper()
+	obj, err := o.Get(podGVR, "default", name)
+	require.NoError(t, err)
+	return obj.(*unstructured.Unstructured)
+}
+
+func TestStrictResourceVersionsUpdate(t *testing.T) {
+	o := newStrictTestTracker(0)
+	pod := newSelectorTestPod("pod", "web", "", "Pending")
+	pod.SetResourceVersion("42")
+	require.NoError(t, o.Create(podGVR, pod, "default"))
+	// The resource version passed in is replaced by the one of the tracker.
+	assert.Equal(t, "2", getPod(t, o, "pod").GetResourceVersion())
+
+	first := getPod(t, o, "pod")
+	second := getPod(t, o, "pod")
+	first.SetLabels(map[string]string{"app": "db"})
+	require.NoError(t, o.Update(podGVR, first, "default"))
+	assert.Equal(t, "3", getPod(t, o, "pod").GetResourceVersion())
+
+	// The second copy is outdated now.
+	second.SetLabels(map[string]string{"app": "cache"})
+	err := o.Update(podGVR, second, "default")
+	assert.True(t, errors.IsConflict(err), "expected Conflict, got %v", err)
+	err = o.Patch(podGVR, second, "default")
+	assert.True(t, errors.IsConflict(err), "expected Conflict, got %v", err)
+
+	// Updates without a resource version are unconditional.
+	second.SetResourceVersion("")
+	require.NoError(t, o.Update(podGVR, second, "default"))
+	assert.Equal(t, map[string]string{"app": "cache"}, getPod(t, o, "pod").GetLabels())
+
+	err = o.Delete(podGVR, "default", "pod", metav1.DeleteOptions{Preconditions: &metav1.Preconditions{ResourceVersion: ptr.To("3")}})
+	assert.True(t, errors.IsConflict(err), "expected Conflict, got %v", err)
+	require.NoError(t, o.Delete(podGVR, "default", "pod", metav1.DeleteOptions{Preconditions: &metav1.Preconditions{ResourceVersion: ptr.To("4")}}))
+}
+
+func TestStrictResourceVersionsPatchReaction(t *testing.T) {
+	for name, strict := range map[string]bool{"strict": true, "traditional": false} {
+		t.Run(name, func(t *testing.T) {
+			o := newStrictTestTracker(0)
+			if !strict {
+				scheme := runtime.NewScheme()
+				scheme.AddKnownTypeWithName(podGVK, &unstructured.Unstructured{})
+				o = NewObjectTracker(scheme, serializer.NewCodecFactory(scheme).UniversalDecoder())
+			}
+			require.NoError(t, o.Add(newSelectorTestPod("pod", "web", "", "Pending")))
+
+			action := NewPatchAction(podGVR, "default", "pod", types.MergePatchType, []byte(`{"metadata":{"labels":{"app":"db"}}}`))
+			handled, obj, err := ObjectReaction(o)(action)
+			require.True(t, handled)
+			require.NoError(t, err)
+			patched := obj.(*unstructured.Unstructured)
+			assert.Equal(t, map[string]string{"app": "db"}, patched.GetLabels())
+			// Only strict trackers assign resource versions to objects.
+			assert.Equal(t, getPod(t, o, "pod").GetResourceVersion(), patched.GetResourceVersion())
+			if strict {
+				assert.Equal(t, "3", patched.GetResourceVersion())
+			}
+		})
+	}
+}
+
+func TestStrictResourceVersionsList(t *testing.T) {
+	o := newStrictTestTracker(3)
+	require.NoError(t, o.Add(newSelectorTestPod("a", "web", "", "Pending")))                      // 2
+	require.NoError(t, o.Add(newSelectorTestPod("b", "web", "", "Pending")))                      // 3
+	require.NoError(t, o.Update(podGVR, newSelectorTestPod("a", "db", "", "Running"), "default")) // 4
+	require.NoError(t, o.Delete(podGVR, "default", "b"))                                          // 5
+	require.NoError(t, o.Add(newSelectorTestPod("c", "web", "", "Pending")))                      // 6
+
+	list := func(opts metav1.ListOptions) (string, map[string]string, error) {
+		obj, err := o.List(podGVR, podGVK, "default", opts)
+		if err != nil {
+			return "", nil, err
+		}
+		listMeta, err := meta.ListAccessor(obj)
+		require.NoError(t, err)
+		items, err := meta.ExtractList(obj)
+		require.NoError(t, err)
+		apps := map[string]string{}
+		for _, item := range items {
+			pod := item.(*unstructured.Unstructured)
+			apps[pod.GetName()] = pod.GetLabels()["app"]
+		}
+		return listMeta.GetResourceVersion(), apps, nil
+	}
+
+	rv, apps, err := list(metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "6", rv)
+	assert.Equal(t, map[string]string{"a": "db", "c": "web"}, apps)
+
+	rv, apps, err = list(metav1.ListOptions{ResourceVersion: "3", ResourceVersionMatch: metav1.ResourceVersionMatchExact})
+	require.NoError(t, err)
+	assert.Equal(t, "3", rv)
+	assert.Equal(t, map[string]string{"a": "web", "b": "web"}, apps)
+
+	rv, _, err = list(metav1.ListOptions{ResourceVersion: "4", ResourceVersionMatch: metav1.ResourceVersionMatchNotOlderThan})
+	require.NoError(t, err)
+	assert.Equal(t, "6", rv)
+
+	// Only the last three events are retained.
+	_, _, err = list(metav1.ListOptions{ResourceVersion: "2", ResourceVersionMatch: metav1.ResourceVersionMatchExact})
+	assert.True(t, errors.IsResourceExpired(err), "expected Expired, got %v", err)
+	_, _, err = list(metav1.ListOptions{ResourceVersion: "7"})
+	assert.True(t, errors.IsTimeout(err) && errors.HasStatusCause(err, metav1.CauseTypeResourceVersionTooLarge), "expected too large resource version, got %v", err)
+	_, _, err = list(metav1.ListOptions{ResourceVersionMatch: metav1.ResourceVersionMatchExact})
+	assert.True(t, errors.IsBadRequest(err), "expected BadRequest, got %v", err)
+}
+
+func TestStrictResourceVersionsWatch(t *testing.T) {
+	o := newStrictTestTracker(3)
+	require.NoError(t, o.Add(newSelectorTestPod("a", "web", "", "Pending")))                       // 2
+	require.NoError(t, o.Add(newSelectorTestPod("b", "db", "", "Pending")))                        // 3
+	require.NoError(t, o.Update(podGVR, newSelectorTestPod("a", "web", "", "Running"), "default")) // 4
+	require.NoError(t, o.Delete(podGVR, "default", "a"))                                           // 5
+
+	_, err := o.Watch(podGVR, "default", metav1.ListOptions{ResourceVersion: "1"})
+	assert.True(t, errors.IsResourceExpired(err), "expected Expired, got %v", err)
+
+	w, err := o.Watch(podGVR, "default", metav1.ListOptions{ResourceVersion: "2", LabelSelector: "app=web"})
+	require.NoError(t, err)
+	defer w.Stop()
+	require.NoError(t, o.Add(newSelectorTestPod("c", "web", "", "Pending"))) // 6
+
+	expected := []struct {
+		eventType       watch.EventType
+		name            string
+		resourceVersion string
+	}{
+		{watch.Modified, "a", "4"},
+		{watch.Deleted, "a", "5"},
+		{watch.Added, "c", "6"},
+	}
+	for _, e := range expected {
+		event := <-w.ResultChan()
+		obj := event.Object.(*unstructured.Unstructured)
+		assert.Equal(t, e.eventType, event.Type)
+		assert.Equal(t, e.name, obj.GetName())
+		assert.Equal(t, e.resourceVersion, obj.GetResourceVersion())
+	}
+	select {
+	case event := <-w.ResultChan():
+		t.Errorf("unexpected event %v", event)
+	default:
+	}
+}
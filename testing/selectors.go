@@ -0,0 +1,371 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// SelectableFieldsRegistry is implemented by the ObjectTrackers of this package.
+// The trackers evaluate field selectors in List and Watch against metadata.name,
+// metadata.namespace and the well-known selectable fields of built-in resources,
+// such as spec.nodeName and status.phase of pods. For resources whose selectable
+// fields are all known, like nodes or registered resources, selecting by any
+// other field is rejected with a BadRequest error, like the API server does.
+// For all other resources, other field labels are looked up as the paths of
+// their values, so a selector by spec.template.spec.nodeName matches that field.
+type SelectableFieldsRegistry interface {
+	// RegisterSelectableFields makes the given fields selectable for the resource,
+	// for example the selectableFields of a CustomResourceDefinition. Fields are
+	// given as JSON paths like ".spec.color", the field label used in selectors is
+	// the path without the leading dot. Afterwards, selecting the resource by any
+	// other field is rejected.
+	RegisterSelectableFields(gvr schema.GroupVersionResource, jsonPaths ...string)
+}
+
+// builtinSelectableFields maps the field labels which the API server supports in
+// field selectors for built-in resources to the paths of their values. Only the
+// lists of completeSelectableFields are exhaustive.
+var builtinSelectableFields = map[schema.GroupResource]map[string]string{
+	{Resource: "pods"}: {
+		"spec.nodeName":            "spec.nodeName",
+		"spec.hostNetwork":         "spec.hostNetwork",
+		"spec.restartPolicy":       "spec.restartPolicy",
+		"spec.schedulerName":       "spec.schedulerName",
+		"spec.serviceAccountName":  "spec.serviceAccountName",
+		"status.phase":             "status.phase",
+		"status.podIP":             "status.podIP",
+		"status.nominatedNodeName": "status.nominatedNodeName",
+	},
+	{Resource: "nodes"}: {
+		"spec.unschedulable": "spec.unschedulable",
+	},
+	{Resource: "namespaces"}: {
+		"status.phase": "status.phase",
+	},
+	{Resource: "services"}: {
+		"spec.clusterIP": "spec.clusterIP",
+		"spec.type":      "spec.type",
+	},
+	{Resource: "secrets"}: {
+		"type": "type",
+	},
+	{Resource: "events"}: {
+		"involvedObject.kind":            "involvedObject.kind",
+		"involvedObject.namespace":       "involvedObject.namespace",
+		"involvedObject.name":            "involvedObject.name",
+		"involvedObject.uid":             "involvedObject.uid",
+		"involvedObject.apiVersion":      "involvedObject.apiVersion",
+		"involvedObject.resourceVersion": "involvedObject.resourceVersion",
+		"involvedObject.fieldPath":       "involvedObject.fieldPath",
+		"reason":                         "reason",
+		"reportingComponent":             "reportingComponent",
+		"source":                         "source.component",
+		"type":                           "type",
+	},
+	{Group: "events.k8s.io", Resource: "events"}: {
+		"regarding.kind":            "regarding.kind",
+		"regarding.namespace":       "regarding.namespace",
+		"regarding.name":            "regarding.name",
+		"regarding.uid":             "regarding.uid",
+		"regarding.apiVersion":      "regarding.apiVersion",
+		"regarding.resourceVersion": "regarding.resourceVersion",
+		"regarding.fieldPath":       "regarding.fieldPath",
+		"reason":                    "reason",
+		"reportingController":       "reportingController",
+		"type":                      "type",
+	},
+}
+
+// builtinFieldDefaults are the values of the boolean field labels of built-in
+// resources when the field is unset. The API server derives them from typed
+// objects, where an unset boolean is false.
+var builtinFieldDefaults = map[schema.GroupResource]map[string]string{
+	{Resource: "pods"}: {
+		"spec.hostNetwork": "false",
+	},
+	{Resource: "nodes"}: {
+		"spec.unschedulable": "false",
+	},
+}
+
+// completeSelectableFields are the built-in resources for which
+// builtinSelectableFields lists all field labels that the API server supports.
+var completeSelectableFields = sets.New[schema.GroupResource](
+	schema.GroupResource{Resource: "nodes"},
+	schema.GroupResource{Resource: "namespaces"},
+	schema.GroupResource{Resource: "services"},
+	schema.GroupResource{Resource: "secrets"},
+	schema.GroupResource{Resource: "events"},
+)
+
+// selectionPredicate is the parsed form of the selectors in ListOptions.
+type selectionPredicate struct {
+	label labels.Selector
+	field fields.Selector
+	// selectableFields maps the supported field labels to the paths of their values.
+	selectableFields map[string]string
+	// defaults are the values of field labels whose field is unset, "" if not listed.
+	defaults map[string]string
+}
+
+// newSelectionPredicate parses the selectors in opts. selectableFields are the
+// resource specific field labels which can be used in addition to metadata.name
+// and metadata.namespace. If they are complete, other field labels are
+// rejected, otherwise they are used as the paths of their values.
+func newSelectionPredicate(gr schema.GroupResource, opts metav1.ListOptions, selectableFields map[string]string, complete bool) (*selectionPredicate, error) {
+	label, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid label selector %q: %v", opts.LabelSelector, err))
+	}
+	field, err := fields.ParseSelector(opts.FieldSelector)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid field selector %q: %v", opts.FieldSelector, err))
+	}
+	for _, requirement := range field.Requirements() {
+		switch requirement.Field {
+		case "metadata.name", "metadata.namespace":
+			continue
+		}
+		if _, ok := selectableFields[requirement.Field]; ok {
+			continue
+		}
+		if complete {
+			return nil, apierrors.NewBadRequest(fmt.Sprintf("field label not supported for %s: %s", gr, requirement.Field))
+		}
+		// Don't modify the shared map of the resource.
+		selectableFields = maps.Clone(selectableFields)
+		if selectableFields == nil {
+			selectableFields = map[string]string{}
+		}
+		selectableFields[requirement.Field] = requirement.Field
+	}
+	return &selectionPredicate{
+		label:            label,
+		field:            field,
+		selectableFields: selectableFields,
+		defaults:         builtinFieldDefaults[gr],
+	}, nil
+}
+
+// matches returns true if obj matches both the label and the field selector.
+// A nil predicate matches everything.
+func (p *selectionPredicate) matches(obj runtime.Object) (bool, error) {
+	if p == nil {
+		return true, nil
+	}
+	if p.label.Empty() && p.field.Empty() {
+		return true, nil
+	}
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return false, err
+	}
+	if !p.label.Matches(labels.Set(objMeta.GetLabels())) {
+		return false, nil
+	}
+	if p.field.Empty() {
+		return true, nil
+	}
+
+	fieldSet := fields.Set{
+		"metadata.name":      objMeta.GetName(),
+		"metadata.namespace": objMeta.GetNamespace(),
+	}
+	if len(p.selectableFields) > 0 {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return false, err
+		}
+		for label, path := range p.selectableFields {
+			value, found, err := unstructured.NestedFieldNoCopy(content, strings.Split(path, ".")...)
+			if err != nil {
+				return false, err
+			}
+			if found && value != nil {
+				fieldSet[label] = fmt.Sprint(value)
+			} else {
+				fieldSet[label] = p.defaults[label]
+			}
+		}
+	}
+	return p.field.Matches(fieldSet), nil
+}
+
+// selectableFieldsFor returns the field labels, beyond metadata.name and
+// metadata.namespace, that can be used in field selectors for gvr and whether
+// they are all the field labels of the resource.
+// The caller must hold the tracker lock.
+func (t *tracker) selectableFieldsFor(gvr schema.GroupVersionResource) (map[string]string, bool) {
+	if selectableFields, ok := t.selectableFields[gvr]; ok {
+		return selectableFields, true
+	}
+	return builtinSelectableFields[gvr.GroupResource()], completeSelectableFields.Has(gvr.GroupResource())
+}
+
+// RegisterSelectableFields implements SelectableFieldsRegistry.
+func (t *tracker) RegisterSelectableFields(gvr schema.GroupVersionResource, jsonPaths ...string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	selectableFields := make(map[string]string, len(jsonPaths))
+	for label, path := range builtinSelectableFields[gvr.GroupResource()] {
+		selectableFields[label] = path
+	}
+	for _, jsonPath := range jsonPaths {
+		path := strings.TrimPrefix(jsonPath, ".")
+		selectableFields[path] = path
+	}
+	t.selectableFields[gvr] = selectableFields
+}
+
+// RegisterSelectableFields implements SelectableFieldsRegistry if the
+// underlying ObjectTracker does.
+func (t *managedFieldObjectTracker) RegisterSelectableFields(gvr schema.GroupVersionResource, jsonPaths ...string) {
+	if registry, ok := t.ObjectTracker.(SelectableFieldsRegistry); ok {
+		registry.RegisterSelectableFields(gvr, jsonPaths...)
+	}
+}
+
+// selectingWatcher is a fake watcher which only receives events for objects
+// that match the selectors of its Watch call.
+//
+// The events which Watch delivers before the watcher is started, like the
+// initial events, are buffered without limit, because there can be more of
+// them than the channel of a RaceFreeFakeWatcher holds. A goroutine sends them
+// before the events which the watcher receives later.
+type selectingWatcher struct {
+	*watch.RaceFreeFakeWatcher
+	predicate *selectionPredicate
+
+	// initial and started are protected by the tracker lock.
+	initial []watch.Event
+	started bool
+
+	// result and stopCh are only set if there were initial events.
+	result   chan watch.Event
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// Action sends an event, or buffers it until start is called.
+func (w *selectingWatcher) Action(eventType watch.EventType, obj runtime.Object) {
+	if !w.started {
+		w.initial = append(w.initial, watch.Event{Type: eventType, Object: obj})
+		return
+	}
+	w.RaceFreeFakeWatcher.Action(eventType, obj)
+}
+
+// Add sends an add event.
+func (w *selectingWatcher) Add(obj runtime.Object) {
+	w.Action(watch.Added, obj)
+}
+
+// Modify sends a modify event.
+func (w *selectingWatcher) Modify(obj runtime.Object) {
+	w.Action(watch.Modified, obj)
+}
+
+// Delete sends a delete event.
+func (w *selectingWatcher) Delete(lastValue runtime.Object) {
+	w.Action(watch.Deleted, lastValue)
+}
+
+// start sends the buffered events, followed by all later events. The caller
+// must hold the tracker lock.
+func (w *selectingWatcher) start() {
+	w.started = true
+	if len(w.initial) == 0 {
+		return
+	}
+	initial := w.initial
+	w.initial = nil
+	w.result = make(chan watch.Event)
+	w.stopCh = make(chan struct{})
+	later := w.RaceFreeFakeWatcher.ResultChan()
+	go func() {
+		defer close(w.result)
+		for _, event := range initial {
+			select {
+			case w.result <- event:
+			case <-w.stopCh:
+				return
+			}
+		}
+		for event := range later {
+			select {
+			case w.result <- event:
+			case <-w.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// ResultChan implements watch.Interface.
+func (w *selectingWatcher) ResultChan() <-chan watch.Event {
+	if w.result != nil {
+		return w.result
+	}
+	return w.RaceFreeFakeWatcher.ResultChan()
+}
+
+// Stop implements watch.Interface.
+func (w *selectingWatcher) Stop() {
+	if w.stopCh != nil {
+		w.stopOnce.Do(func() { close(w.stopCh) })
+	}
+	w.RaceFreeFakeWatcher.Stop()
+}
+
+// matches returns true if obj matches the selectors of the watcher. Objects
+// which cannot be evaluated are delivered, as they were before selectors
+// were supported.
+func (w *selectingWatcher) matches(obj runtime.Object) bool {
+	matches, err := w.predicate.matches(obj)
+	return matches || err != nil
+}
+
+// modify delivers an update from oldObj to newObj the way the API server does
+// for a watch with selectors: objects which start matching are added, objects
+// which stop matching are deleted.
+func (w *selectingWatcher) modify(oldObj, newObj runtime.Object) {
+	oldMatches, newMatches := w.matches(oldObj), w.matches(newObj)
+	switch {
+	case oldMatches && newMatches:
+		w.Modify(newObj)
+	case newMatches:
+		w.Add(newObj)
+	case oldMatches:
+		w.Delete(newObj)
+	}
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	serializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+var (
+	podGVR    = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	podGVK    = schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	nodeGVR   = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+	nodeGVK   = schema.GroupVersionKind{Version: "v1", Kind: "Node"}
+	widgetGVR = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	widgetGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+)
+
+func newSelectorTestTracker() ObjectTracker {
+	scheme := runtime.NewScheme()
+	for _, gvk := range []schema.GroupVersionKind{podGVK, nodeGVK, widgetGVK} {
+		scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+	}
+	codecs := serializer.NewCodecFactory(scheme)
+	return NewObjectTracker(scheme, codecs.UniversalDecoder())
+}
+
+func newSelectorTestPod(name, app, nodeName, phase string) *unstructured.Unstructured {
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"nodeName": nodeName},
+		"status": map[string]interface{}{"phase": phase},
+	}}
+	pod.SetGroupVersionKind(podGVK)
+	pod.SetNamespace("default")
+	pod.SetName(name)
+	pod.SetLabels(map[string]string{"app": app})
+	return pod
+}
+
+func listNames(t *testing.T, o ObjectTracker, gvr schema.GroupVersionResource, gvk schema.GroupVersionKind, opts metav1.ListOptions) []string {
+	t.Helper()
+	list, err := o.List(gvr, gvk, "default", opts)
+	require.NoError(t, err)
+	items, err := meta.ExtractList(list)
+	require.NoError(t, err)
+	var names []string
+	for _, item := range items {
+		accessor, err := meta.Accessor(item)
+		require.NoError(t, err)
+		names = append(names, accessor.GetName())
+	}
+	return names
+}
+
+func TestListWithSelectors(t *testing.T) {
+	o := newSelectorTestTracker()
+	require.NoError(t, o.Add(newSelectorTestPod("a", "web", "node-1", "Running")))
+	hostPod := newSelectorTestPod("b", "web", "node-2", "Pending")
+	require.NoError(t, unstructured.SetNestedField(hostPod.Object, true, "spec", "hostNetwork"))
+	require.NoError(t, o.Add(hostPod))
+	namedPod := newSelectorTestPod("c", "db", "node-1", "Running")
+	require.NoError(t, unstructured.SetNestedField(namedPod.Object, "node-1", "spec", "hostname"))
+	require.NoError(t, o.Add(namedPod))
+
+	testCases := []struct {
+		name     string
+		opts     metav1.ListOptions
+		expected []string
+	}{
+		{
+			name:     "no selectors",
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "label selector",
+			opts:     metav1.ListOptions{LabelSelector: "app=web"},
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "metadata.name",
+			opts:     metav1.ListOptions{FieldSelector: "metadata.name=b"},
+			expected: []string{"b"},
+		},
+		{
+			name:     "spec.nodeName",
+			opts:     metav1.ListOptions{FieldSelector: "spec.nodeName=node-1"},
+			expected: []string{"a", "c"},
+		},
+		{
+			name:     "status.phase",
+			opts:     metav1.ListOptions{FieldSelector: "status.phase!=Running"},
+			expected: []string{"b"},
+		},
+		{
+			name:     "spec.hostNetwork",
+			opts:     metav1.ListOptions{FieldSelector: "spec.hostNetwork=true"},
+			expected: []string{"b"},
+		},
+		{
+			name:     "unset spec.hostNetwork",
+			opts:     metav1.ListOptions{FieldSelector: "spec.hostNetwork=false"},
+			expected: []string{"a", "c"},
+		},
+		{
+			name:     "field without selectable field label",
+			opts:     metav1.ListOptions{FieldSelector: "spec.hostname=node-1"},
+			expected: []string{"c"},
+		},
+		{
+			name:     "label and field selector",
+			opts:     metav1.ListOptions{LabelSelector: "app=web", FieldSelector: "spec.nodeName=node-1,metadata.namespace=default"},
+			expected: []string{"a"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tc.expected, listNames(t, o, podGVR, podGVK, tc.opts))
+		})
+	}
+
+	for _, opts := range []metav1.ListOptions{
+		{FieldSelector: "spec.nodeName"},
+		{LabelSelector: "app in"},
+	} {
+		_, err := o.List(podGVR, podGVK, "default", opts)
+		assert.True(t, errors.IsBadRequest(err), "expected BadRequest for %+v, got %v", opts, err)
+		_, err = o.Watch(podGVR, "default", opts)
+		assert.True(t, errors.IsBadRequest(err), "expected BadRequest for %+v, got %v", opts, err)
+	}
+}
+
+func TestListNodesByUnschedulable(t *testing.T) {
+	o := newSelectorTestTracker()
+	for name, spec := range map[string]map[string]interface{}{
+		"a": {},
+		"b": {"unschedulable": true},
+		"c": {"unschedulable": false},
+	} {
+		node := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		node.SetGroupVersionKind(nodeGVK)
+		node.SetName(name)
+		require.NoError(t, o.Add(node))
+	}
+
+	list := func(fieldSelector string) []string {
+		t.Helper()
+		list, err := o.List(nodeGVR, nodeGVK, "", metav1.ListOptions{FieldSelector: fieldSelector})
+		require.NoError(t, err)
+		items, err := meta.ExtractList(list)
+		require.NoError(t, err)
+		var names []string
+		for _, item := range items {
+			accessor, err := meta.Accessor(item)
+			require.NoError(t, err)
+			names = append(names, accessor.GetName())
+		}
+		return names
+	}
+	assert.ElementsMatch(t, []string{"a", "c"}, list("spec.unschedulable=false"))
+	assert.ElementsMatch(t, []string{"b"}, list("spec.unschedulable=true"))
+	assert.ElementsMatch(t, []string{"b"}, list("spec.unschedulable!=false"))
+}
+
+func TestRegisterSelectableFields(t *testing.T) {
+	o := newSelectorTestTracker()
+	for name, color := range map[string]string{"a": "red", "b": "blue"} {
+		widget := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"color": color},
+		}}
+		widget.SetGroupVersionKind(widgetGVK)
+		widget.SetNamespace("default")
+		widget.SetName(name)
+		require.NoError(t, o.Add(widget))
+	}
+
+	// Without registration, fields are selected by their path.
+	opts := metav1.ListOptions{FieldSelector: "spec.color=red"}
+	assert.Equal(t, []string{"a"}, listNames(t, o, widgetGVR, widgetGVK, opts))
+	assert.Empty(t, listNames(t, o, widgetGVR, widgetGVK, metav1.ListOptions{FieldSelector: "spec.size=1"}))
+
+	registry, ok := o.(SelectableFieldsRegistry)
+	require.True(t, ok, "tracker does not implement SelectableFieldsRegistry")
+	registry.RegisterSelectableFields(widgetGVR, ".spec.color")
+	assert.Equal(t, []string{"a"}, listNames(t, o, widgetGVR, widgetGVK, opts))
+
+	// Registered fields are all the selectable fields of the resource.
+	opts = metav1.ListOptions{FieldSelector: "spec.size=1"}
+	_, err := o.List(widgetGVR, widgetGVK, "default", opts)
+	assert.True(t, errors.IsBadRequest(err), "expected BadRequest after registration, got %v", err)
+	_, err = o.Watch(widgetGVR, "default", opts)
+	assert.True(t, errors.IsBadRequest(err), "expected BadRequest after registration, got %v", err)
+}
+
+func TestWatchWithSelectors(t *testing.T) {
+	o := newSelectorTestTracker()
+	require.NoError(t, o.Add(newSelectorTestPod("existing-match", "web", "node-1", "Running")))
+	require.NoError(t, o.Add(newSelectorTestPod("existing-other", "web", "node-2", "Running")))
+
+	w, err := o.Watch(podGVR, "default", metav1.ListOptions{LabelSelector: "app=web", FieldSelector: "spec.nodeName=node-1"})
+	require.NoError(t, err)
+	defer w.Stop()
+
+	pod := newSelectorTestPod("pod", "web", "node-2", "Pending")
+	require.NoError(t, o.Create(podGVR, pod, "default"))
+	// Scheduled onto the watched node: the pod starts to match.
+	pod = newSelectorTestPod("pod", "web", "node-1", "Pending")
+	require.NoError(t, o.Update(podGVR, pod, "default"))
+	pod = newSelectorTestPod("pod", "web", "node-1", "Running")
+	require.NoError(t, o.Update(podGVR, pod, "default"))
+	// Relabeled: the pod stops matching.
+	pod = newSelectorTestPod("pod", "db", "node-1", "Running")
+	require.NoError(t, o.Update(podGVR, pod, "default"))
+	require.NoError(t, o.Delete(podGVR, "default", "pod"))
+	require.NoError(t, o.Delete(podGVR, "default", "existing-match"))
+
+	expected := []struct {
+		eventType watch.EventType
+		name      string
+		phase     string
+	}{
+		{watch.Added, "existing-match", "Running"},
+		{watch.Added, "pod", "Pending"},
+		{watch.Modified, "pod", "Running"},
+		{watch.Deleted, "pod", "Running"},
+		{watch.Deleted, "existing-match", "Running"},
+	}
+	for _, e := range expected {
+		event := <-w.ResultChan()
+		obj := event.Object.(*unstructured.Unstructured)
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		assert.Equal(t, e.eventType, event.Type)
+		assert.Equal(t, e.name, obj.GetName())
+		assert.Equal(t, e.phase, phase)
+	}
+	select {
+	case event := <-w.ResultChan():
+		t.Errorf("unexpected event %v", event)
+	default:
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// validateSendInitialEvents returns whether a watch has to start with the
+// initial events and rejects the combinations of options which the API server
+// rejects as well.
+func validateSendInitialEvents(opts metav1.ListOptions) (bool, error) {
+	if opts.SendInitialEvents == nil || !*opts.SendInitialEvents {
+		return false, nil
+	}
+	if !opts.AllowWatchBookmarks {
+		return false, apierrors.NewBadRequest("sendInitialEvents requires setting allowWatchBookmarks to true")
+	}
+	if opts.ResourceVersionMatch != metav1.ResourceVersionMatchNotOlderThan {
+		return false, apierrors.NewBadRequest(fmt.Sprintf("sendInitialEvents requires setting resourceVersionMatch to %s", metav1.ResourceVersionMatchNotOlderThan))
+	}
+	return true, nil
+}
+
+// newInitialEventsEndBookmark returns the bookmark which ends the initial
+// events of a watch. It carries the current resource version and the
+// k8s.io/initial-events-end annotation. The caller must hold the tracker lock.
+func (t *tracker) newInitialEventsEndBookmark(gvr schema.GroupVersionResource) (runtime.Object, error) {
+	obj, err := t.newObjectFor(gvr)
+	if err != nil {
+		return nil, err
+	}
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	objMeta.SetResourceVersion(strconv.FormatInt(max(t.resourceVersions[gvr], 1), 10))
+	objMeta.SetAnnotations(map[string]string{metav1.InitialEventsAnnotationKey: "true"})
+	return obj, nil
+}
+
+// newObjectFor returns an empty object of the type which is stored for gvr,
+// because watchers like the Reflector drop events with any other type.
+// The caller must hold the tracker lock.
+func (t *tracker) newObjectFor(gvr schema.GroupVersionResource) (runtime.Object, error) {
+	// The type of the tracked objects is the most accurate, for example it is
+	// unstructured for the dynamic fake and PartialObjectMetadata for the
+	// metadata fake.
+	for _, stored := range t.objects[gvr] {
+		if _, ok := stored.Object.(*unstructured.Unstructured); ok {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(stored.GetObjectKind().GroupVersionKind())
+			return obj, nil
+		}
+		if objType := reflect.TypeOf(stored.Object); objType.Kind() == reflect.Pointer {
+			if obj, ok := reflect.New(objType.Elem()).Interface().(runtime.Object); ok {
+				obj.GetObjectKind().SetGroupVersionKind(stored.GetObjectKind().GroupVersionKind())
+				return obj, nil
+			}
+		}
+	}
+
+	// Otherwise look for the kind of the resource in the scheme.
+	if knownTypes, ok := t.scheme.(interface {
+		AllKnownTypes() map[schema.GroupVersionKind]reflect.Type
+	}); ok {
+		for gvk := range knownTypes.AllKnownTypes() {
+			if gvk.GroupVersion() != gvr.GroupVersion() {
+				continue
+			}
+			if plural, _ := meta.UnsafeGuessKindToResource(gvk); plural != gvr {
+				continue
+			}
+			obj, err := t.scheme.New(gvk)
+			if err != nil {
+				return nil, err
+			}
+			obj.GetObjectKind().SetGroupVersionKind(gvk)
+			return obj, nil
+		}
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(gvr.GroupVersion().String())
+	return obj, nil
+}
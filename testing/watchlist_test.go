@@ -0,0 +1,158 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	clientfeatures "k8s.io/client-go/features"
+	clientfeaturestesting "k8s.io/client-go/features/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/watchlist"
+	"k8s.io/utils/ptr"
+)
+
+func TestWatchSendInitialEvents(t *testing.T) {
+	o := newStrictTestTracker(0)
+	require.NoError(t, o.Add(newSelectorTestPod("a", "web", "", "Pending"))) // 2
+	require.NoError(t, o.Add(newSelectorTestPod("b", "db", "", "Pending")))  // 3
+
+	for _, opts := range []metav1.ListOptions{
+		{SendInitialEvents: ptr.To(true), ResourceVersionMatch: metav1.ResourceVersionMatchNotOlderThan},
+		{SendInitialEvents: ptr.To(true), AllowWatchBookmarks: true},
+	} {
+		_, err := o.Watch(podGVR, "default", opts)
+		assert.True(t, errors.IsBadRequest(err), "expected BadRequest for %+v, got %v", opts, err)
+	}
+
+	w, err := o.Watch(podGVR, "default", metav1.ListOptions{
+		SendInitialEvents:    ptr.To(true),
+		AllowWatchBookmarks:  true,
+		ResourceVersionMatch: metav1.ResourceVersionMatchNotOlderThan,
+		ResourceVersion:      "2",
+		LabelSelector:        "app=web",
+	})
+	require.NoError(t, err)
+	defer w.Stop()
+
+	event := <-w.ResultChan()
+	assert.Equal(t, watch.Added, event.Type)
+	assert.Equal(t, "a", event.Object.(*unstructured.Unstructured).GetName())
+
+	event = <-w.ResultChan()
+	require.Equal(t, watch.Bookmark, event.Type)
+	bookmark, ok := event.Object.(*unstructured.Unstructured)
+	require.True(t, ok, "expected the bookmark to have the type of the tracked objects, got %T", event.Object)
+	assert.Equal(t, "3", bookmark.GetResourceVersion())
+	assert.Equal(t, "true", bookmark.GetAnnotations()[metav1.InitialEventsAnnotationKey])
+
+	// Changes after the bookmark are delivered as usual.
+	require.NoError(t, o.Add(newSelectorTestPod("c", "web", "", "Pending")))
+	event = <-w.ResultChan()
+	assert.Equal(t, watch.Added, event.Type)
+	assert.Equal(t, "c", event.Object.(*unstructured.Unstructured).GetName())
+}
+
+func TestWatchManyInitialEvents(t *testing.T) {
+	// More objects than the channel of a RaceFreeFakeWatcher holds.
+	count := 2 * int(watch.DefaultChanSize)
+	o := newStrictTestTracker(2 * count)
+	for i := range count {
+		require.NoError(t, o.Add(newSelectorTestPod(fmt.Sprintf("pod-%d", i), "web", "", "Pending")))
+	}
+
+	for name, opts := range map[string]metav1.ListOptions{
+		"initial events": {
+			SendInitialEvents:    ptr.To(true),
+			AllowWatchBookmarks:  true,
+			ResourceVersionMatch: metav1.ResourceVersionMatchNotOlderThan,
+		},
+		"replay": {ResourceVersion: "1"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w, err := o.Watch(podGVR, "default", opts)
+			require.NoError(t, err)
+			defer w.Stop()
+
+			names := sets.New[string]()
+			for range count {
+				event := <-w.ResultChan()
+				require.Equal(t, watch.Added, event.Type)
+				names.Insert(event.Object.(*unstructured.Unstructured).GetName())
+			}
+			assert.Equal(t, count, names.Len())
+			if opts.SendInitialEvents != nil {
+				event := <-w.ResultChan()
+				require.Equal(t, watch.Bookmark, event.Type)
+			}
+
+			// Changes are delivered after the buffered events.
+			require.NoError(t, o.Delete(podGVR, "default", "pod-0"))
+			event := <-w.ResultChan()
+			assert.Equal(t, watch.Deleted, event.Type)
+			require.NoError(t, o.Add(newSelectorTestPod("pod-0", "web", "", "Pending")))
+			event = <-w.ResultChan()
+			assert.Equal(t, watch.Added, event.Type)
+		})
+	}
+}
+
+func TestWatchListSemanticsSupport(t *testing.T) {
+	assert.False(t, watchlist.DoesClientNotSupportWatchListSemantics(newStrictTestTracker(0)))
+	assert.True(t, watchlist.DoesClientNotSupportWatchListSemantics(newSelectorTestTracker()))
+}
+
+func TestReflectorWatchListWithTracker(t *testing.T) {
+	clientfeaturestesting.SetFeatureDuringTest(t, clientfeatures.WatchListClient, true)
+	o := newStrictTestTracker(0)
+	require.NoError(t, o.Add(newSelectorTestPod("a", "web", "", "Pending"))) // 2
+	require.NoError(t, o.Add(newSelectorTestPod("b", "db", "", "Pending")))  // 3
+
+	lw := cache.ToListWatcherWithWatchListSemantics(&cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			t.Errorf("unexpected list with %+v", options)
+			return o.List(podGVR, podGVK, "default", options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			return o.Watch(podGVR, "default", options)
+		},
+	}, o)
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	r := cache.NewReflector(lw, &unstructured.Unstructured{}, store, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.RunWithContext(ctx)
+
+	err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 10*time.Second, true, func(context.Context) (bool, error) {
+		return r.LastSyncResourceVersion() == "3" && len(store.List()) == 2, nil
+	})
+	require.NoError(t, err, "reflector did not sync through a watch list")
+}
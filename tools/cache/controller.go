@@ -99,6 +99,9 @@ type Config struct {
 
 	// WatchListPageSize is the requested chunk size of initial and relist watch lists.
 	WatchListPageSize int64
+
+	// InitialSnapshot optionally seeds the Queue instead of the initial list.
+	InitialSnapshot *StoreSnapshot
 }
 
 // ShouldResyncFunc is a type of function that indicates if a reflector should perform a
@@ -186,6 +189,7 @@ func (c *controller) RunWithContext(ctx context.Context) {
 			MinWatchTimeout: c.config.MinWatchTimeout,
 			TypeDescription: c.config.ObjectDescription,
 			Clock:           c.clock,
+			InitialSnapshot: c.config.InitialSnapshot,
 		},
 	)
 	r.ShouldResync = c.config.ShouldResync
@@ -0,0 +1,426 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/resourceversion"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/clock"
+)
+
+// resourceVersionPollInterval is how often WaitForResourceVersion checks
+// the resource version of the informer.
+const resourceVersionPollInterval = 10 * time.Millisecond
+
+// WaitForResourceVersion blocks until the informer has observed all changes
+// up to resourceVersion, typically the resource version of an object returned
+// by a create, update or patch.
+//
+// Resource versions can only be compared if they are integers, which is what
+// the apiserver uses. An error is returned if resourceVersion or the resource
+// version of the informer is not an integer, if ctx is canceled first or if
+// the informer gets stopped.
+//
+// With the AtomicFIFO feature, the store knows the resource version it
+// reflects, so reads from the store which follow see all changes up to
+// resourceVersion. Without it, only the resource version of the reflector is
+// known, which may be ahead of the store while the informer processes the
+// last events it received. Use WaitForWrite to wait for a single object in
+// that case.
+func WaitForResourceVersion(ctx context.Context, informer SharedIndexInformer, resourceVersion string) error {
+	return waitForResourceVersion(ctx, informer, resourceVersion, "")
+}
+
+// WaitForWrite blocks until the store of the informer reflects the write of
+// obj, an object returned by a create, update or patch. Reads from the store
+// which follow then see that write, or a later state of the object.
+//
+// With the AtomicFIFO feature, this is the same as WaitForResourceVersion
+// with the resource version of obj. Without it, WaitForWrite waits until the
+// store contains the object at its resource version or later, so it only
+// returns for objects which the store contains, not for objects which were
+// deleted again or which don't match the selectors of the informer.
+func WaitForWrite(ctx context.Context, informer SharedIndexInformer, obj interface{}) error {
+	key, err := DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return err
+	}
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	return waitForResourceVersion(ctx, informer, objMeta.GetResourceVersion(), key)
+}
+
+// waitForResourceVersion blocks until the store of the informer reflects
+// resourceVersion. If the store doesn't know its resource version and key is
+// set, it waits until the store has the object with that key at
+// resourceVersion or later.
+func waitForResourceVersion(ctx context.Context, informer SharedIndexInformer, resourceVersion, key string) error {
+	if _, err := resourceversion.CompareResourceVersion(resourceVersion, resourceVersion); err != nil {
+		return err
+	}
+	return wait.PollUntilContextCancel(ctx, resourceVersionPollInterval, true, func(ctx context.Context) (bool, error) {
+		if informer.IsStopped() {
+			return false, errors.New("informer is stopped")
+		}
+		indexer := informer.GetIndexer()
+		if current := indexer.LastStoreSyncResourceVersion(); len(current) > 0 {
+			return resourceVersionReached(current, resourceVersion)
+		}
+		reached, err := resourceVersionReached(informer.LastSyncResourceVersion(), resourceVersion)
+		if err != nil || !reached || len(key) == 0 {
+			return reached, err
+		}
+		// The reflector is ahead of the store while it still processes
+		// the events it received, so look at the object.
+		obj, exists, err := indexer.GetByKey(key)
+		if err != nil || !exists {
+			return false, err
+		}
+		objMeta, err := meta.Accessor(obj)
+		if err != nil {
+			return false, err
+		}
+		return resourceVersionReached(objMeta.GetResourceVersion(), resourceVersion)
+	})
+}
+
+// resourceVersionReached returns whether current is at least resourceVersion.
+// An empty current, for example the one of an informer which hasn't synced
+// yet, is not.
+func resourceVersionReached(current, resourceVersion string) (bool, error) {
+	if len(current) == 0 {
+		return false, nil
+	}
+	cmp, err := resourceversion.CompareResourceVersion(current, resourceVersion)
+	if err != nil {
+		return false, fmt.Errorf("cannot compare with the resource version of the informer: %w", err)
+	}
+	return cmp >= 0, nil
+}
+
+// PendingWritesIndexer is an Indexer which overlays the writes of the caller
+// on another Indexer, typically the one of an informer, until that has
+// observed them. In contrast to a MutationCache, it doesn't need to be told
+// about the events of the informer: pending writes are dropped as soon as
+// reads find that the store has caught up with them.
+//
+// Objects are matched to their keys with DeletionHandlingMetaNamespaceKeyFunc,
+// which is what informers use.
+type PendingWritesIndexer interface {
+	Indexer
+
+	// Written records an object which the caller created, updated or
+	// patched, as returned by the server. Until the store has observed the
+	// resource version of the object, reads return it instead of the object
+	// in the store.
+	Written(obj interface{})
+
+	// Deleted records an object which the caller deleted, as last seen by
+	// the caller. Until the store has observed the deletion, reads omit it.
+	Deleted(obj interface{})
+
+	// This interface is not meant to be implemented elsewhere.
+	// Marking it as internal enables future changes without
+	// triggering apidiff.
+	internal()
+}
+
+// NewPendingWritesIndexer returns a PendingWritesIndexer for indexer.
+//
+// Whether the store has observed a write is decided by comparing resource
+// versions, so writes of objects with a resource version that isn't an
+// integer are ignored. A write of an object which the store never contains,
+// for example because it was deleted right away or doesn't match the
+// selectors of the informer, is only known to be observed with the
+// AtomicFIFO feature. Otherwise it is dropped after ttl; zero means the
+// default of 5 minutes.
+func NewPendingWritesIndexer(indexer Indexer, ttl time.Duration) PendingWritesIndexer {
+	if ttl == 0 {
+		ttl = 5 * time.Minute
+	}
+	return &pendingWritesIndexer{
+		Indexer: indexer,
+		ttl:     ttl,
+		clock:   clock.RealClock{},
+		writes:  map[string]*pendingWrite{},
+	}
+}
+
+type pendingWritesIndexer struct {
+	// Indexer is the store. Writes to it and reads which don't return
+	// objects are passed through.
+	Indexer
+	ttl   time.Duration
+	clock clock.PassiveClock
+
+	lock   sync.Mutex
+	writes map[string]*pendingWrite
+}
+
+type pendingWrite struct {
+	obj             interface{}
+	uid             types.UID
+	resourceVersion string
+	deleted         bool
+	expires         time.Time
+}
+
+var _ PendingWritesIndexer = &pendingWritesIndexer{}
+
+func (p *pendingWritesIndexer) Written(obj interface{}) {
+	p.record(obj, false)
+}
+
+func (p *pendingWritesIndexer) Deleted(obj interface{}) {
+	p.record(obj, true)
+}
+
+func (p *pendingWritesIndexer) record(obj interface{}, deleted bool) {
+	key, err := DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	write := &pendingWrite{
+		obj:             obj,
+		uid:             objMeta.GetUID(),
+		resourceVersion: objMeta.GetResourceVersion(),
+		deleted:         deleted,
+		expires:         p.clock.Now().Add(p.ttl),
+	}
+	if _, err := resourceversion.CompareResourceVersion(write.resourceVersion, write.resourceVersion); err != nil {
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	// Writes can be recorded out of order by concurrent callers, keep the
+	// most recent one. A deletion is more recent than a write of the same
+	// resource version.
+	if existing, ok := p.writes[key]; ok {
+		cmp, err := resourceversion.CompareResourceVersion(write.resourceVersion, existing.resourceVersion)
+		if err == nil && (cmp < 0 || (cmp == 0 && existing.deleted && !deleted)) {
+			return
+		}
+	}
+	p.writes[key] = write
+}
+
+// pendingLocked returns the pending write of the key unless the store has
+// caught up with it, in which case the write gets forgotten. stored and
+// exists are the result of looking up the key in the store.
+func (p *pendingWritesIndexer) pendingLocked(key string, stored interface{}, exists bool) (*pendingWrite, bool) {
+	write, ok := p.writes[key]
+	if !ok {
+		return nil, false
+	}
+	if p.clock.Now().After(write.expires) || p.observedLocked(write, stored, exists) {
+		delete(p.writes, key)
+		return nil, false
+	}
+	return write, true
+}
+
+func (p *pendingWritesIndexer) observedLocked(write *pendingWrite, stored interface{}, exists bool) bool {
+	if !exists {
+		if write.deleted {
+			return true
+		}
+		// The store doesn't have the object, either because it hasn't
+		// seen it yet or because it has seen it and its deletion.
+		storeResourceVersion := p.Indexer.LastStoreSyncResourceVersion()
+		if len(storeResourceVersion) == 0 {
+			return false
+		}
+		cmp, err := resourceversion.CompareResourceVersion(storeResourceVersion, write.resourceVersion)
+		return err != nil || cmp >= 0
+	}
+	storedMeta, err := meta.Accessor(stored)
+	if err != nil {
+		return true
+	}
+	if write.deleted && len(write.uid) > 0 && storedMeta.GetUID() != write.uid {
+		// A new object of the same name.
+		return true
+	}
+	cmp, err := resourceversion.CompareResourceVersion(storedMeta.GetResourceVersion(), write.resourceVersion)
+	if err != nil {
+		return true
+	}
+	if write.deleted {
+		// The stored object is the deleted one until it changes, e.g.
+		// because finalizers keep it around.
+		return cmp > 0
+	}
+	return cmp >= 0
+}
+
+// overlay applies the pending writes to items, the objects of the store
+// which are the result of a read. matches returns whether a written object
+// belongs to the result.
+func (p *pendingWritesIndexer) overlay(items []interface{}, matches func(obj interface{}) bool) []interface{} {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.writes) == 0 {
+		return items
+	}
+
+	result := make([]interface{}, 0, len(items))
+	keys := sets.New[string]()
+	for _, item := range items {
+		key, err := DeletionHandlingMetaNamespaceKeyFunc(item)
+		if err != nil {
+			result = append(result, item)
+			continue
+		}
+		keys.Insert(key)
+		write, ok := p.pendingLocked(key, item, true)
+		switch {
+		case !ok:
+			result = append(result, item)
+		case !write.deleted && matches(write.obj):
+			result = append(result, write.obj)
+		}
+	}
+	// The remaining writes are checked even if they don't belong to the
+	// result, so that those which were observed get forgotten.
+	for key := range p.writes {
+		if keys.Has(key) {
+			continue
+		}
+		stored, exists, err := p.Indexer.GetByKey(key)
+		if err != nil {
+			continue
+		}
+		if write, ok := p.pendingLocked(key, stored, exists); ok && !write.deleted && matches(write.obj) {
+			result = append(result, write.obj)
+		}
+	}
+	return result
+}
+
+func (p *pendingWritesIndexer) List() []interface{} {
+	return p.overlay(p.Indexer.List(), func(interface{}) bool { return true })
+}
+
+func (p *pendingWritesIndexer) ListKeys() []string {
+	return objectKeys(p.List())
+}
+
+func (p *pendingWritesIndexer) Get(obj interface{}) (interface{}, bool, error) {
+	key, err := DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return nil, false, KeyError{obj, err}
+	}
+	return p.GetByKey(key)
+}
+
+func (p *pendingWritesIndexer) GetByKey(key string) (interface{}, bool, error) {
+	stored, exists, err := p.Indexer.GetByKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	write, ok := p.pendingLocked(key, stored, exists)
+	if !ok {
+		return stored, exists, nil
+	}
+	if write.deleted {
+		return nil, false, nil
+	}
+	return write.obj, true, nil
+}
+
+func (p *pendingWritesIndexer) Index(indexName string, obj interface{}) ([]interface{}, error) {
+	items, err := p.Indexer.Index(indexName, obj)
+	if err != nil {
+		return nil, err
+	}
+	indexFunc := p.Indexer.GetIndexers()[indexName]
+	indexValues, err := indexFunc(obj)
+	if err != nil {
+		return nil, err
+	}
+	indexedValues := sets.New(indexValues...)
+	return p.overlay(items, func(written interface{}) bool {
+		values, err := indexFunc(written)
+		if err != nil {
+			return false
+		}
+		for _, value := range values {
+			if indexedValues.Has(value) {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+func (p *pendingWritesIndexer) ByIndex(indexName, indexedValue string) ([]interface{}, error) {
+	items, err := p.Indexer.ByIndex(indexName, indexedValue)
+	if err != nil {
+		return nil, err
+	}
+	indexFunc := p.Indexer.GetIndexers()[indexName]
+	return p.overlay(items, func(written interface{}) bool {
+		values, err := indexFunc(written)
+		if err != nil {
+			return false
+		}
+		for _, value := range values {
+			if value == indexedValue {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+func (p *pendingWritesIndexer) IndexKeys(indexName, indexedValue string) ([]string, error) {
+	items, err := p.ByIndex(indexName, indexedValue)
+	if err != nil {
+		return nil, err
+	}
+	return objectKeys(items), nil
+}
+
+func (p *pendingWritesIndexer) internal() {}
+
+func objectKeys(items []interface{}) []string {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		if key, err := DeletionHandlingMetaNamespaceKeyFunc(item); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
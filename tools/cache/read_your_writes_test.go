@@ -0,0 +1,242 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientfeatures "k8s.io/client-go/features"
+	clientfeaturestesting "k8s.io/client-go/features/testing"
+	fcache "k8s.io/client-go/tools/cache/testing"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func newReadYourWritesTestPod(name, app string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID("uid-" + name), Labels: map[string]string{"app": app}}}
+}
+
+// startReadYourWritesTestInformer runs an informer of the pods of source,
+// which are indexed by their app label, and waits until it has synced.
+func startReadYourWritesTestInformer(ctx context.Context, t *testing.T, source *fcache.FakeControllerSource) SharedIndexInformer {
+	t.Helper()
+	informer := NewSharedIndexInformer(source, &v1.Pod{}, 0, Indexers{
+		"app": func(obj interface{}) ([]string, error) {
+			return []string{obj.(*v1.Pod).Labels["app"]}, nil
+		},
+	})
+	t.Cleanup(source.Shutdown)
+	go informer.RunWithContext(ctx)
+	if !WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		t.Fatal("informer didn't sync")
+	}
+	return informer
+}
+
+func podNamesOf(items []interface{}) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		pod := item.(*v1.Pod)
+		names = append(names, fmt.Sprintf("%s@%s", pod.Name, pod.Labels["app"]))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestWaitForResourceVersion(t *testing.T) {
+	for _, atomicFIFO := range []bool{false, true} {
+		t.Run(fmt.Sprintf("AtomicFIFO=%v", atomicFIFO), func(t *testing.T) {
+			clientfeaturestesting.SetFeatureDuringTest(t, clientfeatures.AtomicFIFO, atomicFIFO)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			source := fcache.NewFakeControllerSource()
+			source.Add(newReadYourWritesTestPod("a", "web"))
+			informer := startReadYourWritesTestInformer(ctx, t, source)
+
+			for _, app := range []string{"db", "cache", "proxy"} {
+				pod := newReadYourWritesTestPod("a", app)
+				source.Modify(pod)
+				waitCtx, waitCancel := context.WithTimeout(ctx, wait.ForeverTestTimeout)
+				err := WaitForResourceVersion(waitCtx, informer, pod.ResourceVersion)
+				waitCancel()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !atomicFIFO {
+					// The store may still lag behind the reflector,
+					// unless the object is waited for.
+					waitCtx, waitCancel := context.WithTimeout(ctx, wait.ForeverTestTimeout)
+					err := WaitForWrite(waitCtx, informer, pod)
+					waitCancel()
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+				}
+				stored, _, _ := informer.GetIndexer().GetByKey("default/a")
+				if stored.(*v1.Pod).Labels["app"] != app {
+					t.Errorf("expected the store to have the pod at resource version %s, got %v", pod.ResourceVersion, stored)
+				}
+			}
+
+			if err := WaitForResourceVersion(ctx, informer, "abc"); err == nil {
+				t.Error("expected an error for a resource version which isn't an integer")
+			}
+			waitCtx, waitCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+			defer waitCancel()
+			if err := WaitForResourceVersion(waitCtx, informer, "1000"); err == nil {
+				t.Error("expected an error for a resource version which is never reached")
+			}
+			cancel()
+			if err := WaitForResourceVersion(context.Background(), informer, "1000"); err == nil {
+				t.Error("expected an error for a stopped informer")
+			}
+		})
+	}
+}
+
+func TestResourceVersionReached(t *testing.T) {
+	for _, tc := range []struct {
+		current, resourceVersion string
+		expected                 bool
+		expectErr                bool
+	}{
+		{current: "", resourceVersion: "10", expected: false},
+		{current: "9", resourceVersion: "10", expected: false},
+		{current: "10", resourceVersion: "10", expected: true},
+		{current: "11", resourceVersion: "10", expected: true},
+		{current: "100", resourceVersion: "99", expected: true},
+		{current: "abc", resourceVersion: "10", expectErr: true},
+	} {
+		reached, err := resourceVersionReached(tc.current, tc.resourceVersion)
+		if (err != nil) != tc.expectErr {
+			t.Errorf("%q reached %q: expected error %v, got %v", tc.current, tc.resourceVersion, tc.expectErr, err)
+		}
+		if reached != tc.expected {
+			t.Errorf("%q reached %q: expected %v, got %v", tc.current, tc.resourceVersion, tc.expected, reached)
+		}
+	}
+}
+
+func TestPendingWritesIndexer(t *testing.T) {
+	clientfeaturestesting.SetFeatureDuringTest(t, clientfeatures.AtomicFIFO, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	source := fcache.NewFakeControllerSource()
+	source.Add(newReadYourWritesTestPod("a", "web"))
+	source.Add(newReadYourWritesTestPod("b", "web"))
+	informer := startReadYourWritesTestInformer(ctx, t, source)
+	indexer := NewPendingWritesIndexer(informer.GetIndexer(), 0)
+
+	expectPods := func(expected ...string) {
+		t.Helper()
+		if names := podNamesOf(indexer.List()); fmt.Sprint(names) != fmt.Sprint(expected) {
+			t.Errorf("expected List to return %v, got %v", expected, names)
+		}
+	}
+	expectIndexed := func(app string, expected ...string) {
+		t.Helper()
+		items, err := indexer.ByIndex("app", app)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if names := podNamesOf(items); fmt.Sprint(names) != fmt.Sprint(expected) {
+			t.Errorf("expected ByIndex(%q) to return %v, got %v", app, expected, names)
+		}
+	}
+	waitForSource := func(resourceVersion string) {
+		t.Helper()
+		waitCtx, waitCancel := context.WithTimeout(ctx, wait.ForeverTestTimeout)
+		defer waitCancel()
+		if err := WaitForResourceVersion(waitCtx, informer, resourceVersion); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The informer doesn't observe these writes yet.
+	updated := newReadYourWritesTestPod("a", "db")
+	source.ModifyDropWatch(updated)
+	indexer.Written(updated)
+	created := newReadYourWritesTestPod("c", "db")
+	source.AddDropWatch(created)
+	indexer.Written(created)
+	deleted := newReadYourWritesTestPod("b", "web")
+	source.DeleteDropWatch(deleted)
+	indexer.Deleted(deleted)
+
+	expectPods("a@db", "c@db")
+	expectIndexed("web")
+	expectIndexed("db", "a@db", "c@db")
+	if obj, exists, _ := indexer.Get(deleted); exists {
+		t.Errorf("expected the deleted pod to be hidden, got %v", obj)
+	}
+	if obj, exists, _ := indexer.GetByKey("default/a"); !exists || obj.(*v1.Pod).Labels["app"] != "db" {
+		t.Errorf("expected the updated pod, got %v", obj)
+	}
+	if keys := indexer.ListKeys(); len(keys) != 2 {
+		t.Errorf("expected the keys of the written pods, got %v", keys)
+	}
+
+	// An older write doesn't replace a newer one.
+	older := newReadYourWritesTestPod("a", "web")
+	older.ResourceVersion = "1"
+	indexer.Written(older)
+	expectPods("a@db", "c@db")
+
+	// Once the informer observes the writes, the store is read again.
+	// The created pod has been deleted in the meantime, which is only
+	// known because the store has a resource version past its creation.
+	source.ResetWatch()
+	source.Delete(created.DeepCopy())
+	later := newReadYourWritesTestPod("a", "cache")
+	source.Modify(later)
+	waitForSource(later.ResourceVersion)
+	expectPods("a@cache")
+	expectIndexed("db")
+	if pending := len(indexer.(*pendingWritesIndexer).writes); pending != 0 {
+		t.Errorf("expected no pending writes, got %d", pending)
+	}
+}
+
+func TestPendingWritesIndexerExpiry(t *testing.T) {
+	clientfeaturestesting.SetFeatureDuringTest(t, clientfeatures.AtomicFIFO, false)
+	store := NewIndexer(DeletionHandlingMetaNamespaceKeyFunc, Indexers{})
+	indexer := NewPendingWritesIndexer(store, time.Minute)
+	clock := testingclock.NewFakeClock(time.Now())
+	indexer.(*pendingWritesIndexer).clock = clock
+
+	created := newReadYourWritesTestPod("a", "web")
+	created.ResourceVersion = "10"
+	indexer.Written(created)
+	notComparable := newReadYourWritesTestPod("b", "web")
+	notComparable.ResourceVersion = "abc"
+	indexer.Written(notComparable)
+
+	if items := indexer.List(); len(items) != 1 || items[0] != created {
+		t.Errorf("expected the created pod, got %v", items)
+	}
+	clock.Step(2 * time.Minute)
+	if items := indexer.List(); len(items) != 0 {
+		t.Errorf("expected the write to expire, got %v", items)
+	}
+}
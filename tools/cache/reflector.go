@@ -168,6 +168,8 @@ type Reflector struct {
 	//
 	// See https://github.com/kubernetes/enhancements/tree/master/keps/sig-api-machinery/3157-watch-list#design-details
 	useWatchList bool
+	// initialSnapshot, if set, is used instead of the initial list and cleared afterwards.
+	initialSnapshot *StoreSnapshot
 }
 
 func (r *Reflector) Name() string {
@@ -281,6 +283,11 @@ type ReflectorOptions struct {
 	// DelayWithReset(clock, resetDuration) will be called on it to create the delay function.
 	// TODO(#136943): Expose this configuration through SharedInformerFactory.
 	Backoff *wait.Backoff
+
+	// InitialSnapshot optionally seeds the store instead of the initial list.
+	// The Reflector then starts watching from the snapshot's resource version.
+	// If that resource version is too old, the Reflector falls back to listing.
+	InitialSnapshot *StoreSnapshot
 }
 
 // NewReflectorWithOptions creates a new Reflector object which will keep the
@@ -337,6 +344,7 @@ func NewReflectorWithOptions(lw ListerWatcher, expectedType interface{}, store R
 		clock:             reflectorClock,
 		watchErrorHandler: WatchErrorHandlerWithContext(DefaultWatchErrorHandler),
 		expectedType:      reflect.TypeOf(expectedType),
+		initialSnapshot:   options.InitialSnapshot,
 	}
 
 	if r.name == "" {
@@ -480,6 +488,15 @@ func (r *Reflector) ListAndWatchWithContext(ctx context.Context) error {
 		}
 	}()
 
+	if snapshot := r.takeInitialSnapshot(); snapshot != nil {
+		if err := r.syncWith(snapshot.Objects, snapshot.ResourceVersion); err != nil {
+			return fmt.Errorf("unable to sync snapshot: %w", err)
+		}
+		r.setLastSyncResourceVersion(snapshot.ResourceVersion)
+		logger.V(2).Info("Caches populated from snapshot", "type", r.typeDescription, "reflector", r.name, "resourceVersion", snapshot.ResourceVersion)
+		return r.watchWithResync(ctx, nil)
+	}
+
 	if r.useWatchList {
 		w, err = r.watchList(ctx)
 		if w == nil && err == nil {
@@ -508,6 +525,19 @@ func (r *Reflector) ListAndWatchWithContext(ctx context.Context) error {
 	return r.watchWithResync(ctx, w)
 }
 
+// takeInitialSnapshot returns the snapshot that the reflector was configured
+// with, if any, and makes sure that it is only used once.
+func (r *Reflector) takeInitialSnapshot() *StoreSnapshot {
+	r.lastSyncResourceVersionMutex.Lock()
+	defer r.lastSyncResourceVersionMutex.Unlock()
+	snapshot := r.initialSnapshot
+	r.initialSnapshot = nil
+	if snapshot == nil || snapshot.ResourceVersion == "" || r.lastSyncResourceVersion != "" {
+		return nil
+	}
+	return snapshot
+}
+
 // startResync periodically calls r.store.Resync() method.
 // Note that this method is blocking and should be
 // called in a separate goroutine.
@@ -444,6 +444,11 @@ func NewSharedIndexInformerWithOptions(lw ListerWatcher, exampleObject runtime.O
 	processor := &sharedProcessor{clock: realClock}
 	processor.listenersRCond = sync.NewCond(processor.listenersLock.RLocker())
 
+	snapshotPeriod := options.SnapshotPeriod
+	if snapshotPeriod <= 0 {
+		snapshotPeriod = DefaultSnapshotPeriod
+	}
+
 	return &sharedIndexInformer{
 		indexer:                         NewIndexer(DeletionHandlingMetaNamespaceKeyFunc, options.Indexers, WithStoreMetrics(options.Identifier, options.InformerMetricsProvider)),
 		processor:                       processor,
@@ -458,6 +463,8 @@ func NewSharedIndexInformerWithOptions(lw ListerWatcher, exampleObject runtime.O
 		identifier:                      options.Identifier,
 		informerMetricsProvider:         options.InformerMetricsProvider,
 		keyFunc:                         DeletionHandlingMetaNamespaceKeyFunc,
+		snapshotStorage:                 options.SnapshotStorage,
+		snapshotPeriod:                  snapshotPeriod,
 	}
 }
 
@@ -481,6 +488,22 @@ type SharedIndexInformerOptions struct {
 	// InformerMetricsProvider is the metrics provider for the FIFO queue.
 	// If not set, metrics will be no-ops.
 	InformerMetricsProvider InformerMetricsProvider
+
+	// SnapshotStorage, if set, is used to periodically persist the contents of the
+	// informer's store together with the resource version they reflect. When the
+	// informer starts, it seeds its store from the last snapshot and resumes watching
+	// from its resource version instead of listing all objects. Handlers may therefore
+	// observe stale objects until the watch has caught up. The snapshot contains the
+	// objects as stored in the informer, so the transform function of the informer is
+	// not applied to them again.
+	//
+	// Snapshots are only written while the AtomicFIFO feature gate is enabled, because
+	// only then is the resource version of the store guaranteed to match its contents.
+	SnapshotStorage SnapshotStorage
+
+	// SnapshotPeriod is how often a snapshot is written to SnapshotStorage. A final
+	// snapshot is written when the informer stops. Defaults to DefaultSnapshotPeriod.
+	SnapshotPeriod time.Duration
 }
 
 // InformerSynced is a function that can be used to determine if an informer has synced.  This is useful for determining if caches have synced.
@@ -757,6 +780,13 @@ type sharedIndexInformer struct {
 
 	// keyFunc is called when processing deltas by the underlying process function.
 	keyFunc KeyFunc
+
+	// snapshotStorage, if set, persists the contents of the indexer every snapshotPeriod.
+	snapshotStorage SnapshotStorage
+	snapshotPeriod  time.Duration
+	// snapshotResourceVersion is the resource version of the last snapshot that was
+	// saved or loaded. It is only accessed by RunWithContext and runSnapshots.
+	snapshotResourceVersion string
 }
 
 // dummyController hides the fact that a SharedInformer is different from a dedicated one
@@ -851,7 +881,25 @@ func (s *sharedIndexInformer) RunWithContext(ctx context.Context) {
 		s.startedLock.Lock()
 		defer s.startedLock.Unlock()
 
-		logger, fifo := newQueueFIFO(logger, s.objectType, s.indexer, s.transform, s.identifier, s.informerMetricsProvider)
+		var snapshot *StoreSnapshot
+		if s.snapshotStorage != nil {
+			var err error
+			snapshot, err = s.snapshotStorage.Load()
+			if err != nil {
+				utilruntime.HandleErrorWithContext(ctx, err, "Failed to load informer snapshot, listing instead", "type", fmt.Sprintf("%T", s.objectType))
+				snapshot = nil
+			} else if snapshot != nil {
+				s.snapshotResourceVersion = snapshot.ResourceVersion
+			}
+		}
+
+		transform := s.transform
+		if snapshot != nil && transform != nil {
+			// The objects of the snapshot come from the indexer and are
+			// transformed already.
+			transform = skipSnapshotTransform(snapshot, transform)
+		}
+		logger, fifo := newQueueFIFO(logger, s.objectType, s.indexer, transform, s.identifier, s.informerMetricsProvider)
 
 		cfg := &Config{
 			Queue:             fifo,
@@ -868,6 +916,7 @@ func (s *sharedIndexInformer) RunWithContext(ctx context.Context) {
 				return s.handleBatchDeltas(logger, deltas, isInInitialList)
 			},
 			WatchErrorHandlerWithContext: s.watchErrorHandler,
+			InitialSnapshot:              snapshot,
 		}
 
 		s.controller = New(cfg)
@@ -886,6 +935,9 @@ func (s *sharedIndexInformer) RunWithContext(ctx context.Context) {
 	// has a RunWithContext method that we can use here.
 	wg.StartWithChannel(processorStopCtx.Done(), s.cacheMutationDetector.Run)
 	wg.StartWithContext(processorStopCtx, s.processor.run)
+	if s.snapshotStorage != nil {
+		wg.StartWithContext(processorStopCtx, s.runSnapshots)
+	}
 	wg.Start(func() {
 		select {
 		case <-ctx.Done():
@@ -904,6 +956,63 @@ func (s *sharedIndexInformer) RunWithContext(ctx context.Context) {
 	s.controller.RunWithContext(ctx)
 }
 
+// runSnapshots saves a snapshot of the indexer every snapshotPeriod and once more
+// when the informer stops.
+func (s *sharedIndexInformer) runSnapshots(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	if !clientgofeaturegate.FeatureGates().Enabled(clientgofeaturegate.AtomicFIFO) {
+		logger.Info("Warning: informer snapshots require the AtomicFIFO feature gate, no snapshots will be written", "type", fmt.Sprintf("%T", s.objectType))
+		return
+	}
+
+	timer := s.clock.NewTimer(s.snapshotPeriod)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.saveSnapshot(ctx)
+			return
+		case <-timer.C():
+			s.saveSnapshot(ctx)
+			timer.Reset(s.snapshotPeriod)
+		}
+	}
+}
+
+// saveSnapshot saves the contents of the indexer if they changed since the last snapshot.
+func (s *sharedIndexInformer) saveSnapshot(ctx context.Context) {
+	if !s.HasSynced() {
+		return
+	}
+	lister, ok := s.indexer.(resourceVersionLister)
+	if !ok {
+		return
+	}
+	items, resourceVersion := lister.listWithResourceVersion()
+	if resourceVersion == "" || resourceVersion == s.snapshotResourceVersion {
+		return
+	}
+
+	snapshot := &StoreSnapshot{
+		ResourceVersion: resourceVersion,
+		Objects:         make([]runtime.Object, 0, len(items)),
+	}
+	for _, item := range items {
+		obj, ok := item.(runtime.Object)
+		if !ok {
+			utilruntime.HandleErrorWithContext(ctx, nil, "Unable to snapshot informer store with non-runtime.Object item", "type", fmt.Sprintf("%T", item))
+			return
+		}
+		snapshot.Objects = append(snapshot.Objects, obj)
+	}
+	if err := s.snapshotStorage.Save(snapshot); err != nil {
+		utilruntime.HandleErrorWithContext(ctx, err, "Failed to save informer snapshot", "type", fmt.Sprintf("%T", s.objectType))
+		return
+	}
+	s.snapshotResourceVersion = resourceVersion
+	klog.FromContext(ctx).V(4).Info("Saved informer snapshot", "type", fmt.Sprintf("%T", s.objectType), "resourceVersion", resourceVersion, "count", len(snapshot.Objects))
+}
+
 func (s *sharedIndexInformer) HasStarted() bool {
 	s.startedLock.Lock()
 	defer s.startedLock.Unlock()
@@ -0,0 +1,185 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DefaultSnapshotPeriod is how often a shared informer persists a snapshot of
+// its store if SharedIndexInformerOptions.SnapshotPeriod is unset.
+const DefaultSnapshotPeriod = 5 * time.Minute
+
+// StoreSnapshot is a point in time copy of the contents of an informer's store.
+type StoreSnapshot struct {
+	// ResourceVersion is the resource version which the objects reflect.
+	// A Reflector that is seeded with the snapshot resumes watching from it.
+	ResourceVersion string
+
+	// Objects are the objects which were in the store.
+	Objects []runtime.Object
+}
+
+// SnapshotStorage persists StoreSnapshots so that an informer can be seeded
+// from them after a restart instead of listing all objects from the server.
+type SnapshotStorage interface {
+	// Save persists the snapshot, replacing any snapshot saved before.
+	Save(snapshot *StoreSnapshot) error
+
+	// Load returns the most recently saved snapshot, or nil if there is none.
+	Load() (*StoreSnapshot, error)
+}
+
+// resourceVersionLister is implemented by stores which can list their items
+// together with the resource version those items reflect.
+type resourceVersionLister interface {
+	listWithResourceVersion() ([]interface{}, string)
+}
+
+// skipSnapshotTransform returns a TransformFunc which returns the objects of the
+// snapshot unchanged and transforms all other objects with transform. Objects
+// are only skipped once, so that they don't have to be kept for longer than
+// the initial Replace.
+func skipSnapshotTransform(snapshot *StoreSnapshot, transform TransformFunc) TransformFunc {
+	var lock sync.Mutex
+	pending := make(map[interface{}]struct{}, len(snapshot.Objects))
+	for _, obj := range snapshot.Objects {
+		if isPointer(obj) {
+			pending[obj] = struct{}{}
+		}
+	}
+	return func(obj interface{}) (interface{}, error) {
+		if isPointer(obj) {
+			lock.Lock()
+			_, found := pending[obj]
+			delete(pending, obj)
+			lock.Unlock()
+			if found {
+				return obj, nil
+			}
+		}
+		return transform(obj)
+	}
+}
+
+// isPointer returns true if obj is a pointer, which can be used as map key
+// regardless of what it points to.
+func isPointer(obj interface{}) bool {
+	return obj != nil && reflect.TypeOf(obj).Kind() == reflect.Pointer
+}
+
+// fileSnapshotStorage implements SnapshotStorage on top of a single file.
+type fileSnapshotStorage struct {
+	path  string
+	codec runtime.Codec
+}
+
+// snapshotFile is the on-disk format of a fileSnapshotStorage. The objects are
+// kept as encoded by the codec, so any serialization can be used.
+type snapshotFile struct {
+	ResourceVersion string   `json:"resourceVersion"`
+	Objects         [][]byte `json:"objects"`
+}
+
+// NewFileSnapshotStorage returns a SnapshotStorage which keeps the snapshot in
+// the file at path, encoding and decoding the objects with the given codec.
+// The codec must be able to decode what it encodes into the informer's object
+// type, for example unstructured.UnstructuredJSONScheme for dynamic informers.
+//
+// Snapshots are written to a temporary file which is then renamed to path, so
+// a crash while saving leaves the previous snapshot intact.
+func NewFileSnapshotStorage(path string, codec runtime.Codec) SnapshotStorage {
+	return &fileSnapshotStorage{
+		path:  path,
+		codec: codec,
+	}
+}
+
+func (f *fileSnapshotStorage) Save(snapshot *StoreSnapshot) error {
+	file := snapshotFile{
+		ResourceVersion: snapshot.ResourceVersion,
+		Objects:         make([][]byte, 0, len(snapshot.Objects)),
+	}
+	for _, obj := range snapshot.Objects {
+		data, err := runtime.Encode(f.codec, obj)
+		if err != nil {
+			return fmt.Errorf("failed to encode %T: %w", obj, err)
+		}
+		file.Objects = append(file.Objects, data)
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Only does something if the rename below did not happen.
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+func (f *fileSnapshotStorage) Load() (*StoreSnapshot, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file snapshotFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", f.path, err)
+	}
+	snapshot := &StoreSnapshot{
+		ResourceVersion: file.ResourceVersion,
+		Objects:         make([]runtime.Object, 0, len(file.Objects)),
+	}
+	for i, data := range file.Objects {
+		obj, err := runtime.Decode(f.codec, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode object %d of snapshot %s: %w", i, f.path, err)
+		}
+		snapshot.Objects = append(snapshot.Objects, obj)
+	}
+	return snapshot, nil
+}
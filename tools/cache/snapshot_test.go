@@ -0,0 +1,228 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	clientfeatures "k8s.io/client-go/features"
+	clientfeaturestesting "k8s.io/client-go/features/testing"
+	"k8s.io/klog/v2/ktesting"
+)
+
+func newSnapshotTestObject(name, resourceVersion string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("example.com/v1")
+	obj.SetKind("Widget")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetResourceVersion(resourceVersion)
+	return obj
+}
+
+func TestFileSnapshotStorage(t *testing.T) {
+	storage := NewFileSnapshotStorage(filepath.Join(t.TempDir(), "snapshot"), unstructured.UnstructuredJSONScheme)
+
+	snapshot, err := storage.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot != nil {
+		t.Fatalf("expected no snapshot, got %v", snapshot)
+	}
+
+	for _, rv := range []string{"5", "7"} {
+		err = storage.Save(&StoreSnapshot{
+			ResourceVersion: rv,
+			Objects:         []runtime.Object{newSnapshotTestObject("a", "3"), newSnapshotTestObject("b", rv)},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	snapshot, err = storage.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := "7", snapshot.ResourceVersion; e != a {
+		t.Errorf("expected resource version %q, got %q", e, a)
+	}
+	if e, a := 2, len(snapshot.Objects); e != a {
+		t.Fatalf("expected %d objects, got %d", e, a)
+	}
+	b := snapshot.Objects[1].(*unstructured.Unstructured)
+	if b.GetName() != "b" || b.GetResourceVersion() != "7" {
+		t.Errorf("unexpected object %v", b)
+	}
+}
+
+func TestReflectorInitialSnapshot(t *testing.T) {
+	clientfeaturestesting.SetFeatureDuringTest(t, clientfeatures.WatchListClient, false)
+	_, ctx := ktesting.NewTestContext(t)
+	store := NewStore(MetaNamespaceKeyFunc)
+	fw := watch.NewFake()
+	var listCalls int
+	var watchResourceVersions []string
+	lw := &ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			listCalls++
+			list := &unstructured.UnstructuredList{}
+			list.SetResourceVersion("20")
+			return list, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			watchResourceVersions = append(watchResourceVersions, options.ResourceVersion)
+			return fw, nil
+		},
+	}
+	r := NewReflectorWithOptions(lw, &unstructured.Unstructured{}, store, ReflectorOptions{
+		InitialSnapshot: &StoreSnapshot{
+			ResourceVersion: "10",
+			Objects:         []runtime.Object{newSnapshotTestObject("a", "8")},
+		},
+	})
+
+	go func() {
+		// The watch from the snapshot's resource version is too old.
+		fw.Error(&apierrors.NewResourceExpired("too old").ErrStatus)
+	}()
+	if err := r.ListAndWatchWithContext(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := 0, listCalls; e != a {
+		t.Errorf("expected %d list calls, got %d", e, a)
+	}
+	if e, a := []string{"10"}, watchResourceVersions; len(a) != 1 || e[0] != a[0] {
+		t.Errorf("expected watches from %v, got %v", e, a)
+	}
+	if _, exists, _ := store.GetByKey("default/a"); !exists {
+		t.Errorf("expected the store to be seeded from the snapshot")
+	}
+
+	// The snapshot is only used once, the next attempt falls back to a list.
+	fw = watch.NewFake()
+	go fw.Stop()
+	if err := r.ListAndWatchWithContext(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := 1, listCalls; e != a {
+		t.Errorf("expected %d list calls, got %d", e, a)
+	}
+	if _, exists, _ := store.GetByKey("default/a"); exists {
+		t.Errorf("expected the snapshot contents to be replaced by the list")
+	}
+	if e, a := "20", r.LastSyncResourceVersion(); e != a {
+		t.Errorf("expected resource version %q, got %q", e, a)
+	}
+}
+
+func TestSharedInformerSnapshot(t *testing.T) {
+	clientfeaturestesting.SetFeatureDuringTest(t, clientfeatures.WatchListClient, false)
+	clientfeaturestesting.SetFeatureDuringTest(t, clientfeatures.AtomicFIFO, true)
+	storage := NewFileSnapshotStorage(filepath.Join(t.TempDir(), "snapshot"), unstructured.UnstructuredJSONScheme)
+
+	var lock sync.Mutex
+	var listCalls int
+	var watchResourceVersions []string
+	lw := &ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			listCalls++
+			list := &unstructured.UnstructuredList{}
+			list.SetResourceVersion("10")
+			list.Items = []unstructured.Unstructured{*newSnapshotTestObject("a", "4"), *newSnapshotTestObject("b", "9")}
+			return list, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			watchResourceVersions = append(watchResourceVersions, options.ResourceVersion)
+			return watch.NewRaceFreeFake(), nil
+		},
+	}
+	run := func() SharedIndexInformer {
+		_, ctx := ktesting.NewTestContext(t)
+		ctx, cancel := context.WithCancel(ctx)
+		informer := NewSharedIndexInformerWithOptions(lw, &unstructured.Unstructured{}, SharedIndexInformerOptions{
+			SnapshotStorage: storage,
+			SnapshotPeriod:  time.Hour,
+		})
+		// The transform is not idempotent, so objects of the snapshot must
+		// not be transformed again.
+		if err := informer.SetTransform(func(obj interface{}) (interface{}, error) {
+			u := obj.(*unstructured.Unstructured)
+			u.SetLabels(map[string]string{"transformed": u.GetLabels()["transformed"] + "x"})
+			return u, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		var wg wait.Group
+		wg.StartWithContext(ctx, informer.RunWithContext)
+		if !WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+			t.Fatal("informer did not sync")
+		}
+		// Stopping the informer writes the final snapshot.
+		cancel()
+		wg.Wait()
+		return informer
+	}
+
+	run()
+	snapshot, err := storage.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot == nil {
+		t.Fatal("expected a snapshot to be written")
+	}
+	if e, a := "10", snapshot.ResourceVersion; e != a {
+		t.Errorf("expected resource version %q, got %q", e, a)
+	}
+	if e, a := 2, len(snapshot.Objects); e != a {
+		t.Errorf("expected %d objects, got %d", e, a)
+	}
+
+	informer := run()
+	lock.Lock()
+	defer lock.Unlock()
+	if e, a := 1, listCalls; e != a {
+		t.Errorf("expected %d list calls, got %d", e, a)
+	}
+	if e, a := "10", watchResourceVersions[len(watchResourceVersions)-1]; e != a {
+		t.Errorf("expected the seeded informer to watch from %q, got %q", e, a)
+	}
+	if e, a := 2, len(informer.GetStore().List()); e != a {
+		t.Errorf("expected %d objects in the seeded store, got %d", e, a)
+	}
+	for _, obj := range informer.GetStore().List() {
+		if e, a := "x", obj.(*unstructured.Unstructured).GetLabels()["transformed"]; e != a {
+			t.Errorf("expected the objects of the snapshot to be transformed once, got the label %q", a)
+		}
+	}
+}
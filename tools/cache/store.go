@@ -309,6 +309,13 @@ func (c *cache) LastStoreSyncResourceVersion() string {
 	return c.cacheStorage.LastStoreSyncResourceVersion()
 }
 
+func (c *cache) listWithResourceVersion() ([]interface{}, string) {
+	if lister, ok := c.cacheStorage.(resourceVersionLister); ok {
+		return lister.listWithResourceVersion()
+	}
+	return c.cacheStorage.List(), ""
+}
+
 func (c *cache) Bookmark(rv string) {
 	c.cacheStorage.Bookmark(rv)
 }
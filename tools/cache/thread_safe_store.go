@@ -443,6 +443,23 @@ func (c *threadSafeMap) LastStoreSyncResourceVersion() string {
 	return c.rv
 }
 
+// listWithResourceVersion returns all items together with the resource version
+// that the store had when they were listed. Like LastStoreSyncResourceVersion,
+// the resource version is empty unless the AtomicFIFO feature gate is enabled.
+func (c *threadSafeMap) listWithResourceVersion() ([]interface{}, string) {
+	atomicFIFO := clientgofeaturegate.FeatureGates().Enabled(clientgofeaturegate.AtomicFIFO)
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	list := make([]interface{}, 0, len(c.items))
+	for _, item := range c.items {
+		list = append(list, item)
+	}
+	if !atomicFIFO {
+		return list, ""
+	}
+	return list, c.rv
+}
+
 // Bookmark sets the latest resource version that the store has seen.
 func (c *threadSafeMap) Bookmark(rv string) {
 	var rvInt int64
@@ -0,0 +1,327 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cachedclient provides a client for a single resource which reads
+// from a shared informer and writes to the server. Controllers can use it
+// instead of mixing listers with direct calls of the typed client:
+//
+//	factory := informers.NewSharedInformerFactory(clientset, resync)
+//	pods := cachedclient.New(ctx, factory, factory.Core().V1().Pods(),
+//		clientset.CoreV1().Pods(namespace), namespace,
+//		cachedclient.WithReadYourWrites(10*time.Second))
+//
+//	pod, err := pods.Get(ctx, name, metav1.GetOptions{})
+//
+// The informer is only created and started when the client first reads, so
+// clients for resources which end up not being read cost nothing.
+package cachedclient
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Object is the type of objects the client works with, for example *v1.Pod.
+type Object interface {
+	metav1.Object
+	runtime.Object
+}
+
+// Interface is implemented by the typed clients of a resource, for example
+// kubernetes.Interface.CoreV1().Pods(namespace), and by Client itself.
+type Interface[T Object, L runtime.Object] interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (T, error)
+	List(ctx context.Context, opts metav1.ListOptions) (L, error)
+	Create(ctx context.Context, obj T, opts metav1.CreateOptions) (T, error)
+	Update(ctx context.Context, obj T, opts metav1.UpdateOptions) (T, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (T, error)
+}
+
+// InformerFactory starts the informers which were requested from it, for
+// example informers.SharedInformerFactory.
+type InformerFactory interface {
+	Start(stopCh <-chan struct{})
+}
+
+// Informer provides the shared informer of a resource, for example
+// informers.SharedInformerFactory.Core().V1().Pods().
+type Informer interface {
+	Informer() cache.SharedIndexInformer
+}
+
+// Option configures a Client.
+type Option func(*options)
+
+type options struct {
+	readYourWritesTimeout time.Duration
+}
+
+// WithReadYourWrites makes Create, Update and Patch wait until the informer
+// has observed the resource version of the written object, so that reads
+// which follow a write see it. The wait ends after the timeout, in which
+// case the write still succeeds and reads may return the previous state.
+//
+// Writes don't wait before the client first read, because the informer
+// isn't started yet and will observe them when it is.
+func WithReadYourWrites(timeout time.Duration) Option {
+	return func(o *options) {
+		o.readYourWritesTimeout = timeout
+	}
+}
+
+// Client serves Get and List from a shared informer and sends all writes to
+// the server.
+//
+// Get falls back to the server if the object is not in the cache, which
+// means that objects created by others can be read before the informer
+// observes them and that NotFound errors come from the server. Reads which
+// the cache cannot serve also go to the server: a specific resource version,
+// paginated lists and field selectors on fields other than metadata.name and
+// metadata.namespace.
+//
+// Objects returned from the cache are copies and may be modified.
+type Client[T Object, L runtime.Object] struct {
+	ctx       context.Context
+	factory   InformerFactory
+	informer  Informer
+	client    Interface[T, L]
+	namespace string
+	options   options
+
+	startOnce      sync.Once
+	started        atomic.Bool
+	sharedInformer cache.SharedIndexInformer
+}
+
+var _ Interface[*metav1.PartialObjectMetadata, *metav1.PartialObjectMetadataList] = &Client[*metav1.PartialObjectMetadata, *metav1.PartialObjectMetadataList]{}
+
+// New returns a Client for the resource of informer. client must be a client
+// for the same resource in namespace, which is empty for all namespaces and
+// cluster-scoped resources. The informer gets started with factory when the
+// client first reads and runs until ctx is canceled.
+func New[T Object, L runtime.Object](ctx context.Context, factory InformerFactory, informer Informer, client Interface[T, L], namespace string, opts ...Option) *Client[T, L] {
+	c := &Client[T, L]{
+		ctx:       ctx,
+		factory:   factory,
+		informer:  informer,
+		client:    client,
+		namespace: namespace,
+	}
+	for _, opt := range opts {
+		opt(&c.options)
+	}
+	return c
+}
+
+// Get returns the object with the given name from the cache, or from the
+// server if the cache doesn't have it or opts asks for a specific resource
+// version.
+func (c *Client[T, L]) Get(ctx context.Context, name string, opts metav1.GetOptions) (T, error) {
+	var zero T
+	if !servedFromCache(opts.ResourceVersion) {
+		return c.client.Get(ctx, name, opts)
+	}
+	informer, err := c.start(ctx)
+	if err != nil {
+		return zero, err
+	}
+	obj, exists, err := informer.GetIndexer().GetByKey(c.key(name))
+	if err != nil {
+		return zero, err
+	}
+	if !exists {
+		return c.client.Get(ctx, name, opts)
+	}
+	return deepCopy[T](obj)
+}
+
+// List returns the objects which match the selectors of opts from the cache,
+// sorted like the server sorts them. The resource version of the list is the
+// one the informer last synced to.
+func (c *Client[T, L]) List(ctx context.Context, opts metav1.ListOptions) (L, error) {
+	var zero L
+	if !servedFromCache(opts.ResourceVersion) || opts.Limit > 0 || len(opts.Continue) > 0 || len(opts.ResourceVersionMatch) > 0 {
+		return c.client.List(ctx, opts)
+	}
+	labelSelector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return zero, apierrors.NewBadRequest(err.Error())
+	}
+	fieldSelector, err := fields.ParseSelector(opts.FieldSelector)
+	if err != nil {
+		return zero, apierrors.NewBadRequest(err.Error())
+	}
+	for _, requirement := range fieldSelector.Requirements() {
+		if requirement.Field != "metadata.name" && requirement.Field != "metadata.namespace" {
+			return c.client.List(ctx, opts)
+		}
+	}
+
+	informer, err := c.start(ctx)
+	if err != nil {
+		return zero, err
+	}
+	resourceVersion := informer.LastSyncResourceVersion()
+	var objs []Object
+	err = cache.ListAllByNamespace(informer.GetIndexer(), c.namespace, labelSelector, func(obj interface{}) {
+		o, ok := obj.(Object)
+		if !ok {
+			return
+		}
+		if fieldSelector.Matches(fields.Set{"metadata.name": o.GetName(), "metadata.namespace": o.GetNamespace()}) {
+			objs = append(objs, o)
+		}
+	})
+	if err != nil {
+		return zero, err
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		if objs[i].GetNamespace() != objs[j].GetNamespace() {
+			return objs[i].GetNamespace() < objs[j].GetNamespace()
+		}
+		return objs[i].GetName() < objs[j].GetName()
+	})
+
+	items := make([]runtime.Object, 0, len(objs))
+	for _, obj := range objs {
+		item, err := deepCopy[T](obj)
+		if err != nil {
+			return zero, err
+		}
+		items = append(items, item)
+	}
+	list := reflect.New(reflect.TypeFor[L]().Elem()).Interface().(L)
+	if err := meta.SetList(list, items); err != nil {
+		return zero, err
+	}
+	listMeta, err := meta.ListAccessor(list)
+	if err != nil {
+		return zero, err
+	}
+	listMeta.SetResourceVersion(resourceVersion)
+	return list, nil
+}
+
+// Create creates the object on the server.
+func (c *Client[T, L]) Create(ctx context.Context, obj T, opts metav1.CreateOptions) (T, error) {
+	result, err := c.client.Create(ctx, obj, opts)
+	if err == nil {
+		c.waitForWrite(ctx, result)
+	}
+	return result, err
+}
+
+// Update updates the object on the server.
+func (c *Client[T, L]) Update(ctx context.Context, obj T, opts metav1.UpdateOptions) (T, error) {
+	result, err := c.client.Update(ctx, obj, opts)
+	if err == nil {
+		c.waitForWrite(ctx, result)
+	}
+	return result, err
+}
+
+// Delete deletes the object on the server. It doesn't wait for the informer
+// to observe the deletion, because the resource version of the deletion is
+// not known.
+func (c *Client[T, L]) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete(ctx, name, opts)
+}
+
+// Patch patches the object on the server.
+func (c *Client[T, L]) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (T, error) {
+	result, err := c.client.Patch(ctx, name, pt, data, opts, subresources...)
+	if err == nil {
+		c.waitForWrite(ctx, result)
+	}
+	return result, err
+}
+
+// start starts the informer on the first call and waits until it has synced.
+func (c *Client[T, L]) start(ctx context.Context) (cache.SharedIndexInformer, error) {
+	c.startOnce.Do(func() {
+		c.sharedInformer = c.informer.Informer()
+		c.factory.Start(c.ctx.Done())
+		c.started.Store(true)
+	})
+	if err := c.ctx.Err(); err != nil {
+		return nil, fmt.Errorf("cached client is stopped: %w", err)
+	}
+
+	// Stop waiting when the informer gets stopped, it won't sync anymore.
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(c.ctx, cancel)
+	defer stop()
+	if !cache.WaitFor(waitCtx, "", c.sharedInformer.HasSyncedChecker()) {
+		if err := c.ctx.Err(); err != nil {
+			return nil, fmt.Errorf("cached client is stopped: %w", err)
+		}
+		return nil, fmt.Errorf("failed to wait for the cache to sync: %w", ctx.Err())
+	}
+	return c.sharedInformer, nil
+}
+
+// waitForWrite waits until the informer has observed the written object if
+// read-your-writes is enabled.
+func (c *Client[T, L]) waitForWrite(ctx context.Context, obj T) {
+	if c.options.readYourWritesTimeout <= 0 || !c.started.Load() || len(obj.GetResourceVersion()) == 0 {
+		return
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, c.options.readYourWritesTimeout)
+	defer cancel()
+	if err := cache.WaitForWrite(waitCtx, c.sharedInformer, obj); err != nil {
+		klog.FromContext(ctx).V(4).Info("Cache didn't observe the write", "object", klog.KObj(obj), "resourceVersion", obj.GetResourceVersion(), "err", err)
+	}
+}
+
+func (c *Client[T, L]) key(name string) string {
+	if len(c.namespace) == 0 {
+		return name
+	}
+	return c.namespace + "/" + name
+}
+
+// servedFromCache returns whether a read with the resource version can be
+// served from the cache. The cache is as recent as the informer, which is
+// what the client asks for when it doesn't ask for a specific version.
+func servedFromCache(resourceVersion string) bool {
+	return len(resourceVersion) == 0 || resourceVersion == "0"
+}
+
+func deepCopy[T Object](obj interface{}) (T, error) {
+	o, ok := obj.(T)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("unexpected object of type %T in the cache", obj)
+	}
+	return o.DeepCopyObject().(T), nil
+}
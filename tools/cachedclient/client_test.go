@@ -0,0 +1,220 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cachedclient
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func newPod(namespace, name string, labels map[string]string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+}
+
+func podNames(list *v1.PodList) []string {
+	var names []string
+	for _, pod := range list.Items {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+// countActions returns the number of actions of the verb on pods.
+func countActions(clientset *fake.Clientset, verb string) int {
+	count := 0
+	for _, action := range clientset.Actions() {
+		if action.Matches(verb, "pods") {
+			count++
+		}
+	}
+	return count
+}
+
+// newTestClient returns a client of the pods in namespace default. The fake
+// clientset assigns increasing resource versions to written pods.
+func newTestClient(ctx context.Context, t *testing.T, opts ...Option) (*Client[*v1.Pod, *v1.PodList], *fake.Clientset) {
+	t.Helper()
+	clientset := fake.NewClientset(
+		newPod("default", "b", nil),
+		newPod("default", "a", map[string]string{"app": "web"}),
+		newPod("other", "c", map[string]string{"app": "web"}),
+	)
+	var resourceVersion atomic.Int64
+	resourceVersion.Store(100)
+	setResourceVersion := func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action, ok := action.(clienttesting.CreateAction); ok {
+			action.GetObject().(*v1.Pod).ResourceVersion = strconv.FormatInt(resourceVersion.Add(1), 10)
+		}
+		return false, nil, nil
+	}
+	clientset.PrependReactor("create", "pods", setResourceVersion)
+	clientset.PrependReactor("update", "pods", setResourceVersion)
+
+	factory := informers.NewSharedInformerFactory(clientset, 0)
+	t.Cleanup(factory.Shutdown)
+	return New(ctx, factory, factory.Core().V1().Pods(), clientset.CoreV1().Pods("default"), "default", opts...), clientset
+}
+
+func TestClientReads(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client, clientset := newTestClient(ctx, t)
+	if lists := countActions(clientset, "list"); lists != 0 {
+		t.Fatalf("expected the informer to start on the first read, got %d lists", lists)
+	}
+
+	for selector, expected := range map[metav1.ListOptions][]string{
+		{}:                                   {"a", "b"},
+		{LabelSelector: "app=web"}:           {"a"},
+		{FieldSelector: "metadata.name=b"}:   {"b"},
+		{FieldSelector: "metadata.name!=b"}:  {"a"},
+		{ResourceVersion: "0"}:               {"a", "b"},
+		{LabelSelector: "app=web,tier=back"}: nil,
+	} {
+		list, err := client.List(ctx, selector)
+		if err != nil {
+			t.Fatalf("%+v: unexpected error: %v", selector, err)
+		}
+		if names := podNames(list); len(names) != len(expected) || (len(names) > 0 && names[0] != expected[0]) {
+			t.Errorf("%+v: expected %v, got %v", selector, expected, names)
+		}
+		if len(list.ResourceVersion) == 0 {
+			t.Errorf("%+v: expected the list to have a resource version", selector)
+		}
+	}
+	if lists := countActions(clientset, "list"); lists != 1 {
+		t.Errorf("expected lists to be served from the cache, got %d lists", lists)
+	}
+
+	pod, err := client.Get(ctx, "a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod.Labels["app"] != "web" {
+		t.Errorf("expected pod a, got %v", pod)
+	}
+	pod.Labels["app"] = "modified"
+	if pod, _ := client.Get(ctx, "a", metav1.GetOptions{}); pod.Labels["app"] != "web" {
+		t.Error("expected a copy of the cached pod")
+	}
+	if gets := countActions(clientset, "get"); gets != 0 {
+		t.Errorf("expected gets to be served from the cache, got %d gets", gets)
+	}
+
+	// Reads the cache cannot serve go to the server.
+	if _, err := client.Get(ctx, "c", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected a NotFound error for a pod in another namespace, got %v", err)
+	}
+	if _, err := client.Get(ctx, "a", metav1.GetOptions{ResourceVersion: "1"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if gets := countActions(clientset, "get"); gets != 2 {
+		t.Errorf("expected 2 gets from the server, got %d", gets)
+	}
+	for _, opts := range []metav1.ListOptions{
+		{Limit: 1},
+		{FieldSelector: "spec.nodeName=node"},
+		{ResourceVersion: "1", ResourceVersionMatch: metav1.ResourceVersionMatchNotOlderThan},
+	} {
+		if _, err := client.List(ctx, opts); err != nil {
+			t.Errorf("%+v: unexpected error: %v", opts, err)
+		}
+	}
+	if lists := countActions(clientset, "list"); lists != 4 {
+		t.Errorf("expected 3 lists from the server, got %d", lists-1)
+	}
+
+	if _, err := client.List(ctx, metav1.ListOptions{LabelSelector: "app in (web"}); !apierrors.IsBadRequest(err) {
+		t.Errorf("expected a BadRequest error for an invalid selector, got %v", err)
+	}
+}
+
+func TestClientReadYourWrites(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client, clientset := newTestClient(ctx, t, WithReadYourWrites(wait.ForeverTestTimeout))
+
+	// Writes before the first read don't wait for the informer.
+	if _, err := client.Create(ctx, newPod("default", "d", nil), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.List(ctx, metav1.ListOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"e", "f", "g"} {
+		created, err := client.Create(ctx, newPod("default", name, nil), metav1.CreateOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		list, err := client.List(ctx, metav1.ListOptions{FieldSelector: "metadata.name=" + name})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(list.Items) != 1 || list.Items[0].ResourceVersion != created.ResourceVersion {
+			t.Errorf("expected pod %s at resource version %s to be listed, got %v", name, created.ResourceVersion, list.Items)
+		}
+	}
+	if lists := countActions(clientset, "list"); lists != 1 {
+		t.Errorf("expected lists to be served from the cache, got %d lists", lists)
+	}
+}
+
+func TestClientReadYourWritesTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client, clientset := newTestClient(ctx, t, WithReadYourWrites(100*time.Millisecond))
+	// The update is never observed by the informer.
+	clientset.PrependReactor("update", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		pod := action.(clienttesting.UpdateAction).GetObject().(*v1.Pod).DeepCopy()
+		pod.ResourceVersion = "1000"
+		return true, pod, nil
+	})
+	pod, err := client.Get(ctx, "a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := client.Update(ctx, pod, metav1.UpdateOptions{}); err != nil {
+		t.Errorf("expected the update to succeed after the timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected the update to wait for the timeout, returned after %v", elapsed)
+	}
+}
+
+func TestClientStopped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	client, _ := newTestClient(ctx, t)
+	cancel()
+	if _, err := client.Get(context.Background(), "a", metav1.GetOptions{}); err == nil {
+		t.Error("expected an error after the client was stopped")
+	}
+}
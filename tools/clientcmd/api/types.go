@@ -269,6 +269,17 @@ type ExecConfig struct {
 	// +optional
 	InteractiveMode ExecInteractiveMode `json:"interactiveMode,omitempty"`
 
+	// CacheCredentials determines whether the credentials returned by this exec plugin
+	// are cached on disk, so that they can be reused by other processes until their
+	// expirationTimestamp instead of executing the plugin again. Only credentials with
+	// an expirationTimestamp are cached. The cache is stored in ~/.kube/cache/exec with
+	// permissions that only allow the current user to read it. Processes only share
+	// credentials if they execute the plugin with the same arguments and environment,
+	// and inherit the same values of variables which commonly select an identity,
+	// like KUBECONFIG or AWS_PROFILE. By default, it is set to false.
+	// +optional
+	CacheCredentials bool `json:"cacheCredentials,omitempty"`
+
 	// StdinUnavailable indicates whether the exec authenticator can pass standard
 	// input through to this exec plugin. For example, a higher level entity might be using
 	// standard input for something else and therefore it would not be safe for the exec
@@ -358,7 +369,7 @@ func (c ExecConfig) String() string {
 	if c.Config != nil {
 		config = "runtime.Object(--- REDACTED ---)"
 	}
-	return fmt.Sprintf("api.ExecConfig{Command: %q, Args: %#v, Env: %s, APIVersion: %q, ProvideClusterInfo: %t, Config: %s, StdinUnavailable: %t}", c.Command, args, env, c.APIVersion, c.ProvideClusterInfo, config, c.StdinUnavailable)
+	return fmt.Sprintf("api.ExecConfig{Command: %q, Args: %#v, Env: %s, APIVersion: %q, ProvideClusterInfo: %t, Config: %s, CacheCredentials: %t, StdinUnavailable: %t}", c.Command, args, env, c.APIVersion, c.ProvideClusterInfo, config, c.CacheCredentials, c.StdinUnavailable)
 }
 
 // ExecEnvVar is used for setting environment variables when executing an exec-based
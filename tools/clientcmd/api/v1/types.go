@@ -244,6 +244,17 @@ type ExecConfig struct {
 	// to "IfAvailable" when unset. Otherwise, this field is required.
 	//+optional
 	InteractiveMode ExecInteractiveMode `json:"interactiveMode,omitempty"`
+
+	// CacheCredentials determines whether the credentials returned by this exec plugin
+	// are cached on disk, so that they can be reused by other processes until their
+	// expirationTimestamp instead of executing the plugin again. Only credentials with
+	// an expirationTimestamp are cached. The cache is stored in ~/.kube/cache/exec with
+	// permissions that only allow the current user to read it. Processes only share
+	// credentials if they execute the plugin with the same arguments and environment,
+	// and inherit the same values of variables which commonly select an identity,
+	// like KUBECONFIG or AWS_PROFILE. By default, it is set to false.
+	// +optional
+	CacheCredentials bool `json:"cacheCredentials,omitempty"`
 }
 
 // ExecEnvVar is used for setting environment variables when executing an exec-based
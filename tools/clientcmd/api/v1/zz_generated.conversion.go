@@ -380,6 +380,7 @@ func autoConvert_v1_ExecConfig_To_api_ExecConfig(in *ExecConfig, out *api.ExecCo
 	out.InstallHint = in.InstallHint
 	out.ProvideClusterInfo = in.ProvideClusterInfo
 	out.InteractiveMode = api.ExecInteractiveMode(in.InteractiveMode)
+	out.CacheCredentials = in.CacheCredentials
 	return nil
 }
 
@@ -397,6 +398,7 @@ func autoConvert_api_ExecConfig_To_v1_ExecConfig(in *api.ExecConfig, out *ExecCo
 	out.ProvideClusterInfo = in.ProvideClusterInfo
 	// INFO: in.Config opted out of conversion generation
 	out.InteractiveMode = ExecInteractiveMode(in.InteractiveMode)
+	out.CacheCredentials = in.CacheCredentials
 	// INFO: in.StdinUnavailable opted out of conversion generation
 	// INFO: in.StdinUnavailableMessage opted out of conversion generation
 	// INFO: in.PluginPolicy opted out of conversion generation
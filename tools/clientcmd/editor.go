@@ -0,0 +1,356 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientcmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	clientcmdapiv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// configLockTimeout is how long EditConfigFile waits for the lock of a
+	// kubeconfig file which is held by another writer.
+	configLockTimeout = 10 * time.Second
+	// configLockRetryPeriod is how often EditConfigFile tries to take the lock.
+	configLockRetryPeriod = 50 * time.Millisecond
+)
+
+// EditConfigFile loads a kubeconfig file, applies edit to it and writes the
+// result back. If the file does not exist, edit starts from an empty config
+// and the file is created with mode 0600.
+//
+// The file is locked during the edit with the same lock file as ModifyConfig,
+// so that concurrent writers do not lose each other's changes, and the new
+// content is written to a temporary file which is renamed over the old one,
+// so that readers never see a partially written file. The order of the
+// clusters, contexts and users of the file is preserved, its comments are not.
+func EditConfigFile(filename string, edit func(config *clientcmdapi.Config) error) error {
+	if UseModifyConfigLock {
+		if err := lockFileWithTimeout(filename, configLockTimeout); err != nil {
+			return err
+		}
+		defer unlockFile(filename) //nolint:errcheck
+	}
+
+	// Write through symlinks instead of replacing them.
+	target := filename
+	if resolved, err := filepath.EvalSymlinks(filename); err == nil {
+		target = resolved
+	}
+	original, err := os.ReadFile(target)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(target); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	config, err := Load(original)
+	if err != nil {
+		return fmt.Errorf("error loading config file %q: %w", filename, err)
+	}
+	if err := edit(config); err != nil {
+		return err
+	}
+	content, err := Write(*config)
+	if err != nil {
+		return err
+	}
+	if len(original) > 0 {
+		content = preserveOrder(original, content)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomically(target, content, mode)
+}
+
+// lockFileWithTimeout takes the lock of a kubeconfig file, waiting for other
+// writers which hold it.
+func lockFileWithTimeout(filename string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := lockFile(filename)
+		if err == nil || !os.IsExist(err) {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for the lock of %q, remove %q if no other process is writing the file", filename, lockName(filename))
+		}
+		time.Sleep(configLockRetryPeriod)
+	}
+}
+
+// writeFileAtomically writes data to a temporary file in the directory of
+// filename and renames it to filename.
+func writeFileAtomically(filename string, data []byte, mode os.FileMode) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+	if err = f.Chmod(mode); err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filename)
+}
+
+// preserveOrder orders the clusters, contexts, users and extensions of the
+// updated kubeconfig like the ones of the original, so that an edit does not
+// move the entries which it does not touch. New entries are added after the
+// existing ones. It returns updated unchanged if either cannot be parsed.
+func preserveOrder(original, updated []byte) []byte {
+	var originalConfig, updatedConfig clientcmdapiv1.Config
+	if err := yaml.Unmarshal(original, &originalConfig); err != nil {
+		return updated
+	}
+	if err := yaml.Unmarshal(updated, &updatedConfig); err != nil {
+		return updated
+	}
+	sortLike(updatedConfig.Clusters, originalConfig.Clusters, func(c clientcmdapiv1.NamedCluster) string { return c.Name })
+	sortLike(updatedConfig.AuthInfos, originalConfig.AuthInfos, func(a clientcmdapiv1.NamedAuthInfo) string { return a.Name })
+	sortLike(updatedConfig.Contexts, originalConfig.Contexts, func(c clientcmdapiv1.NamedContext) string { return c.Name })
+	sortLike(updatedConfig.Extensions, originalConfig.Extensions, func(e clientcmdapiv1.NamedExtension) string { return e.Name })
+	content, err := yaml.Marshal(&updatedConfig)
+	if err != nil {
+		return updated
+	}
+	return content
+}
+
+// sortLike sorts items by the position of the item with the same name in
+// original. Items which original does not have keep their order at the end.
+func sortLike[T any](items, original []T, name func(T) string) {
+	positions := make(map[string]int, len(original))
+	for i, item := range original {
+		positions[name(item)] = i
+	}
+	slices.SortStableFunc(items, func(a, b T) int {
+		aPosition, aExists := positions[name(a)]
+		bPosition, bExists := positions[name(b)]
+		switch {
+		case aExists && bExists:
+			return cmp.Compare(aPosition, bPosition)
+		case aExists:
+			return -1
+		case bExists:
+			return 1
+		}
+		return 0
+	})
+}
+
+// AddCluster validates a cluster and adds it to the config. It fails if the
+// config already has a cluster with the name.
+func AddCluster(config *clientcmdapi.Config, name string, cluster *clientcmdapi.Cluster) error {
+	if _, exists := config.Clusters[name]; exists {
+		return fmt.Errorf("cluster %q already exists", name)
+	}
+	if err := newErrConfigurationInvalid(validateClusterInfo(name, *cluster)); err != nil {
+		return err
+	}
+	if config.Clusters == nil {
+		config.Clusters = map[string]*clientcmdapi.Cluster{}
+	}
+	config.Clusters[name] = cluster
+	return nil
+}
+
+// AddAuthInfo validates a user and adds it to the config. It fails if the
+// config already has a user with the name.
+func AddAuthInfo(config *clientcmdapi.Config, name string, authInfo *clientcmdapi.AuthInfo) error {
+	if _, exists := config.AuthInfos[name]; exists {
+		return fmt.Errorf("user %q already exists", name)
+	}
+	if err := newErrConfigurationInvalid(validateAuthInfo(name, *authInfo)); err != nil {
+		return err
+	}
+	if config.AuthInfos == nil {
+		config.AuthInfos = map[string]*clientcmdapi.AuthInfo{}
+	}
+	config.AuthInfos[name] = authInfo
+	return nil
+}
+
+// AddContext validates a context against the clusters and users of the config
+// and adds it. It fails if the config already has a context with the name.
+func AddContext(config *clientcmdapi.Config, name string, context *clientcmdapi.Context) error {
+	if _, exists := config.Contexts[name]; exists {
+		return fmt.Errorf("context %q already exists", name)
+	}
+	if err := newErrConfigurationInvalid(validateContext(name, *context, *config)); err != nil {
+		return err
+	}
+	if config.Contexts == nil {
+		config.Contexts = map[string]*clientcmdapi.Context{}
+	}
+	config.Contexts[name] = context
+	return nil
+}
+
+// RenameContext renames a context, and the current context if it is the
+// renamed one.
+func RenameContext(config *clientcmdapi.Config, oldName, newName string) error {
+	context, exists := config.Contexts[oldName]
+	if !exists {
+		return &errContextNotFound{oldName}
+	}
+	if len(newName) == 0 {
+		return errors.New("the new context name must not be empty")
+	}
+	if oldName == newName {
+		return nil
+	}
+	if _, exists := config.Contexts[newName]; exists {
+		return fmt.Errorf("context %q already exists", newName)
+	}
+	delete(config.Contexts, oldName)
+	config.Contexts[newName] = context
+	if config.CurrentContext == oldName {
+		config.CurrentContext = newName
+	}
+	return nil
+}
+
+// PruneUnused removes the clusters and users which are not referenced by any
+// context. It returns the names of the removed clusters and users.
+func PruneUnused(config *clientcmdapi.Config) (clusters, authInfos []string) {
+	usedClusters := map[string]bool{}
+	usedAuthInfos := map[string]bool{}
+	for _, context := range config.Contexts {
+		usedClusters[context.Cluster] = true
+		usedAuthInfos[context.AuthInfo] = true
+	}
+	for name := range config.Clusters {
+		if !usedClusters[name] {
+			delete(config.Clusters, name)
+			clusters = append(clusters, name)
+		}
+	}
+	for name := range config.AuthInfos {
+		if !usedAuthInfos[name] {
+			delete(config.AuthInfos, name)
+			authInfos = append(authInfos, name)
+		}
+	}
+	sort.Strings(clusters)
+	sort.Strings(authInfos)
+	return clusters, authInfos
+}
+
+// MergeFrom adds the clusters, users and contexts of other to the config,
+// after validating them. Entries which exist in both configs with different
+// content are only replaced if overwrite is true, otherwise MergeFrom fails
+// without changing the config. The current context is taken from other if the
+// config has none.
+func MergeFrom(config *clientcmdapi.Config, other *clientcmdapi.Config, overwrite bool) error {
+	merged := config.DeepCopy()
+	if merged.Clusters == nil {
+		merged.Clusters = map[string]*clientcmdapi.Cluster{}
+	}
+	if merged.AuthInfos == nil {
+		merged.AuthInfos = map[string]*clientcmdapi.AuthInfo{}
+	}
+	if merged.Contexts == nil {
+		merged.Contexts = map[string]*clientcmdapi.Context{}
+	}
+
+	var errs []error
+	for name, cluster := range other.Clusters {
+		if existing, exists := merged.Clusters[name]; exists && !overwrite && !sameCluster(existing, cluster) {
+			errs = append(errs, fmt.Errorf("cluster %q already exists with different content", name))
+			continue
+		}
+		errs = append(errs, validateClusterInfo(name, *cluster)...)
+		merged.Clusters[name] = cluster.DeepCopy()
+	}
+	for name, authInfo := range other.AuthInfos {
+		if existing, exists := merged.AuthInfos[name]; exists && !overwrite && !sameAuthInfo(existing, authInfo) {
+			errs = append(errs, fmt.Errorf("user %q already exists with different content", name))
+			continue
+		}
+		errs = append(errs, validateAuthInfo(name, *authInfo)...)
+		merged.AuthInfos[name] = authInfo.DeepCopy()
+	}
+	for name, context := range other.Contexts {
+		if existing, exists := merged.Contexts[name]; exists && !overwrite && !sameContext(existing, context) {
+			errs = append(errs, fmt.Errorf("context %q already exists with different content", name))
+			continue
+		}
+		merged.Contexts[name] = context.DeepCopy()
+	}
+	// Contexts are validated once all clusters and users are merged.
+	for name := range other.Contexts {
+		errs = append(errs, validateContext(name, *merged.Contexts[name], *merged)...)
+	}
+	if len(merged.CurrentContext) == 0 {
+		merged.CurrentContext = other.CurrentContext
+	}
+	if err := newErrConfigurationInvalid(errs); err != nil {
+		return err
+	}
+	*config = *merged
+	return nil
+}
+
+// The LocationOfOrigin of clusters, users and contexts only says which file
+// they were loaded from, so it is ignored when comparing them.
+
+func sameCluster(a, b *clientcmdapi.Cluster) bool {
+	a, b = a.DeepCopy(), b.DeepCopy()
+	a.LocationOfOrigin, b.LocationOfOrigin = "", ""
+	return reflect.DeepEqual(a, b)
+}
+
+func sameAuthInfo(a, b *clientcmdapi.AuthInfo) bool {
+	a, b = a.DeepCopy(), b.DeepCopy()
+	a.LocationOfOrigin, b.LocationOfOrigin = "", ""
+	return reflect.DeepEqual(a, b)
+}
+
+func sameContext(a, b *clientcmdapi.Context) bool {
+	a, b = a.DeepCopy(), b.DeepCopy()
+	a.LocationOfOrigin, b.LocationOfOrigin = "", ""
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,356 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const commentedConfig = `# Managed by hand, keep the comments.
+apiVersion: v1
+kind: Config
+current-context: zebra
+clusters:
+- name: zebra-cluster # the old one
+  cluster:
+    server: https://zebra.example.com
+- name: alpha-cluster
+  cluster:
+    server: https://alpha.example.com
+contexts:
+- name: zebra
+  context:
+    cluster: zebra-cluster
+    user: zebra-user
+users:
+- name: zebra-user
+  user:
+    token: zebra-token
+`
+
+func TestEditConfigFilePreservesOrder(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(filename, []byte(commentedConfig), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	err := EditConfigFile(filename, func(config *clientcmdapi.Config) error {
+		return AddCluster(config, "beta-cluster", &clientcmdapi.Cluster{Server: "https://beta.example.com"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `apiVersion: v1
+clusters:
+- cluster:
+    server: https://zebra.example.com
+  name: zebra-cluster
+- cluster:
+    server: https://alpha.example.com
+  name: alpha-cluster
+- cluster:
+    server: https://beta.example.com
+  name: beta-cluster
+contexts:
+- context:
+    cluster: zebra-cluster
+    user: zebra-user
+  name: zebra
+current-context: zebra
+kind: Config
+users:
+- name: zebra-user
+  user:
+    token: zebra-token
+`
+	if diff := cmp.Diff(expected, string(content)); diff != "" {
+		t.Errorf("unexpected content (-want +got):\n%s", diff)
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("expected mode 0640, got %v", info.Mode().Perm())
+	}
+	if _, err := os.Stat(lockName(filename)); !os.IsNotExist(err) {
+		t.Errorf("expected the lock file to be removed, got %v", err)
+	}
+}
+
+func TestEditConfigFileCreatesFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "nested", "config")
+	err := EditConfigFile(filename, func(config *clientcmdapi.Config) error {
+		config.CurrentContext = "foo"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config, err := LoadFromFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.CurrentContext != "foo" {
+		t.Errorf("expected current context foo, got %q", config.CurrentContext)
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestEditConfigFileKeepsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "real-config")
+	link := filepath.Join(dir, "config")
+	if err := os.WriteFile(target, []byte(commentedConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+
+	err := EditConfigFile(link, func(config *clientcmdapi.Config) error {
+		config.CurrentContext = ""
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	info, err := os.Lstat(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("expected %s to still be a symlink", link)
+	}
+	config, err := LoadFromFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.CurrentContext) != 0 {
+		t.Errorf("expected the target to be updated, got current context %q", config.CurrentContext)
+	}
+}
+
+func TestEditConfigFileEditError(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(filename, []byte(commentedConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	err := EditConfigFile(filename, func(config *clientcmdapi.Config) error {
+		return AddCluster(config, "alpha-cluster", &clientcmdapi.Cluster{Server: "https://other.example.com"})
+	})
+	if err == nil {
+		t.Fatal("expected an error for an existing cluster")
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != commentedConfig {
+		t.Errorf("expected the file to be unchanged, got:\n%s", content)
+	}
+}
+
+func TestEditConfigFileLockTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { configLockTimeout = timeout }(configLockTimeout)
+	configLockTimeout = 100 * time.Millisecond
+
+	filename := filepath.Join(t.TempDir(), "config")
+	if err := lockFile(filename); err != nil {
+		t.Fatal(err)
+	}
+	err := EditConfigFile(filename, func(config *clientcmdapi.Config) error {
+		t.Error("unexpected edit while the file is locked")
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), lockName(filename)) {
+		t.Errorf("expected a lock timeout naming the lock file, got %v", err)
+	}
+}
+
+func TestEditConfigFileConcurrently(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config")
+	const editors = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, editors)
+	for i := 0; i < editors; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- EditConfigFile(filename, func(config *clientcmdapi.Config) error {
+				return AddCluster(config, fmt.Sprintf("cluster-%d", i), &clientcmdapi.Cluster{Server: fmt.Sprintf("https://%d.example.com", i)})
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	config, err := LoadFromFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Clusters) != editors {
+		t.Errorf("expected %d clusters, got %d", editors, len(config.Clusters))
+	}
+}
+
+func TestAddCluster(t *testing.T) {
+	config := clientcmdapi.NewConfig()
+	if err := AddCluster(config, "foo", &clientcmdapi.Cluster{}); err == nil || !IsConfigurationInvalid(err) {
+		t.Errorf("expected an invalid configuration error for a cluster without a server, got %v", err)
+	}
+	if err := AddCluster(config, "foo", &clientcmdapi.Cluster{Server: "https://foo"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := AddCluster(config, "foo", &clientcmdapi.Cluster{Server: "https://bar"}); err == nil {
+		t.Error("expected an error for an existing cluster")
+	}
+}
+
+func TestAddContext(t *testing.T) {
+	config := clientcmdapi.NewConfig()
+	config.Clusters["foo"] = &clientcmdapi.Cluster{Server: "https://foo"}
+	config.AuthInfos["foo"] = &clientcmdapi.AuthInfo{Token: "foo"}
+	if err := AddContext(config, "foo", &clientcmdapi.Context{Cluster: "missing", AuthInfo: "foo"}); err == nil {
+		t.Error("expected an error for a context of a missing cluster")
+	}
+	if err := AddContext(config, "foo", &clientcmdapi.Context{Cluster: "foo", AuthInfo: "foo"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRenameContext(t *testing.T) {
+	config := clientcmdapi.NewConfig()
+	config.Contexts["foo"] = &clientcmdapi.Context{Cluster: "foo"}
+	config.Contexts["bar"] = &clientcmdapi.Context{Cluster: "bar"}
+	config.CurrentContext = "foo"
+
+	if err := RenameContext(config, "missing", "baz"); err == nil {
+		t.Error("expected an error for a missing context")
+	}
+	if err := RenameContext(config, "foo", "bar"); err == nil {
+		t.Error("expected an error for an existing new name")
+	}
+	if err := RenameContext(config, "foo", "baz"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, exists := config.Contexts["foo"]; exists {
+		t.Error("expected the old context to be removed")
+	}
+	if config.Contexts["baz"].Cluster != "foo" {
+		t.Errorf("expected the renamed context, got %v", config.Contexts["baz"])
+	}
+	if config.CurrentContext != "baz" {
+		t.Errorf("expected the current context to be renamed, got %q", config.CurrentContext)
+	}
+}
+
+func TestPruneUnused(t *testing.T) {
+	config := clientcmdapi.NewConfig()
+	config.Clusters["used"] = &clientcmdapi.Cluster{Server: "https://used"}
+	config.Clusters["unused-b"] = &clientcmdapi.Cluster{Server: "https://b"}
+	config.Clusters["unused-a"] = &clientcmdapi.Cluster{Server: "https://a"}
+	config.AuthInfos["used"] = &clientcmdapi.AuthInfo{Token: "used"}
+	config.AuthInfos["unused"] = &clientcmdapi.AuthInfo{Token: "unused"}
+	config.Contexts["ctx"] = &clientcmdapi.Context{Cluster: "used", AuthInfo: "used"}
+
+	clusters, authInfos := PruneUnused(config)
+	if !reflect.DeepEqual(clusters, []string{"unused-a", "unused-b"}) {
+		t.Errorf("unexpected pruned clusters %v", clusters)
+	}
+	if !reflect.DeepEqual(authInfos, []string{"unused"}) {
+		t.Errorf("unexpected pruned users %v", authInfos)
+	}
+	if len(config.Clusters) != 1 || len(config.AuthInfos) != 1 {
+		t.Errorf("expected only the used entries to be left, got clusters %v and users %v", config.Clusters, config.AuthInfos)
+	}
+}
+
+func TestMergeFrom(t *testing.T) {
+	newConfig := func() *clientcmdapi.Config {
+		config := clientcmdapi.NewConfig()
+		config.Clusters["foo"] = &clientcmdapi.Cluster{Server: "https://foo", LocationOfOrigin: "a"}
+		config.AuthInfos["foo"] = &clientcmdapi.AuthInfo{Token: "foo"}
+		config.Contexts["foo"] = &clientcmdapi.Context{Cluster: "foo", AuthInfo: "foo"}
+		return config
+	}
+	other := clientcmdapi.NewConfig()
+	other.Clusters["foo"] = &clientcmdapi.Cluster{Server: "https://foo", LocationOfOrigin: "b"}
+	other.Clusters["bar"] = &clientcmdapi.Cluster{Server: "https://bar"}
+	other.Contexts["bar"] = &clientcmdapi.Context{Cluster: "bar", AuthInfo: "foo"}
+	other.CurrentContext = "bar"
+
+	config := newConfig()
+	if err := MergeFrom(config, other, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Clusters["bar"] == nil || config.Contexts["bar"] == nil {
+		t.Errorf("expected the new entries to be merged, got %v", config)
+	}
+	if config.CurrentContext != "bar" {
+		t.Errorf("expected the current context of other, got %q", config.CurrentContext)
+	}
+
+	conflicting := other.DeepCopy()
+	conflicting.Clusters["foo"].Server = "https://other"
+	config = newConfig()
+	if err := MergeFrom(config, conflicting, false); err == nil {
+		t.Error("expected an error for a conflicting cluster")
+	}
+	if !reflect.DeepEqual(config, newConfig()) {
+		t.Errorf("expected the config to be unchanged after a failed merge, got %v", config)
+	}
+	if err := MergeFrom(config, conflicting, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Clusters["foo"].Server != "https://other" {
+		t.Errorf("expected the conflicting cluster to be overwritten, got %v", config.Clusters["foo"])
+	}
+
+	invalid := clientcmdapi.NewConfig()
+	invalid.Contexts["baz"] = &clientcmdapi.Context{Cluster: "missing", AuthInfo: "foo"}
+	if err := MergeFrom(newConfig(), invalid, false); err == nil || !IsConfigurationInvalid(err) {
+		t.Errorf("expected an invalid configuration error, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+)
+
+const (
+	defaultLRUCacheSize = 4096
+
+	// by default, allow a source to send 25 events about an object
+	// but control the refill rate to 1 new event every 5 minutes,
+	// like the EventCorrelator of k8s.io/client-go/tools/record.
+	defaultSpamBurst = 25
+	defaultSpamQPS   = 1. / 300.
+)
+
+// EventSpamKeyFunc returns the key of the events which share a rate limit.
+type EventSpamKeyFunc func(event *eventsv1.Event) string
+
+// EventAggregatorKeyFunc returns the key of the events which are recorded as
+// a series. The first event with a key is sent to the sink, later events with
+// the same key only increase the count of its series.
+type EventAggregatorKeyFunc func(event *eventsv1.Event) string
+
+// CorrelatorOptions configures the filtering and aggregation of the events of
+// an EventBroadcaster. They match the CorrelatorOptions of
+// k8s.io/client-go/tools/record.
+type CorrelatorOptions struct {
+	// The lru cache size used for the rate limits of EventSourceObjectSpamFilter.
+	// If not specified (zero value), 4096 will be picked.
+	LRUCacheSize int
+	// The burst size used by the token bucket rate filtering in EventSourceObjectSpamFilter.
+	// If not specified (zero value), 25 will be picked.
+	BurstSize int
+	// The fill rate of the token bucket in queries per second in EventSourceObjectSpamFilter.
+	// If not specified (zero value), one event per 5 minutes will be picked.
+	QPS float32
+	// The func used by EventSourceObjectSpamFilter to group events which share a rate limit.
+	// If not specified (zero value), EventSpamKeyByRegardingFunc will be used.
+	SpamKeyFunc EventSpamKeyFunc
+	// The func used to group events into series.
+	// If not specified (zero value), events are grouped by their type, action, reason,
+	// reporting controller and instance, and regarding and related objects.
+	KeyFunc EventAggregatorKeyFunc
+	// The clock used by EventSourceObjectSpamFilter to allow for testing.
+	// If not specified (zero value), clock.RealClock{} will be used.
+	Clock clock.PassiveClock
+}
+
+// populateDefaults populates the zero value options with defaults
+func populateDefaults(options CorrelatorOptions) CorrelatorOptions {
+	if options.LRUCacheSize == 0 {
+		options.LRUCacheSize = defaultLRUCacheSize
+	}
+	if options.BurstSize == 0 {
+		options.BurstSize = defaultSpamBurst
+	}
+	if options.QPS == 0 {
+		options.QPS = defaultSpamQPS
+	}
+	if options.SpamKeyFunc == nil {
+		options.SpamKeyFunc = EventSpamKeyByRegardingFunc
+	}
+	if options.Clock == nil {
+		options.Clock = clock.RealClock{}
+	}
+	return options
+}
+
+// EventSpamKeyByRegardingFunc groups events by their reporting controller and
+// instance, regarding object and type.
+func EventSpamKeyByRegardingFunc(event *eventsv1.Event) string {
+	// Quote the fields, so that they cannot collide when they contain each
+	// other's prefixes or suffixes.
+	return fmt.Sprintf("%q", []string{
+		event.ReportingController,
+		event.ReportingInstance,
+		event.Regarding.Kind,
+		event.Regarding.Namespace,
+		event.Regarding.Name,
+		string(event.Regarding.UID),
+		event.Regarding.APIVersion,
+		event.Type,
+	})
+}
+
+// EventSourceObjectSpamFilter is responsible for throttling
+// the amount of events a source and object can produce. It applies the
+// EventSourceObjectSpamFilter of k8s.io/client-go/tools/record to the keys
+// of events.k8s.io events.
+type EventSourceObjectSpamFilter struct {
+	filter *record.EventSourceObjectSpamFilter
+
+	// spamKeyFunc is a func used to create a key based on an event, which is later used to filter spam events.
+	spamKeyFunc EventSpamKeyFunc
+}
+
+// NewEventSourceObjectSpamFilter allows burst events with the same key with the specified qps refill.
+func NewEventSourceObjectSpamFilter(lruCacheSize, burst int, qps float32, clock clock.PassiveClock, spamKeyFunc EventSpamKeyFunc) *EventSourceObjectSpamFilter {
+	return &EventSourceObjectSpamFilter{
+		filter:      record.NewEventSourceObjectSpamFilter(lruCacheSize, burst, qps, clock, spamKeyOf),
+		spamKeyFunc: spamKeyFunc,
+	}
+}
+
+// Filter returns true if the event exceeds the rate of its key and should be
+// skipped. The event parameter must not be nil.
+func (f *EventSourceObjectSpamFilter) Filter(event *eventsv1.Event) bool {
+	return f.filter.Filter(&corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: f.spamKeyFunc(event)}})
+}
+
+// spamKeyOf is the spam key function of the record filter, which only sees
+// the keys that Filter passes as names of core events.
+func spamKeyOf(event *corev1.Event) string {
+	return event.Name
+}
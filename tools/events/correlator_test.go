@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2/ktesting"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func makeTestEvent(name, reason string) *eventsv1.Event {
+	return &eventsv1.Event{
+		ObjectMeta:          metav1.ObjectMeta{Name: name + "." + reason, Namespace: metav1.NamespaceDefault},
+		EventTime:           metav1.MicroTime{Time: time.Now()},
+		Type:                corev1.EventTypeNormal,
+		Reason:              reason,
+		Action:              "Sync",
+		ReportingController: "test",
+		ReportingInstance:   "test-1",
+		Regarding:           corev1.ObjectReference{Kind: "Pod", Namespace: metav1.NamespaceDefault, Name: name, UID: types.UID("uid-" + name)},
+	}
+}
+
+func TestEventSourceObjectSpamFilter(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	filter := NewEventSourceObjectSpamFilter(10, 2, 1, fakeClock, EventSpamKeyByRegardingFunc)
+
+	for i, tc := range []struct {
+		event    *eventsv1.Event
+		step     time.Duration
+		expected bool
+	}{
+		{event: makeTestEvent("foo", "Started"), expected: false},
+		{event: makeTestEvent("foo", "Pulled"), expected: false},
+		{event: makeTestEvent("foo", "Killed"), expected: true},
+		// Other objects have their own rate limit.
+		{event: makeTestEvent("bar", "Started"), expected: false},
+		{event: makeTestEvent("foo", "Killed"), step: time.Second, expected: false},
+		{event: makeTestEvent("foo", "Killed"), expected: true},
+	} {
+		fakeClock.Step(tc.step)
+		if filtered := filter.Filter(tc.event); filtered != tc.expected {
+			t.Errorf("%d: expected the event %s to be filtered %v, got %v", i, tc.event.Name, tc.expected, filtered)
+		}
+	}
+}
+
+func TestEventSpamKeyByRegardingFunc(t *testing.T) {
+	a := makeTestEvent("foo", "Started")
+	a.ReportingController, a.ReportingInstance = "example.com/a", "b"
+	b := makeTestEvent("foo", "Started")
+	b.ReportingController, b.ReportingInstance = "example.com/", "ab"
+	if EventSpamKeyByRegardingFunc(a) == EventSpamKeyByRegardingFunc(b) {
+		t.Errorf("expected different keys for different reporting controllers, got %q", EventSpamKeyByRegardingFunc(a))
+	}
+	c := makeTestEvent("foo", "Killed")
+	c.ReportingController, c.ReportingInstance = a.ReportingController, a.ReportingInstance
+	if EventSpamKeyByRegardingFunc(a) != EventSpamKeyByRegardingFunc(c) {
+		t.Errorf("expected the reason not to change the key")
+	}
+}
+
+func TestRecordToSinkWithCorrelatorOptions(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	e := newBroadcaster(&testEventSeriesSink{}, 0, map[eventKey]*eventsv1.Event{}, WithCorrelatorOptions(CorrelatorOptions{
+		BurstSize: 3,
+		QPS:       0.1,
+		Clock:     fakeClock,
+		// Events about an object form one series, regardless of their reason.
+		KeyFunc: func(event *eventsv1.Event) string {
+			return event.Regarding.Name
+		},
+	})).(*eventBroadcasterImpl)
+
+	for _, event := range []*eventsv1.Event{
+		makeTestEvent("foo", "Started"),
+		makeTestEvent("foo", "Pulled"),
+		makeTestEvent("foo", "Killed"),
+		// Exceeds the burst of foo and is dropped.
+		makeTestEvent("foo", "Killed"),
+		makeTestEvent("bar", "Started"),
+	} {
+		e.recordToSink(ctx, event, fakeClock)
+	}
+	var recorded []*eventsv1.Event
+	for len(e.eventQueue) > 0 {
+		recorded = append(recorded, <-e.eventQueue)
+	}
+	// The first event of foo is created and turned into a series by the
+	// second one, the third only counts.
+	if len(recorded) != 3 || recorded[0].Reason != "Started" || recorded[0].Series != nil ||
+		recorded[1].Reason != "Started" || recorded[1].Series == nil || recorded[2].Regarding.Name != "bar" {
+		t.Fatalf("unexpected recorded events: %+v", recorded)
+	}
+	if count := e.eventCache[eventKey{aggregateKey: "foo"}].Series.Count; count != 3 {
+		t.Errorf("expected a series of 3 events about foo, got %d", count)
+	}
+
+	fakeClock.Step(10 * time.Second)
+	e.recordToSink(ctx, makeTestEvent("foo", "Killed"), fakeClock)
+	if count := e.eventCache[eventKey{aggregateKey: "foo"}].Series.Count; count != 4 {
+		t.Errorf("expected a series of 4 events about foo after the rate limit refilled, got %d", count)
+	}
+}
+
+func TestRecordToSinkWithoutCorrelatorOptions(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+	e := newBroadcaster(&testEventSeriesSink{}, 0, map[eventKey]*eventsv1.Event{}).(*eventBroadcasterImpl)
+	for i := 0; i < 2*defaultSpamBurst; i++ {
+		e.recordToSink(ctx, makeTestEvent("foo", "Started"), testingclock.NewFakeClock(time.Now()))
+	}
+	if count := e.eventCache[getKey(makeTestEvent("foo", "Started"))].Series.Count; count != 2*defaultSpamBurst {
+		t.Errorf("expected all %d events to be counted, got %d", 2*defaultSpamBurst, count)
+	}
+}
@@ -65,6 +65,9 @@ type eventKey struct {
 	reportingInstance   string
 	regarding           corev1.ObjectReference
 	related             corev1.ObjectReference
+	// aggregateKey is the key of an EventAggregatorKeyFunc, which
+	// replaces the other fields.
+	aggregateKey string
 }
 
 type eventBroadcasterImpl struct {
@@ -75,6 +78,10 @@ type eventBroadcasterImpl struct {
 	sink          EventSink
 	eventQueue    chan *eventsv1.Event
 	cancel        func()
+	// spamFilter drops the events which exceed their rate, it is nil
+	// without CorrelatorOptions.
+	spamFilter *EventSourceObjectSpamFilter
+	keyFunc    EventAggregatorKeyFunc
 }
 
 // EventSinkImpl wraps EventsV1Interface to implement EventSink.
@@ -108,20 +115,47 @@ func (e *EventSinkImpl) Patch(ctx context.Context, event *eventsv1.Event, data [
 	return e.Interface.Events(event.Namespace).Patch(ctx, event.Name, types.StrategicMergePatchType, data, metav1.PatchOptions{})
 }
 
+// BroadcasterOption configures an EventBroadcaster.
+type BroadcasterOption func(*config)
+
+type config struct {
+	correlatorOptions *CorrelatorOptions
+}
+
+// WithCorrelatorOptions makes the broadcaster drop the events which exceed
+// the rate limit of their source and object, and group events into series
+// with the key function of the options. Without it, events are not rate
+// limited.
+func WithCorrelatorOptions(options CorrelatorOptions) BroadcasterOption {
+	return func(c *config) {
+		c.correlatorOptions = &options
+	}
+}
+
 // NewBroadcaster Creates a new event broadcaster.
-func NewBroadcaster(sink EventSink) EventBroadcaster {
-	return newBroadcaster(sink, defaultSleepDuration, map[eventKey]*eventsv1.Event{})
+func NewBroadcaster(sink EventSink, opts ...BroadcasterOption) EventBroadcaster {
+	return newBroadcaster(sink, defaultSleepDuration, map[eventKey]*eventsv1.Event{}, opts...)
 }
 
 // NewBroadcasterForTest Creates a new event broadcaster for test purposes.
-func newBroadcaster(sink EventSink, sleepDuration time.Duration, eventCache map[eventKey]*eventsv1.Event) EventBroadcaster {
-	return &eventBroadcasterImpl{
+func newBroadcaster(sink EventSink, sleepDuration time.Duration, eventCache map[eventKey]*eventsv1.Event, opts ...BroadcasterOption) EventBroadcaster {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	e := &eventBroadcasterImpl{
 		Broadcaster:   watch.NewBroadcaster(maxQueuedEvents, watch.DropIfChannelFull),
 		eventCache:    eventCache,
 		sleepDuration: sleepDuration,
 		sink:          sink,
 		eventQueue:    make(chan *eventsv1.Event, maxQueuedEvents),
 	}
+	if c.correlatorOptions != nil {
+		options := populateDefaults(*c.correlatorOptions)
+		e.spamFilter = NewEventSourceObjectSpamFilter(options.LRUCacheSize, options.BurstSize, options.QPS, options.Clock, options.SpamKeyFunc)
+		e.keyFunc = options.KeyFunc
+	}
+	return e
 }
 
 func (e *eventBroadcasterImpl) Shutdown() {
@@ -223,7 +257,10 @@ func (e *eventBroadcasterImpl) recordToSink(ctx context.Context, event *eventsv1
 	record := func() *eventsv1.Event {
 		e.mu.Lock()
 		defer e.mu.Unlock()
-		eventKey := getKey(eventCopy)
+		if e.spamFilter != nil && e.spamFilter.Filter(eventCopy) {
+			return nil
+		}
+		eventKey := e.getKey(eventCopy)
 		isomorphicEvent, isIsomorphic := e.eventCache[eventKey]
 		if isIsomorphic {
 			if isomorphicEvent.Series != nil {
@@ -357,6 +394,14 @@ func createPatchBytesForSeries(event *eventsv1.Event) ([]byte, error) {
 	return strategicpatch.CreateTwoWayMergePatch(oldData, newData, eventsv1.Event{})
 }
 
+// getKey returns the key of the series of the event.
+func (e *eventBroadcasterImpl) getKey(event *eventsv1.Event) eventKey {
+	if e.keyFunc != nil {
+		return eventKey{aggregateKey: e.keyFunc(event)}
+	}
+	return getKey(event)
+}
+
 func getKey(event *eventsv1.Event) eventKey {
 	key := eventKey{
 		eventType:           event.Type,
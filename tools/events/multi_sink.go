@@ -0,0 +1,156 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	internalevents "k8s.io/client-go/tools/internal/events"
+	"k8s.io/client-go/tools/metrics"
+	"k8s.io/klog/v2"
+)
+
+// EventFilter selects events by their type, reason and the group, version
+// and kind of their regarding object. Empty fields select all events.
+type EventFilter struct {
+	Types   []string
+	Reasons []string
+	// GroupVersionKinds of the regarding objects. A GroupVersionKind
+	// without version selects the objects of all versions.
+	GroupVersionKinds []schema.GroupVersionKind
+}
+
+// Matches returns true if the filter selects the event.
+func (f EventFilter) Matches(event *eventsv1.Event) bool {
+	return internalevents.EventFilter(f).Matches(event.Type, event.Reason, &event.Regarding)
+}
+
+// FilteredSink is a sink of a MultiSink, which only receives the events
+// selected by the filter.
+type FilteredSink struct {
+	Sink   EventSink
+	Filter EventFilter
+}
+
+// MultiSink writes events to a primary sink, usually an EventSinkImpl, and
+// mirrors the written events to other sinks. It allows to emit events to logs,
+// metrics and external systems without an extra event watcher.
+type MultiSink struct {
+	sink internalevents.MultiSink[EventSink, *eventsv1.Event]
+}
+
+var _ EventSink = &MultiSink{}
+
+// NewMultiSink returns a sink which writes events to primary and then to the
+// sinks which select them. Only the result of primary is returned, so the
+// broadcaster retries events based on the result of primary only. Errors of
+// the other sinks are logged. If primary is nil, events are only written to
+// the other sinks.
+func NewMultiSink(primary EventSink, sinks ...FilteredSink) *MultiSink {
+	m := &MultiSink{sink: internalevents.MultiSink[EventSink, *eventsv1.Event]{
+		Primary: primary,
+		Describe: func(event *eventsv1.Event) (string, string, *corev1.ObjectReference) {
+			return event.Type, event.Reason, &event.Regarding
+		},
+	}}
+	for _, sink := range sinks {
+		m.sink.Sinks = append(m.sink.Sinks, internalevents.FilteredSink[EventSink]{Sink: sink.Sink, Filter: internalevents.EventFilter(sink.Filter)})
+	}
+	return m
+}
+
+// Create creates the event with the primary sink and the other sinks.
+func (m *MultiSink) Create(ctx context.Context, event *eventsv1.Event) (*eventsv1.Event, error) {
+	return m.sink.Write(klog.FromContext(ctx), "create", event, func(sink EventSink, event *eventsv1.Event) (*eventsv1.Event, error) {
+		return sink.Create(ctx, event)
+	})
+}
+
+// Update updates the event with the primary sink and the other sinks.
+func (m *MultiSink) Update(ctx context.Context, event *eventsv1.Event) (*eventsv1.Event, error) {
+	return m.sink.Write(klog.FromContext(ctx), "update", event, func(sink EventSink, event *eventsv1.Event) (*eventsv1.Event, error) {
+		return sink.Update(ctx, event)
+	})
+}
+
+// Patch patches the event with the primary sink and the other sinks.
+func (m *MultiSink) Patch(ctx context.Context, event *eventsv1.Event, data []byte) (*eventsv1.Event, error) {
+	return m.sink.Write(klog.FromContext(ctx), "patch", event, func(sink EventSink, event *eventsv1.Event) (*eventsv1.Event, error) {
+		return sink.Patch(ctx, event, data)
+	})
+}
+
+// EventSinkFunc is an EventSink which calls the function for each written
+// event. The function must be safe for concurrent use.
+type EventSinkFunc func(ctx context.Context, event *eventsv1.Event) error
+
+var _ EventSink = EventSinkFunc(nil)
+
+// Create calls f.
+func (f EventSinkFunc) Create(ctx context.Context, event *eventsv1.Event) (*eventsv1.Event, error) {
+	return event, f(ctx, event)
+}
+
+// Update calls f.
+func (f EventSinkFunc) Update(ctx context.Context, event *eventsv1.Event) (*eventsv1.Event, error) {
+	return event, f(ctx, event)
+}
+
+// Patch calls f with the patched event.
+func (f EventSinkFunc) Patch(ctx context.Context, event *eventsv1.Event, data []byte) (*eventsv1.Event, error) {
+	return event, f(ctx, event)
+}
+
+// NewJSONSink returns a sink which writes each event as a line of JSON to w,
+// for example a log file.
+func NewJSONSink(w io.Writer) EventSink {
+	var lock sync.Mutex
+	encoder := json.NewEncoder(w)
+	return EventSinkFunc(func(ctx context.Context, event *eventsv1.Event) error {
+		lock.Lock()
+		defer lock.Unlock()
+		return encoder.Encode(event)
+	})
+}
+
+// NewMetricsSink returns a sink which counts the events with the
+// EventsWritten metric of k8s.io/client-go/tools/metrics by their type and
+// reason. Only created events are counted, updates of event series are not.
+func NewMetricsSink() EventSink {
+	return metricsSink{}
+}
+
+type metricsSink struct{}
+
+func (metricsSink) Create(ctx context.Context, event *eventsv1.Event) (*eventsv1.Event, error) {
+	metrics.EventsWritten.Increment(event.Type, event.Reason)
+	return event, nil
+}
+
+func (metricsSink) Update(ctx context.Context, event *eventsv1.Event) (*eventsv1.Event, error) {
+	return event, nil
+}
+
+func (metricsSink) Patch(ctx context.Context, event *eventsv1.Event, data []byte) (*eventsv1.Event, error) {
+	return event, nil
+}
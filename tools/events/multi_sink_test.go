@@ -0,0 +1,148 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/metrics"
+	"k8s.io/klog/v2/ktesting"
+)
+
+type fakeEventsMetric struct {
+	lock   sync.Mutex
+	counts map[string]int
+}
+
+func (m *fakeEventsMetric) Increment(eventType, reason string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.counts[eventType+"/"+reason]++
+}
+
+func makeMultiSinkTestEvent(kind, apiVersion, eventType, reason string) *eventsv1.Event {
+	return &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "foo." + reason},
+		Regarding:  corev1.ObjectReference{Kind: kind, APIVersion: apiVersion, Namespace: metav1.NamespaceDefault, Name: "foo"},
+		Type:       eventType,
+		Reason:     reason,
+	}
+}
+
+func TestMultiSink(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+	metric := &fakeEventsMetric{counts: map[string]int{}}
+	previous := metrics.EventsWritten
+	metrics.EventsWritten = metric
+	t.Cleanup(func() { metrics.EventsWritten = previous })
+
+	var primaryErr error
+	primary := &testEventSeriesSink{
+		OnCreate: func(e *eventsv1.Event) (*eventsv1.Event, error) {
+			if primaryErr != nil {
+				return nil, primaryErr
+			}
+			written := e.DeepCopy()
+			written.ResourceVersion = "1"
+			return written, nil
+		},
+	}
+	var file bytes.Buffer
+	sink := NewMultiSink(primary,
+		FilteredSink{Sink: NewJSONSink(&file)},
+		FilteredSink{Sink: NewMetricsSink()},
+	)
+
+	if _, err := sink.Create(ctx, makeMultiSinkTestEvent("Pod", "v1", corev1.EventTypeWarning, "BackOff")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := sink.Patch(ctx, makeMultiSinkTestEvent("Pod", "v1", corev1.EventTypeNormal, "Pulled"), []byte("{}")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	primaryErr = fmt.Errorf("apiserver unavailable")
+	if _, err := sink.Create(ctx, makeMultiSinkTestEvent("Pod", "v1", corev1.EventTypeWarning, "Failed")); err != primaryErr {
+		t.Fatalf("expected the error of the primary sink, got %v", err)
+	}
+
+	// Only created events are counted, the event of the failed write is
+	// neither counted nor written to the file.
+	if metric.counts["Warning/BackOff"] != 1 || len(metric.counts) != 1 {
+		t.Errorf("unexpected counts: %v", metric.counts)
+	}
+	decoder := json.NewDecoder(&file)
+	var reasons []string
+	for decoder.More() {
+		var event eventsv1.Event
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		reasons = append(reasons, event.Reason)
+	}
+	if len(reasons) != 2 || reasons[0] != "BackOff" || reasons[1] != "Pulled" {
+		t.Errorf("expected two events in the file, got %v", reasons)
+	}
+}
+
+func TestMultiSinkWithBroadcaster(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	kubeClient := fake.NewSimpleClientset()
+	mirrored := make(chan *eventsv1.Event, 10)
+	sink := NewMultiSink(&EventSinkImpl{Interface: kubeClient.EventsV1()}, FilteredSink{
+		Sink: EventSinkFunc(func(ctx context.Context, event *eventsv1.Event) error {
+			mirrored <- event
+			return nil
+		}),
+		Filter: EventFilter{GroupVersionKinds: []schema.GroupVersionKind{{Version: "v1", Kind: "Pod"}}},
+	})
+	eventBroadcaster := NewBroadcaster(sink)
+	defer eventBroadcaster.Shutdown()
+	if err := eventBroadcaster.StartRecordingToSinkWithContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, "test")
+	recorder.Eventf(&corev1.ObjectReference{Kind: "Node", APIVersion: "v1", Name: "node"}, nil, corev1.EventTypeNormal, "Ready", "Sync", "")
+	recorder.Eventf(&corev1.ObjectReference{Kind: "Pod", APIVersion: "v1", Namespace: metav1.NamespaceDefault, Name: "foo"}, nil, corev1.EventTypeNormal, "Started", "Sync", "")
+
+	select {
+	case event := <-mirrored:
+		if event.Reason != "Started" || event.Name == "" {
+			t.Errorf("expected the created event about the pod, got %+v", event)
+		}
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("timed out waiting for the mirrored event")
+	}
+	select {
+	case event := <-mirrored:
+		t.Errorf("expected only the event about the pod, got %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+)
+
+// EventFilter is the filter of the MultiSinks of k8s.io/client-go/tools/record
+// and k8s.io/client-go/tools/events, which convert their filters to it.
+type EventFilter struct {
+	Types             []string
+	Reasons           []string
+	GroupVersionKinds []schema.GroupVersionKind
+}
+
+// Matches returns true if the filter selects an event with the given type,
+// reason and object.
+func (f EventFilter) Matches(eventType, reason string, object *corev1.ObjectReference) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, eventType) {
+		return false
+	}
+	if len(f.Reasons) > 0 && !slices.Contains(f.Reasons, reason) {
+		return false
+	}
+	if len(f.GroupVersionKinds) > 0 {
+		gvk := schema.FromAPIVersionAndKind(object.APIVersion, object.Kind)
+		return slices.ContainsFunc(f.GroupVersionKinds, func(selected schema.GroupVersionKind) bool {
+			return selected.Group == gvk.Group && selected.Kind == gvk.Kind && (selected.Version == "" || selected.Version == gvk.Version)
+		})
+	}
+	return true
+}
+
+// FilteredSink is a sink of type S which only receives the events selected
+// by the filter.
+type FilteredSink[S any] struct {
+	Sink   S
+	Filter EventFilter
+}
+
+// MultiSink writes events of type E to a primary sink of type S, which is an
+// interface, and mirrors the written events to other sinks.
+type MultiSink[S any, E interface {
+	klog.KMetadata
+	DeepCopy() E
+}] struct {
+	// Primary is the sink whose result is returned. If nil, events are only
+	// written to Sinks.
+	Primary S
+	Sinks   []FilteredSink[S]
+	// Describe returns the fields of an event which filters select by.
+	Describe func(event E) (eventType, reason string, object *corev1.ObjectReference)
+}
+
+// Write writes the event to the primary sink and, if that succeeds, copies of
+// the event which it returned to the other sinks which select it. Errors of
+// the other sinks are logged, only the result of the primary sink is
+// returned.
+func (m *MultiSink[S, E]) Write(logger klog.Logger, verb string, event E, write func(sink S, event E) (E, error)) (E, error) {
+	written := event
+	if any(m.Primary) != nil {
+		var err error
+		written, err = write(m.Primary, event)
+		if err != nil {
+			return written, err
+		}
+	}
+	eventType, reason, object := m.Describe(written)
+	for _, sink := range m.Sinks {
+		if !sink.Filter.Matches(eventType, reason, object) {
+			continue
+		}
+		// Sinks must not modify the event of the primary sink.
+		if _, err := write(sink.Sink, written.DeepCopy()); err != nil {
+			logger.Error(err, "Unable to write event to sink", "verb", verb, "event", klog.KObj(written))
+		}
+	}
+	return written, nil
+}
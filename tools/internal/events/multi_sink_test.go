@@ -0,0 +1,170 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2/ktesting"
+)
+
+func makeMultiSinkTestEvent(kind, apiVersion, eventType, reason string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "foo." + reason},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, APIVersion: apiVersion, Namespace: metav1.NamespaceDefault, Name: "foo"},
+		Type:           eventType,
+		Reason:         reason,
+	}
+}
+
+func TestEventFilter(t *testing.T) {
+	pod := makeMultiSinkTestEvent("Pod", "v1", corev1.EventTypeWarning, "BackOff")
+	deployment := makeMultiSinkTestEvent("Deployment", "apps/v1", corev1.EventTypeNormal, "ScalingReplicaSet")
+	for name, tc := range map[string]struct {
+		filter   EventFilter
+		expected []*corev1.Event
+	}{
+		"empty": {
+			expected: []*corev1.Event{pod, deployment},
+		},
+		"type": {
+			filter:   EventFilter{Types: []string{corev1.EventTypeWarning}},
+			expected: []*corev1.Event{pod},
+		},
+		"reason": {
+			filter:   EventFilter{Reasons: []string{"ScalingReplicaSet", "Other"}},
+			expected: []*corev1.Event{deployment},
+		},
+		"kind of all versions": {
+			filter:   EventFilter{GroupVersionKinds: []schema.GroupVersionKind{{Group: "apps", Kind: "Deployment"}}},
+			expected: []*corev1.Event{deployment},
+		},
+		"kind of another version": {
+			filter: EventFilter{GroupVersionKinds: []schema.GroupVersionKind{{Group: "apps", Version: "v1beta1", Kind: "Deployment"}}},
+		},
+		"core kind": {
+			filter:   EventFilter{GroupVersionKinds: []schema.GroupVersionKind{{Version: "v1", Kind: "Pod"}}},
+			expected: []*corev1.Event{pod},
+		},
+		"all fields": {
+			filter: EventFilter{Types: []string{corev1.EventTypeWarning}, Reasons: []string{"ScalingReplicaSet"}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var matched []*corev1.Event
+			for _, event := range []*corev1.Event{pod, deployment} {
+				if tc.filter.Matches(event.Type, event.Reason, &event.InvolvedObject) {
+					matched = append(matched, event)
+				}
+			}
+			if len(matched) != len(tc.expected) || (len(matched) > 0 && matched[0] != tc.expected[0]) {
+				t.Errorf("expected the events %v, got %v", tc.expected, matched)
+			}
+		})
+	}
+}
+
+type testSink interface {
+	Write(event *corev1.Event) (*corev1.Event, error)
+}
+
+type testSinkFunc func(event *corev1.Event) (*corev1.Event, error)
+
+func (f testSinkFunc) Write(event *corev1.Event) (*corev1.Event, error) {
+	return f(event)
+}
+
+func writeToTestSink(sink testSink, event *corev1.Event) (*corev1.Event, error) {
+	return sink.Write(event)
+}
+
+func describeTestEvent(event *corev1.Event) (string, string, *corev1.ObjectReference) {
+	return event.Type, event.Reason, &event.InvolvedObject
+}
+
+func TestMultiSink(t *testing.T) {
+	logger, _ := ktesting.NewTestContext(t)
+	var primaryErr error
+	primary := testSinkFunc(func(event *corev1.Event) (*corev1.Event, error) {
+		if primaryErr != nil {
+			return nil, primaryErr
+		}
+		written := event.DeepCopy()
+		written.ResourceVersion = "1"
+		return written, nil
+	})
+	var all, warnings []*corev1.Event
+	sink := MultiSink[testSink, *corev1.Event]{
+		Primary: primary,
+		Sinks: []FilteredSink[testSink]{
+			{Sink: testSinkFunc(func(event *corev1.Event) (*corev1.Event, error) {
+				all = append(all, event)
+				return event, nil
+			})},
+			{
+				Sink: testSinkFunc(func(event *corev1.Event) (*corev1.Event, error) {
+					warnings = append(warnings, event)
+					event.Reason = "Modified"
+					return nil, fmt.Errorf("sink failed")
+				}),
+				Filter: EventFilter{Types: []string{corev1.EventTypeWarning}},
+			},
+		},
+		Describe: describeTestEvent,
+	}
+
+	written, err := sink.Write(logger, "create", makeMultiSinkTestEvent("Pod", "v1", corev1.EventTypeWarning, "BackOff"), writeToTestSink)
+	if err != nil {
+		t.Fatalf("expected errors of the other sinks to be ignored, got %v", err)
+	}
+	if written.ResourceVersion != "1" || written.Reason != "BackOff" {
+		t.Errorf("expected the unmodified event of the primary sink, got %+v", written)
+	}
+	if _, err := sink.Write(logger, "patch", makeMultiSinkTestEvent("Pod", "v1", corev1.EventTypeNormal, "Pulled"), writeToTestSink); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	primaryErr = fmt.Errorf("apiserver unavailable")
+	if _, err := sink.Write(logger, "create", makeMultiSinkTestEvent("Pod", "v1", corev1.EventTypeWarning, "Failed"), writeToTestSink); err != primaryErr {
+		t.Fatalf("expected the error of the primary sink, got %v", err)
+	}
+
+	// The event of the failed write is not mirrored, the broadcaster
+	// retries it.
+	if len(all) != 2 || all[0].Reason != "BackOff" || all[1].Reason != "Pulled" {
+		t.Errorf("expected the BackOff and Pulled events, got %v", all)
+	}
+	if len(all) > 0 && (all[0] == written || all[0].ResourceVersion != "1") {
+		t.Errorf("expected a copy of the event of the primary sink, got %+v", all[0])
+	}
+	if len(warnings) != 1 || warnings[0].Reason != "Modified" {
+		t.Errorf("expected only the BackOff warning, got %v", warnings)
+	}
+
+	// Without primary sink, events are only mirrored.
+	all = nil
+	sink.Primary = nil
+	if _, err := sink.Write(logger, "update", makeMultiSinkTestEvent("Pod", "v1", corev1.EventTypeNormal, "Started"), writeToTestSink); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 1 || all[0].Reason != "Started" {
+		t.Errorf("expected the event to be mirrored, got %v", all)
+	}
+}
@@ -17,7 +17,9 @@ limitations under the License.
 // Package leaderelection implements leader election of a set of endpoints.
 // It uses an annotation in the endpoints object to store the record of the
 // election state. This implementation does not guarantee that only one
-// client is acting as a leader (a.k.a. fencing).
+// client is acting as a leader (a.k.a. fencing). Leaders can however pass the
+// epoch from LeaderCallbacks.OnStartedLeadingWithEpoch along with their writes,
+// so that the receivers can reject the writes of earlier leaders.
 //
 // A client only acts on timestamps captured locally to infer the state of the
 // leader election. The client does not consider timestamps in the leader
@@ -89,7 +91,7 @@ func NewLeaderElector(lec LeaderElectionConfig) (*LeaderElector, error) {
 	if lec.RetryPeriod < 1 {
 		return nil, fmt.Errorf("retryPeriod must be greater than zero")
 	}
-	if lec.Callbacks.OnStartedLeading == nil {
+	if lec.Callbacks.OnStartedLeading == nil && lec.Callbacks.OnStartedLeadingWithEpoch == nil {
 		return nil, fmt.Errorf("OnStartedLeading callback must not be nil")
 	}
 	if lec.Callbacks.OnStoppedLeading == nil {
@@ -171,8 +173,17 @@ type LeaderElectionConfig struct {
 // possible future callbacks:
 //   - OnChallenge()
 type LeaderCallbacks struct {
-	// OnStartedLeading is called when a LeaderElector client starts leading
+	// OnStartedLeading is called when a LeaderElector client starts leading.
+	// The context is cancelled when the client stops leading, at the latest
+	// RenewDeadline after the last successful renewal of the lease, which is
+	// before other clients can take the lease over.
 	OnStartedLeading func(context.Context)
+	// OnStartedLeadingWithEpoch is called instead of OnStartedLeading if set.
+	// The epoch is the number of leader transitions of the lease when the
+	// client acquired it. It increases whenever the lease changes hands, so it
+	// can be used as a fencing token as long as the lease isn't deleted and
+	// the identity of each client is unique.
+	OnStartedLeadingWithEpoch func(ctx context.Context, epoch int)
 	// OnStoppedLeading is called when a LeaderElector client stops leading.
 	// This callback is always called when the LeaderElector exits, even if it did not start leading.
 	// Users should not assume that OnStoppedLeading is only called after OnStartedLeading.
@@ -202,9 +213,30 @@ type LeaderElector struct {
 	// used to lock the observedRecord and the observedTime
 	observedRecordLock sync.RWMutex
 
+	// term is the current term of leadership of this client, nil when the
+	// client isn't leading. It is protected by termLock.
+	term     *leaderTerm
+	termLock sync.Mutex
+
 	metrics leaderMetricsAdapter
 }
 
+// leaderTerm is a term of leadership of a client.
+type leaderTerm struct {
+	// cancel cancels the context of OnStartedLeading and stops renewing.
+	cancel context.CancelFunc
+	// done is closed when OnStartedLeading has returned.
+	done <-chan struct{}
+	// handover is the requested hand over of the lease, if any.
+	handover *handoverRequest
+}
+
+type handoverRequest struct {
+	ctx       context.Context
+	candidate string
+	result    chan<- error
+}
+
 // Run starts the leader election loop. Run will not return
 // before leader election loop is stopped by ctx or it has
 // stopped holding the leader lease
@@ -217,7 +249,18 @@ func (le *LeaderElector) Run(ctx context.Context) {
 	}
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
-	go le.config.Callbacks.OnStartedLeading(ctx)
+	epoch := le.getObservedRecord().LeaderTransitions
+	done := make(chan struct{})
+	le.setTerm(&leaderTerm{cancel: cancel, done: done})
+	go le.cancelBeforeExpiry(ctx, cancel)
+	go func() {
+		defer close(done)
+		if le.config.Callbacks.OnStartedLeadingWithEpoch != nil {
+			le.config.Callbacks.OnStartedLeadingWithEpoch(ctx, epoch)
+		} else {
+			le.config.Callbacks.OnStartedLeading(ctx)
+		}
+	}()
 	le.renew(ctx)
 }
 
@@ -305,12 +348,121 @@ func (le *LeaderElector) renew(ctx context.Context) {
 		cancel()
 	}, le.config.RetryPeriod)
 
-	// if we hold the lease, give it up
-	if le.config.ReleaseOnCancel {
+	// if we hold the lease, hand it over or give it up
+	if term := le.setTerm(nil); term != nil && term.handover != nil {
+		term.handover.result <- le.handOver(term)
+	} else if le.config.ReleaseOnCancel {
 		le.release(logger)
 	}
 }
 
+// HandOver stops leading and passes the lease to the candidate with the given
+// identity. It cancels the context of OnStartedLeading and waits for it to
+// return before it updates the lease, so that the work guarded by the lease
+// has stopped when the candidate takes over. Other candidates don't acquire
+// the lease until it expires, in case the candidate doesn't take it. Clients
+// of versions which don't support hand overs acquire it regardless.
+//
+// HandOver returns an error if the client isn't leading, if ctx is done
+// before the hand over completed or if the lease couldn't be updated. Run
+// returns in any case, as the client stopped leading.
+func (le *LeaderElector) HandOver(ctx context.Context, candidate string) error {
+	if le.config.Coordinated {
+		return fmt.Errorf("hand over is not supported with coordinated leader election")
+	}
+	if candidate == "" || candidate == le.config.Lock.Identity() {
+		return fmt.Errorf("invalid candidate %q", candidate)
+	}
+	result := make(chan error, 1)
+	le.termLock.Lock()
+	term := le.term
+	if term == nil || term.handover != nil {
+		le.termLock.Unlock()
+		return fmt.Errorf("not leading on lease %s", le.config.Lock.Describe())
+	}
+	term.handover = &handoverRequest{ctx: ctx, candidate: candidate, result: result}
+	le.termLock.Unlock()
+
+	term.cancel()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// handOver waits until OnStartedLeading of the term has returned and marks
+// the lease as free for the candidate of the requested hand over.
+func (le *LeaderElector) handOver(term *leaderTerm) error {
+	ctx, candidate := term.handover.ctx, term.handover.candidate
+	logger := klog.FromContext(ctx)
+	select {
+	case <-term.done:
+	case <-ctx.Done():
+		return fmt.Errorf("failed to wait for OnStartedLeading to return: %w", ctx.Err())
+	}
+	for {
+		oldLeaderElectionRecord, _, err := le.config.Lock.Get(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get lease lock %s: %w", le.config.Lock.Describe(), err)
+		}
+		if oldLeaderElectionRecord.HolderIdentity != le.config.Lock.Identity() {
+			return fmt.Errorf("lease lock %s is held by %q", le.config.Lock.Describe(), oldLeaderElectionRecord.HolderIdentity)
+		}
+		now := metav1.NewTime(le.clock.Now())
+		leaderElectionRecord := rl.LeaderElectionRecord{
+			LeaseDurationSeconds: oldLeaderElectionRecord.LeaseDurationSeconds,
+			AcquireTime:          now,
+			RenewTime:            now,
+			LeaderTransitions:    oldLeaderElectionRecord.LeaderTransitions,
+			PreferredHolder:      candidate,
+		}
+		if err := le.config.Lock.Update(ctx, leaderElectionRecord); err != nil {
+			if errors.IsConflict(err) {
+				logger.V(4).Info("Conflict when handing over lease, retrying", "lock", le.config.Lock.Describe())
+				continue
+			}
+			return fmt.Errorf("failed to hand over lease lock %s: %w", le.config.Lock.Describe(), err)
+		}
+		le.setObservedRecord(&leaderElectionRecord)
+		logger.Info("Handed over lease", "lock", le.config.Lock.Describe(), "candidate", candidate)
+		return nil
+	}
+}
+
+// cancelBeforeExpiry calls cancel when the lease wasn't renewed for
+// RenewDeadline or when another client holds it, whichever comes first.
+// Other clients wait for the longer LeaseDuration before they take the lease
+// over, measured from when they observed the last renewal.
+func (le *LeaderElector) cancelBeforeExpiry(ctx context.Context, cancel context.CancelFunc) {
+	logger := klog.FromContext(ctx)
+	for {
+		record := le.getObservedRecord()
+		if record.HolderIdentity != le.config.Lock.Identity() {
+			cancel()
+			return
+		}
+		deadline := le.config.RenewDeadline
+		if leaseDuration := time.Duration(record.LeaseDurationSeconds) * time.Second; leaseDuration < deadline {
+			deadline = leaseDuration
+		}
+		remaining := record.RenewTime.Add(deadline).Sub(le.clock.Now())
+		if remaining <= 0 {
+			logger.Info("Lease was not renewed in time, stopping leading", "lock", le.config.Lock.Describe())
+			cancel()
+			return
+		}
+		timer := le.clock.NewTimer(remaining)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+	}
+}
+
 // release attempts to release the leader lease if we have acquired it.
 // It retries on conflict, which may occur if the context cancellation
 // races with an inflight renew() operation. The client will see a ctx
@@ -493,6 +645,11 @@ func (le *LeaderElector) tryAcquireOrRenew(ctx context.Context) bool {
 		logger.V(4).Info("Lease is held by and has not yet expired", "lock", le.config.Lock.Describe(), "holder", oldLeaderElectionRecord.HolderIdentity)
 		return false
 	}
+	if len(oldLeaderElectionRecord.HolderIdentity) == 0 && len(oldLeaderElectionRecord.PreferredHolder) > 0 &&
+		oldLeaderElectionRecord.PreferredHolder != le.config.Lock.Identity() && le.isLeaseValid(now.Time) {
+		logger.V(4).Info("Lease is handed over to another candidate and has not yet expired", "lock", le.config.Lock.Describe(), "candidate", oldLeaderElectionRecord.PreferredHolder)
+		return false
+	}
 
 	// 4. We're going to try to update. The leaderElectionRecord is set to it's default
 	// here. Let's correct it before updating.
@@ -563,6 +720,16 @@ func (le *LeaderElector) setObservedRecord(observedRecord *rl.LeaderElectionReco
 	le.observedTime = le.clock.Now()
 }
 
+// setTerm sets the current term of leadership and returns the previous one.
+func (le *LeaderElector) setTerm(term *leaderTerm) *leaderTerm {
+	le.termLock.Lock()
+	defer le.termLock.Unlock()
+
+	previous := le.term
+	le.term = term
+	return previous
+}
+
 // getObservedRecord returns observersRecord.
 // Protect critical sections with lock.
 func (le *LeaderElector) getObservedRecord() rl.LeaderElectionRecord {
@@ -39,6 +39,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2/ktesting"
 	"k8s.io/utils/clock"
+	testingclock "k8s.io/utils/clock/testing"
 )
 
 func createLockObject(t *testing.T, objectType, namespace, name string, record *rl.LeaderElectionRecord) (obj runtime.Object) {
@@ -224,6 +225,27 @@ func testTryAcquireOrRenew(t *testing.T, objectType string) {
 			transitionLeader: true,
 			outHolder:        "baz",
 		},
+		{
+			name: "acquire from object handed over to us",
+			reactors: []Reactor{
+				{
+					verb: "get",
+					reaction: func(action fakeclient.Action) (handled bool, ret runtime.Object, err error) {
+						return true, createLockObject(t, objectType, action.GetNamespace(), action.(fakeclient.GetAction).GetName(), &rl.LeaderElectionRecord{PreferredHolder: "baz", LeaseDurationSeconds: 10}), nil
+					},
+				},
+				{
+					verb: "update",
+					reaction: func(action fakeclient.Action) (handled bool, ret runtime.Object, err error) {
+						return true, action.(fakeclient.CreateAction).GetObject(), nil
+					},
+				},
+			},
+
+			expectSuccess:    true,
+			transitionLeader: true,
+			outHolder:        "baz",
+		},
 		{
 			name: "don't acquire from led, acked object",
 			reactors: []Reactor{
@@ -1325,3 +1347,144 @@ func TestCheckMethodRace(t *testing.T) {
 		time.Sleep(10 * time.Millisecond)
 	}
 }
+
+func TestHandOver(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	c := fake.NewClientset()
+
+	type candidate struct {
+		elector *LeaderElector
+		epochs  chan int
+		stopped chan struct{}
+	}
+	runCandidate := func(identity string) *candidate {
+		lock, err := rl.New("leases", "foo", "bar", c.CoreV1(), c.CoordinationV1(), rl.ResourceLockConfig{Identity: identity})
+		if err != nil {
+			t.Fatal("resourcelock.New() = ", err)
+		}
+		cand := &candidate{epochs: make(chan int, 1), stopped: make(chan struct{})}
+		cand.elector, err = NewLeaderElector(LeaderElectionConfig{
+			Lock:          lock,
+			LeaseDuration: 60 * time.Second,
+			RenewDeadline: 30 * time.Second,
+			RetryPeriod:   10 * time.Millisecond,
+			Callbacks: LeaderCallbacks{
+				OnStartedLeadingWithEpoch: func(ctx context.Context, epoch int) {
+					cand.epochs <- epoch
+					<-ctx.Done()
+				},
+				OnStoppedLeading: func() {},
+			},
+		})
+		if err != nil {
+			t.Fatal("Failed to create leader elector: ", err)
+		}
+		go func() {
+			defer close(cand.stopped)
+			cand.elector.Run(ctx)
+		}()
+		return cand
+	}
+	expectEpoch := func(cand *candidate, expected int) {
+		t.Helper()
+		select {
+		case epoch := <-cand.epochs:
+			if epoch != expected {
+				t.Errorf("expected epoch %d, got %d", expected, epoch)
+			}
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatal("failed to become the leader")
+		}
+	}
+
+	baz := runCandidate("baz")
+	expectEpoch(baz, 0)
+	qux := runCandidate("qux")
+	other := runCandidate("other")
+
+	if err := qux.elector.HandOver(ctx, "other"); err == nil {
+		t.Error("expected an error when handing over without leading")
+	}
+	if err := baz.elector.HandOver(ctx, "baz"); err == nil {
+		t.Error("expected an error when handing over to the leader itself")
+	}
+	if err := baz.elector.HandOver(ctx, "qux"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-baz.stopped:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("the elector didn't stop after handing over")
+	}
+
+	expectEpoch(qux, 1)
+	if !qux.elector.IsLeader() || other.elector.IsLeader() {
+		t.Errorf("expected qux to lead, got %q", other.elector.GetLeader())
+	}
+	select {
+	case <-other.epochs:
+		t.Error("expected the lease to be handed over to qux only")
+	default:
+	}
+	lease, err := c.CoordinationV1().Leases("foo").Get(ctx, "bar", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *lease.Spec.HolderIdentity != "qux" || *lease.Spec.LeaseTransitions != 1 || lease.Spec.PreferredHolder != nil {
+		t.Errorf("unexpected lease after the hand over: %+v", lease.Spec)
+	}
+}
+
+func TestCancelBeforeExpiry(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	le := &LeaderElector{
+		config: LeaderElectionConfig{
+			Lock:          &rl.LeaseLock{LockConfig: rl.ResourceLockConfig{Identity: "baz"}},
+			RenewDeadline: 10 * time.Second,
+		},
+		clock: fakeClock,
+	}
+	renew := func(holder string) {
+		le.setObservedRecord(&rl.LeaderElectionRecord{HolderIdentity: holder, LeaseDurationSeconds: 15, RenewTime: metav1.NewTime(fakeClock.Now())})
+	}
+	_, ctx := ktesting.NewTestContext(t)
+	waitForTimer := func() {
+		t.Helper()
+		if err := wait.PollUntilContextTimeout(ctx, time.Millisecond, wait.ForeverTestTimeout, true, func(context.Context) (bool, error) {
+			return fakeClock.HasWaiters(), nil
+		}); err != nil {
+			t.Fatal("lease expiry timer wasn't started")
+		}
+	}
+	expectCancelled := func(ctx context.Context) {
+		t.Helper()
+		select {
+		case <-ctx.Done():
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatal("context wasn't cancelled")
+		}
+	}
+
+	renew("baz")
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go le.cancelBeforeExpiry(leaderCtx, cancel)
+	waitForTimer()
+	fakeClock.Step(5 * time.Second)
+	renew("baz")
+	fakeClock.Step(6 * time.Second)
+	waitForTimer()
+	if err := leaderCtx.Err(); err != nil {
+		t.Fatalf("context was cancelled although the lease was renewed: %v", err)
+	}
+	fakeClock.Step(4 * time.Second)
+	expectCancelled(leaderCtx)
+
+	renew("bing")
+	leaderCtx, cancel = context.WithCancel(ctx)
+	defer cancel()
+	go le.cancelBeforeExpiry(leaderCtx, cancel)
+	expectCancelled(leaderCtx)
+}
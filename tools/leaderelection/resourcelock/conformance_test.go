@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcelock_test
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/leaderelection/resourcelock/resourcelocktest"
+)
+
+func TestLeaseLockConformance(t *testing.T) {
+	resourcelocktest.RunConformanceTests(t, func(t *testing.T) func(resourcelock.ResourceLockConfig) resourcelock.Interface {
+		// The tracker of fake.NewClientset doesn't detect conflicts.
+		tracker := clienttesting.NewObjectTrackerWithOptions(scheme.Scheme, scheme.Codecs.UniversalDecoder(), clienttesting.ObjectTrackerOptions{
+			StrictResourceVersions: true,
+		})
+		client := &fake.Clientset{}
+		client.AddReactor("*", "*", clienttesting.ObjectReaction(tracker))
+		return func(config resourcelock.ResourceLockConfig) resourcelock.Interface {
+			return &resourcelock.LeaseLock{
+				LeaseMeta:  metav1.ObjectMeta{Namespace: "kube-system", Name: "lock"},
+				Client:     client.CoordinationV1(),
+				LockConfig: config,
+			}
+		}
+	})
+}
+
+func TestMemoryLockConformance(t *testing.T) {
+	resourcelocktest.RunConformanceTests(t, func(t *testing.T) func(resourcelock.ResourceLockConfig) resourcelock.Interface {
+		store := resourcelock.NewMemoryLockStore()
+		return func(config resourcelock.ResourceLockConfig) resourcelock.Interface {
+			return store.Lock("lock", config)
+		}
+	})
+}
+
+func TestFileLockConformance(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file locks are not supported on windows")
+	}
+	resourcelocktest.RunConformanceTests(t, func(t *testing.T) func(resourcelock.ResourceLockConfig) resourcelock.Interface {
+		path := filepath.Join(t.TempDir(), "lock")
+		return func(config resourcelock.ResourceLockConfig) resourcelock.Interface {
+			return resourcelock.NewFileLock(path, config)
+		}
+	})
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcelock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var fileLockResource = schema.GroupResource{Resource: "filelocks"}
+
+// FileLock is a lock which stores the election record in a file, for leader
+// election between the processes of a single host. Writes are serialized with
+// flock(2) on a second file with the suffix ".lock", so FileLock is only
+// supported on platforms which have it, and the files must not be on a
+// network file system.
+type FileLock struct {
+	path   string
+	config ResourceLockConfig
+
+	// lock protects version, the version of the record the lock last
+	// observed, which is 0 if it didn't observe the record yet.
+	lock    sync.Mutex
+	version int64
+}
+
+var _ Interface = &FileLock{}
+
+// fileRecord is the content of the file of a FileLock.
+type fileRecord struct {
+	Version int64                `json:"version"`
+	Record  LeaderElectionRecord `json:"record"`
+}
+
+// NewFileLock returns a lock on the record stored in the file at path.
+func NewFileLock(path string, config ResourceLockConfig) *FileLock {
+	return &FileLock{path: path, config: config}
+}
+
+// Get returns the election record.
+func (fl *FileLock) Get(ctx context.Context) (*LeaderElectionRecord, []byte, error) {
+	stored, err := fl.read()
+	if err != nil {
+		return nil, nil, err
+	}
+	fl.lock.Lock()
+	fl.version = stored.Version
+	fl.lock.Unlock()
+	recordBytes, err := json.Marshal(stored.Record)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &stored.Record, recordBytes, nil
+}
+
+// Create creates the election record.
+func (fl *FileLock) Create(ctx context.Context, ler LeaderElectionRecord) error {
+	return fl.withFileLock(func() error {
+		if _, err := os.Stat(fl.path); err == nil {
+			return apierrors.NewAlreadyExists(fileLockResource, fl.path)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		return fl.write(fileRecord{Version: 1, Record: ler})
+	})
+}
+
+// Update updates the election record if it is unchanged since the lock last
+// got, created or updated it.
+func (fl *FileLock) Update(ctx context.Context, ler LeaderElectionRecord) error {
+	fl.lock.Lock()
+	version := fl.version
+	fl.lock.Unlock()
+	if version == 0 {
+		return errors.New("record not initialized, call get or create first")
+	}
+	return fl.withFileLock(func() error {
+		stored, err := fl.read()
+		if err != nil {
+			return err
+		}
+		if stored.Version != version {
+			return apierrors.NewConflict(fileLockResource, fl.path, errors.New("the record has been modified"))
+		}
+		return fl.write(fileRecord{Version: version + 1, Record: ler})
+	})
+}
+
+// RecordEvent records an event about a lease with the base name of the file.
+func (fl *FileLock) RecordEvent(s string) {
+	recordLeaseEvent(fl.config, metav1.ObjectMeta{Name: filepath.Base(fl.path)}, s)
+}
+
+// Describe returns the path of the file.
+func (fl *FileLock) Describe() string {
+	return fmt.Sprintf("file/%s", fl.path)
+}
+
+// Identity returns the Identity of the lock.
+func (fl *FileLock) Identity() string {
+	return fl.config.Identity
+}
+
+// withFileLock calls f while it holds the lock on the file.
+func (fl *FileLock) withFileLock(f func() error) error {
+	unlock, err := lockFile(fl.path + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock %s: %w", fl.path, err)
+	}
+	defer unlock()
+	return f()
+}
+
+func (fl *FileLock) read() (*fileRecord, error) {
+	data, err := os.ReadFile(fl.path)
+	if os.IsNotExist(err) {
+		return nil, apierrors.NewNotFound(fileLockResource, fl.path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	stored := &fileRecord{}
+	if err := json.Unmarshal(data, stored); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", fl.path, err)
+	}
+	return stored, nil
+}
+
+// write replaces the file, so that reads without the lock on the file see
+// either the previous or the new record.
+func (fl *FileLock) write(stored fileRecord) error {
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(fl.path), filepath.Base(fl.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), fl.path); err != nil {
+		return err
+	}
+	fl.lock.Lock()
+	fl.version = stored.Version
+	fl.lock.Unlock()
+	return nil
+}
@@ -18,7 +18,9 @@ package resourcelock
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/coordination/v1"
@@ -99,31 +101,101 @@ type Interface interface {
 	Describe() string
 }
 
+// BackendConfig is the configuration of a lock which a Backend creates.
+type BackendConfig struct {
+	// Namespace and Name identify the lock.
+	Namespace string
+	Name      string
+
+	// CoreClient and CoordinationClient are the clients which were passed
+	// to New. Backends which don't store locks in the apiserver ignore them.
+	CoreClient         corev1.CoreV1Interface
+	CoordinationClient coordinationv1.CoordinationV1Interface
+
+	// LockConfig is the configuration common to all locks.
+	LockConfig ResourceLockConfig
+
+	// Labels are the labels which the holder of the lock applies, if the
+	// backend supports labels.
+	Labels map[string]string
+}
+
+// Backend creates the locks of a lock type.
+type Backend func(config BackendConfig) (Interface, error)
+
+var (
+	backendsLock sync.RWMutex
+	backends     = map[string]Backend{
+		LeasesResourceLock: func(config BackendConfig) (Interface, error) {
+			return &LeaseLock{
+				LeaseMeta: metav1.ObjectMeta{
+					Namespace: config.Namespace,
+					Name:      config.Name,
+				},
+				Client:     config.CoordinationClient,
+				LockConfig: config.LockConfig,
+				Labels:     config.Labels,
+			}, nil
+		},
+	}
+
+	// removedResourceLocks are the lock types which are no longer supported.
+	removedResourceLocks = map[string]string{
+		endpointsResourceLock:        fmt.Sprintf("endpoints lock is removed, migrate to %s", LeasesResourceLock),
+		configMapsResourceLock:       fmt.Sprintf("configmaps lock is removed, migrate to %s", LeasesResourceLock),
+		endpointsLeasesResourceLock:  fmt.Sprintf("endpointsleases lock is removed, migrate to %s", LeasesResourceLock),
+		configMapsLeasesResourceLock: fmt.Sprintf("configmapsleases lock is removed, migrated to %s", LeasesResourceLock),
+	}
+)
+
+// RegisterBackend registers the backend which creates the locks of lockType,
+// so that New, NewWithLabels and NewFromKubeconfig support the lock type. It
+// returns an error if the lock type is already registered. Implementations
+// can be verified with the resourcelocktest package.
+//
+// Backends are usually registered in an init function:
+//
+//	func init() {
+//		resourcelock.RegisterBackend("files", func(config resourcelock.BackendConfig) (resourcelock.Interface, error) {
+//			path := filepath.Join(lockDir, config.Namespace+"_"+config.Name)
+//			return resourcelock.NewFileLock(path, config.LockConfig), nil
+//		})
+//	}
+func RegisterBackend(lockType string, backend Backend) error {
+	if backend == nil {
+		return errors.New("backend must not be nil")
+	}
+	if _, ok := removedResourceLocks[lockType]; ok {
+		return fmt.Errorf("lock type %s is reserved", lockType)
+	}
+	backendsLock.Lock()
+	defer backendsLock.Unlock()
+	if _, ok := backends[lockType]; ok {
+		return fmt.Errorf("lock type %s is already registered", lockType)
+	}
+	backends[lockType] = backend
+	return nil
+}
+
 // new will create a lock of a given type according to the input parameters
 func new(lockType string, ns string, name string, coreClient corev1.CoreV1Interface, coordinationClient coordinationv1.CoordinationV1Interface, rlc ResourceLockConfig, labels map[string]string) (Interface, error) {
-	leaseLock := &LeaseLock{
-		LeaseMeta: metav1.ObjectMeta{
-			Namespace: ns,
-			Name:      name,
-		},
-		Client:     coordinationClient,
-		LockConfig: rlc,
-		Labels:     labels,
+	if message, ok := removedResourceLocks[lockType]; ok {
+		return nil, errors.New(message)
 	}
-	switch lockType {
-	case endpointsResourceLock:
-		return nil, fmt.Errorf("endpoints lock is removed, migrate to %s", LeasesResourceLock)
-	case configMapsResourceLock:
-		return nil, fmt.Errorf("configmaps lock is removed, migrate to %s", LeasesResourceLock)
-	case LeasesResourceLock:
-		return leaseLock, nil
-	case endpointsLeasesResourceLock:
-		return nil, fmt.Errorf("endpointsleases lock is removed, migrate to %s", LeasesResourceLock)
-	case configMapsLeasesResourceLock:
-		return nil, fmt.Errorf("configmapsleases lock is removed, migrated to %s", LeasesResourceLock)
-	default:
+	backendsLock.RLock()
+	backend, ok := backends[lockType]
+	backendsLock.RUnlock()
+	if !ok {
 		return nil, fmt.Errorf("Invalid lock-type %s", lockType)
 	}
+	return backend(BackendConfig{
+		Namespace:          ns,
+		Name:               name,
+		CoreClient:         coreClient,
+		CoordinationClient: coordinationClient,
+		LockConfig:         rlc,
+		Labels:             labels,
+	})
 }
 
 // New will create a lock of a given type according to the input parameters
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcelock
+
+import (
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRegisterBackend(t *testing.T) {
+	store := NewMemoryLockStore()
+	if err := RegisterBackend("memory", func(config BackendConfig) (Interface, error) {
+		return store.Lock(config.Namespace+"/"+config.Name, config.LockConfig), nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		backendsLock.Lock()
+		defer backendsLock.Unlock()
+		delete(backends, "memory")
+	})
+
+	client := fake.NewClientset()
+	lock, err := New("memory", "kube-system", "lock", client.CoreV1(), client.CoordinationV1(), ResourceLockConfig{Identity: "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lock.Describe() != "memory/kube-system/lock" || lock.Identity() != "a" {
+		t.Errorf("unexpected lock %s of %s", lock.Describe(), lock.Identity())
+	}
+	lock, err = New(LeasesResourceLock, "kube-system", "lock", client.CoreV1(), client.CoordinationV1(), ResourceLockConfig{Identity: "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := lock.(*LeaseLock); !ok {
+		t.Errorf("expected a LeaseLock, got %T", lock)
+	}
+
+	backend := func(config BackendConfig) (Interface, error) { return nil, nil }
+	for _, lockType := range []string{"memory", LeasesResourceLock, endpointsResourceLock} {
+		if err := RegisterBackend(lockType, backend); err == nil {
+			t.Errorf("expected an error for registering %s", lockType)
+		}
+	}
+	if err := RegisterBackend("nil", nil); err == nil {
+		t.Error("expected an error for a nil backend")
+	}
+}
+
+func TestNewInvalidLockType(t *testing.T) {
+	client := fake.NewClientset()
+	for lockType, expected := range map[string]string{
+		endpointsResourceLock:        "endpoints lock is removed, migrate to leases",
+		configMapsResourceLock:       "configmaps lock is removed, migrate to leases",
+		endpointsLeasesResourceLock:  "endpointsleases lock is removed, migrate to leases",
+		configMapsLeasesResourceLock: "configmapsleases lock is removed, migrated to leases",
+		"unknown":                    "Invalid lock-type unknown",
+	} {
+		_, err := New(lockType, "kube-system", "lock", client.CoreV1(), client.CoordinationV1(), ResourceLockConfig{Identity: "a"})
+		if err == nil || err.Error() != expected {
+			t.Errorf("expected the error %q for %s, got %v", expected, lockType, err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcelock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var memoryLockResource = schema.GroupResource{Resource: "memorylocks"}
+
+// MemoryLockStore stores the records of in-memory locks. Locks of the same
+// store and name contend for the same record, which allows testing leader
+// election between several candidates of a process without an apiserver.
+type MemoryLockStore struct {
+	lock    sync.Mutex
+	records map[string]memoryRecord
+}
+
+type memoryRecord struct {
+	record  LeaderElectionRecord
+	version int
+}
+
+// NewMemoryLockStore returns an empty MemoryLockStore.
+func NewMemoryLockStore() *MemoryLockStore {
+	return &MemoryLockStore{records: map[string]memoryRecord{}}
+}
+
+// Lock returns a lock on the record with the given name.
+func (s *MemoryLockStore) Lock(name string, config ResourceLockConfig) *MemoryLock {
+	return &MemoryLock{store: s, name: name, config: config}
+}
+
+// MemoryLock is a lock on a record of a MemoryLockStore. Like LeaseLock, it
+// only updates the record if it is unchanged since the lock last got,
+// created or updated it.
+type MemoryLock struct {
+	store  *MemoryLockStore
+	name   string
+	config ResourceLockConfig
+
+	// version is the version of the record the lock last observed, 0 if
+	// it didn't observe the record yet. It is protected by the lock of
+	// the store.
+	version int
+}
+
+var _ Interface = &MemoryLock{}
+
+// Get returns the election record.
+func (ml *MemoryLock) Get(ctx context.Context) (*LeaderElectionRecord, []byte, error) {
+	ml.store.lock.Lock()
+	defer ml.store.lock.Unlock()
+	stored, ok := ml.store.records[ml.name]
+	if !ok {
+		return nil, nil, apierrors.NewNotFound(memoryLockResource, ml.name)
+	}
+	ml.version = stored.version
+	record := stored.record
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &record, recordBytes, nil
+}
+
+// Create creates the election record.
+func (ml *MemoryLock) Create(ctx context.Context, ler LeaderElectionRecord) error {
+	ml.store.lock.Lock()
+	defer ml.store.lock.Unlock()
+	if _, ok := ml.store.records[ml.name]; ok {
+		return apierrors.NewAlreadyExists(memoryLockResource, ml.name)
+	}
+	ml.store.records[ml.name] = memoryRecord{record: ler, version: 1}
+	ml.version = 1
+	return nil
+}
+
+// Update updates the election record.
+func (ml *MemoryLock) Update(ctx context.Context, ler LeaderElectionRecord) error {
+	ml.store.lock.Lock()
+	defer ml.store.lock.Unlock()
+	if ml.version == 0 {
+		return errors.New("record not initialized, call get or create first")
+	}
+	stored, ok := ml.store.records[ml.name]
+	if !ok {
+		return apierrors.NewNotFound(memoryLockResource, ml.name)
+	}
+	if stored.version != ml.version {
+		return apierrors.NewConflict(memoryLockResource, ml.name, errors.New("the record has been modified"))
+	}
+	ml.store.records[ml.name] = memoryRecord{record: ler, version: stored.version + 1}
+	ml.version = stored.version + 1
+	return nil
+}
+
+// RecordEvent records an event about a lease with the name of the lock.
+func (ml *MemoryLock) RecordEvent(s string) {
+	recordLeaseEvent(ml.config, metav1.ObjectMeta{Name: ml.name}, s)
+}
+
+// Describe returns the name of the lock.
+func (ml *MemoryLock) Describe() string {
+	return fmt.Sprintf("memory/%s", ml.name)
+}
+
+// Identity returns the Identity of the lock.
+func (ml *MemoryLock) Identity() string {
+	return ml.config.Identity
+}
+
+// recordLeaseEvent records an event about a lease for locks which aren't
+// backed by an object of the apiserver.
+func recordLeaseEvent(config ResourceLockConfig, meta metav1.ObjectMeta, s string) {
+	if config.EventRecorder == nil {
+		return
+	}
+	subject := &coordinationv1.Lease{ObjectMeta: meta}
+	subject.Kind = "Lease"
+	subject.APIVersion = coordinationv1.SchemeGroupVersion.String()
+	config.EventRecorder.Eventf(subject, corev1.EventTypeNormal, "LeaderElection", "%s %s", config.Identity, s)
+}
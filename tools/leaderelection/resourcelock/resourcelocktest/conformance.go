@@ -0,0 +1,221 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resourcelocktest verifies implementations of resourcelock.Interface.
+package resourcelocktest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// NewLocksFunc is called by each test and returns a function which creates
+// locks with the given configuration on the same record. The record must not
+// exist initially.
+type NewLocksFunc func(t *testing.T) func(config resourcelock.ResourceLockConfig) resourcelock.Interface
+
+// RunConformanceTests runs the tests which verify that the locks of
+// newLocks behave like the leader election expects:
+//
+//   - Get returns a NotFound error if the record doesn't exist, and otherwise
+//     the record and a serialization of it which only changes when the record
+//     changes.
+//   - Create returns an AlreadyExists error if the record exists.
+//   - Update returns an error if the lock didn't get or create the record
+//     before, and a Conflict error if the record changed since the lock last
+//     got, created or updated it.
+//   - RecordEvent records an event with the message "<identity> <event>" if
+//     the configuration has an EventRecorder.
+func RunConformanceTests(t *testing.T, newLocks NewLocksFunc) {
+	now := time.Now().Truncate(time.Second)
+	first := resourcelock.LeaderElectionRecord{
+		HolderIdentity:       "a",
+		LeaseDurationSeconds: 15,
+		AcquireTime:          metav1.NewTime(now),
+		RenewTime:            metav1.NewTime(now),
+	}
+	second := first
+	second.RenewTime = metav1.NewTime(now.Add(2 * time.Second))
+	second.LeaderTransitions = 1
+	second.PreferredHolder = "b"
+
+	t.Run("Get", func(t *testing.T) {
+		ctx := context.Background()
+		newLock := newLocks(t)
+		a, b := newLock(config("a")), newLock(config("b"))
+		if _, _, err := a.Get(ctx); !apierrors.IsNotFound(err) {
+			t.Fatalf("expected a NotFound error for a missing record, got %v", err)
+		}
+		if err := a.Create(ctx, first); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		record, raw, err := b.Get(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expectRecord(t, record, first)
+		_, rawAgain, err := a.Get(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(raw) == 0 || !bytes.Equal(raw, rawAgain) {
+			t.Errorf("expected the same serialization of an unchanged record, got %q and %q", raw, rawAgain)
+		}
+		if err := a.Update(ctx, second); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		record, rawUpdated, err := b.Get(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expectRecord(t, record, second)
+		if bytes.Equal(raw, rawUpdated) {
+			t.Errorf("expected the serialization to change with the record, got %q", raw)
+		}
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		ctx := context.Background()
+		newLock := newLocks(t)
+		a, b := newLock(config("a")), newLock(config("b"))
+		if err := a.Create(ctx, first); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := b.Create(ctx, second); !apierrors.IsAlreadyExists(err) {
+			t.Fatalf("expected an AlreadyExists error for an existing record, got %v", err)
+		}
+		record, _, err := b.Get(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expectRecord(t, record, first)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		ctx := context.Background()
+		newLock := newLocks(t)
+		a, b, c := newLock(config("a")), newLock(config("b")), newLock(config("c"))
+		if err := a.Create(ctx, first); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := c.Update(ctx, second); err == nil {
+			t.Error("expected an error for an update without get")
+		}
+		if _, _, err := b.Get(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// a updates twice in a row, after which b's view is stale.
+		if err := a.Update(ctx, second); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := a.Update(ctx, first); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := b.Update(ctx, second); !apierrors.IsConflict(err) {
+			t.Fatalf("expected a Conflict error for a stale update, got %v", err)
+		}
+		if _, _, err := b.Get(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := b.Update(ctx, second); err != nil {
+			t.Fatalf("unexpected error after getting the record again: %v", err)
+		}
+		record, _, err := c.Get(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expectRecord(t, record, second)
+	})
+
+	t.Run("Identity", func(t *testing.T) {
+		newLock := newLocks(t)
+		a, b := newLock(config("a")), newLock(config("b"))
+		if a.Identity() != "a" || b.Identity() != "b" {
+			t.Errorf("expected the identities a and b, got %q and %q", a.Identity(), b.Identity())
+		}
+		if a.Describe() == "" || a.Describe() != b.Describe() {
+			t.Errorf("expected the same description of locks on the same record, got %q and %q", a.Describe(), b.Describe())
+		}
+	})
+
+	t.Run("RecordEvent", func(t *testing.T) {
+		ctx := context.Background()
+		newLock := newLocks(t)
+		recorder := &eventRecorder{}
+		config := config("a")
+		config.EventRecorder = recorder
+		a := newLock(config)
+		if err := a.Create(ctx, first); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		a.RecordEvent("became leader")
+		if events := recorder.get(); len(events) != 1 || events[0] != "a became leader" {
+			t.Errorf("expected the event %q, got %q", "a became leader", events)
+		}
+		// Without recorder, events are dropped.
+		b := newLock(resourcelock.ResourceLockConfig{Identity: "b"})
+		if _, _, err := b.Get(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		b.RecordEvent("stopped leading")
+	})
+}
+
+func config(identity string) resourcelock.ResourceLockConfig {
+	return resourcelock.ResourceLockConfig{Identity: identity}
+}
+
+// expectRecord compares records regardless of the location and monotonic
+// clock reading of their times.
+func expectRecord(t *testing.T, got *resourcelock.LeaderElectionRecord, expected resourcelock.LeaderElectionRecord) {
+	t.Helper()
+	normalized := *got
+	for _, r := range []*resourcelock.LeaderElectionRecord{&normalized, &expected} {
+		r.AcquireTime = metav1.Time{Time: r.AcquireTime.UTC()}
+		r.RenewTime = metav1.Time{Time: r.RenewTime.UTC()}
+	}
+	if !reflect.DeepEqual(normalized, expected) {
+		t.Errorf("expected the record %+v, got %+v", expected, normalized)
+	}
+}
+
+type eventRecorder struct {
+	lock   sync.Mutex
+	events []string
+}
+
+func (r *eventRecorder) Eventf(obj runtime.Object, eventType, reason, message string, args ...interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = append(r.events, fmt.Sprintf(message, args...))
+}
+
+func (r *eventRecorder) get() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]string(nil), r.events...)
+}
@@ -0,0 +1,436 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sharding spreads the work of a controller over all of its replicas
+// instead of a single leader. The key space of the objects is divided into a
+// fixed number of partitions, which are assigned to the live members of a
+// group with rendezvous hashing, so that only the partitions of the members
+// which join or leave move when the membership changes.
+//
+// Each member maintains a coordination/v1 Lease which is labeled with the
+// group. Like leader election, a member considers another member alive while
+// it observes the renewals of its lease, measured with the local clock.
+//
+//	coordinator, err := sharding.NewCoordinator(sharding.Config{
+//		Client:        clientset.CoordinationV1(),
+//		Namespace:     "kube-system",
+//		Group:         "my-controller",
+//		Identity:      podName,
+//		Partitions:    128,
+//		LeaseDuration: 15 * time.Second,
+//		RenewDeadline: 10 * time.Second,
+//		RetryPeriod:   2 * time.Second,
+//		OnAssignmentChanged: func(partitions []int) {
+//			// enqueue the objects of the newly owned partitions
+//		},
+//	})
+//	go coordinator.Run(ctx)
+//	informer.AddEventHandler(coordinator.ResourceEventHandler(handler))
+//	queue = sharding.NewFilteredQueue(queue, coordinator.Owns)
+//
+// The members only agree on the assignment once they observed the same
+// members, so while the membership changes a partition may briefly be owned
+// by two members or none. Controllers must tolerate this, which they usually
+// do because they reconcile idempotently.
+//
+// DISCLAIMER: this is an alpha API. This library will likely change
+// significantly or even be removed entirely in subsequent releases.
+package sharding
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/client-go/tools/cache"
+	rl "k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// GroupLabel is the label of the member leases whose value is the group.
+	GroupLabel = "sharding.client-go.k8s.io/group"
+
+	jitterFactor = 1.2
+)
+
+// Config configures a Coordinator.
+type Config struct {
+	// Client is used to maintain and list the member leases.
+	Client coordinationv1client.LeasesGetter
+
+	// Namespace is the namespace of the member leases.
+	Namespace string
+
+	// Group is the name of the set of members which share the partitions.
+	// The lease of a member is named <Group>-<Identity>.
+	Group string
+
+	// Identity uniquely identifies the member within the group, for
+	// example the name of its pod.
+	Identity string
+
+	// Partitions is the number of partitions. All members of a group must
+	// use the same number, which should be much larger than the number of
+	// members so that the partitions spread evenly.
+	Partitions int
+
+	// LeaseDuration is how long other members consider a member alive after
+	// they observed the last renewal of its lease.
+	LeaseDuration time.Duration
+	// RenewDeadline is how long a member keeps its partitions after the last
+	// successful renewal of its lease. It must be shorter than
+	// LeaseDuration, so that the member gives its partitions up before
+	// others take them over.
+	RenewDeadline time.Duration
+	// RetryPeriod is how often a member renews its lease and checks the
+	// membership of the group.
+	RetryPeriod time.Duration
+
+	// OnAssignmentChanged is called with the sorted partitions the member
+	// owns whenever they change, including to none when Run returns. It is
+	// called synchronously and must not block.
+	OnAssignmentChanged func(partitions []int)
+}
+
+// Coordinator maintains the membership of a member of a group and the
+// partitions it owns.
+type Coordinator struct {
+	config Config
+	lock   *rl.LeaseLock
+	clock  clock.Clock
+
+	// bookkeeping of the membership, only used by Run
+	joined      bool
+	acquireTime metav1.Time
+	renewTime   time.Time
+	observed    map[string]observedLease
+
+	// the current assignment, protected by assignmentLock
+	assignmentLock sync.RWMutex
+	members        []string
+	owned          []bool
+}
+
+// observedLease is the lease of another member and when its last renewal was
+// observed.
+type observedLease struct {
+	lease        *coordinationv1.Lease
+	observedTime time.Time
+}
+
+// NewCoordinator creates a Coordinator from a Config.
+func NewCoordinator(config Config) (*Coordinator, error) {
+	if config.Client == nil {
+		return nil, fmt.Errorf("Client must not be nil")
+	}
+	if config.Group == "" || config.Identity == "" {
+		return nil, fmt.Errorf("Group and Identity must not be empty")
+	}
+	leaseName := config.Group + "-" + config.Identity
+	if errs := validation.IsDNS1123Subdomain(leaseName); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid lease name %q: %v", leaseName, errs)
+	}
+	if config.Partitions < 1 {
+		return nil, fmt.Errorf("partitions must be greater than zero")
+	}
+	if config.LeaseDuration <= config.RenewDeadline {
+		return nil, fmt.Errorf("leaseDuration must be greater than renewDeadline")
+	}
+	if config.RenewDeadline <= time.Duration(jitterFactor*float64(config.RetryPeriod)) {
+		return nil, fmt.Errorf("renewDeadline must be greater than retryPeriod*%v", jitterFactor)
+	}
+	if config.RetryPeriod < 1 {
+		return nil, fmt.Errorf("retryPeriod must be greater than zero")
+	}
+
+	return &Coordinator{
+		config: config,
+		lock: &rl.LeaseLock{
+			LeaseMeta:  metav1.ObjectMeta{Namespace: config.Namespace, Name: leaseName},
+			Client:     config.Client,
+			LockConfig: rl.ResourceLockConfig{Identity: config.Identity},
+			Labels:     map[string]string{GroupLabel: config.Group},
+		},
+		clock:    clock.RealClock{},
+		observed: map[string]observedLease{},
+		owned:    make([]bool, config.Partitions),
+	}, nil
+}
+
+// Run maintains the membership until ctx is done. It then leaves the group by
+// deleting the lease of the member, so that the other members take its
+// partitions over without waiting for the lease to expire.
+func (c *Coordinator) Run(ctx context.Context) {
+	defer runtime.HandleCrashWithContext(ctx)
+	logger := klog.FromContext(ctx)
+	logger.Info("Joining sharding group", "group", c.config.Group, "lease", klog.KRef(c.config.Namespace, c.lock.LeaseMeta.Name))
+	wait.JitterUntilWithContext(ctx, c.sync, c.config.RetryPeriod, jitterFactor, true)
+	c.leave(logger)
+}
+
+// Partition returns the partition of a key.
+func (c *Coordinator) Partition(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(c.config.Partitions))
+}
+
+// Owns returns whether the member owns the partition of a key.
+func (c *Coordinator) Owns(key string) bool {
+	partition := c.Partition(key)
+	c.assignmentLock.RLock()
+	defer c.assignmentLock.RUnlock()
+	return c.owned[partition]
+}
+
+// OwnsObject returns whether the member owns the partition of the
+// namespace/name key of an object. obj may be a DeletedFinalStateUnknown.
+func (c *Coordinator) OwnsObject(obj interface{}) bool {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return false
+	}
+	return c.Owns(key)
+}
+
+// OwnedPartitions returns the sorted partitions the member owns.
+func (c *Coordinator) OwnedPartitions() []int {
+	c.assignmentLock.RLock()
+	defer c.assignmentLock.RUnlock()
+	return ownedPartitions(c.owned)
+}
+
+// Members returns the sorted identities of the members which the member
+// considers alive, including itself while its lease is renewed.
+func (c *Coordinator) Members() []string {
+	c.assignmentLock.RLock()
+	defer c.assignmentLock.RUnlock()
+	return slices.Clone(c.members)
+}
+
+// sync renews the lease of the member, observes the leases of the others and
+// updates the assignment.
+func (c *Coordinator) sync(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	start := c.clock.Now()
+	if err := c.renew(ctx, start); err != nil {
+		logger.Error(err, "Failed to renew member lease", "lease", klog.KRef(c.config.Namespace, c.lock.LeaseMeta.Name))
+	} else {
+		c.renewTime = start
+	}
+
+	leases, err := c.config.Client.Leases(c.config.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{GroupLabel: c.config.Group}).String(),
+	})
+	if err != nil {
+		logger.Error(err, "Failed to list member leases", "group", c.config.Group)
+	} else {
+		c.observe(leases.Items)
+	}
+
+	now := c.clock.Now()
+	var members []string
+	if !c.renewTime.IsZero() && now.Sub(c.renewTime) < c.config.RenewDeadline {
+		members = append(members, c.config.Identity)
+	}
+	for identity, observed := range c.observed {
+		if c.isAlive(observed, now) {
+			members = append(members, identity)
+			continue
+		}
+		c.deleteExpired(ctx, observed.lease)
+		delete(c.observed, identity)
+	}
+	c.assign(logger, members)
+}
+
+// renew creates or updates the lease of the member.
+func (c *Coordinator) renew(ctx context.Context, now time.Time) error {
+	if !c.joined {
+		c.acquireTime = metav1.NewTime(now)
+	}
+	record := rl.LeaderElectionRecord{
+		HolderIdentity:       c.config.Identity,
+		LeaseDurationSeconds: int(c.config.LeaseDuration / time.Second),
+		AcquireTime:          c.acquireTime,
+		RenewTime:            metav1.NewTime(now),
+	}
+	// The update only fails if the lease was changed or deleted in the
+	// meantime, so try without getting it first.
+	if c.joined && c.lock.Update(ctx, record) == nil {
+		return nil
+	}
+	_, _, err := c.lock.Get(ctx)
+	switch {
+	case apierrors.IsNotFound(err):
+		err = c.lock.Create(ctx, record)
+	case err == nil:
+		err = c.lock.Update(ctx, record)
+	}
+	c.joined = err == nil
+	return err
+}
+
+// observe records the leases of the other members of the group and when they
+// were last renewed. Members whose leases are gone left the group.
+func (c *Coordinator) observe(leases []coordinationv1.Lease) {
+	now := c.clock.Now()
+	observed := make(map[string]observedLease, len(leases))
+	for i := range leases {
+		lease := &leases[i]
+		if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" || *lease.Spec.HolderIdentity == c.config.Identity {
+			continue
+		}
+		identity := *lease.Spec.HolderIdentity
+		o, ok := c.observed[identity]
+		if !ok || !renewTime(o.lease).Equal(renewTime(lease)) {
+			o.observedTime = now
+		}
+		o.lease = lease
+		observed[identity] = o
+	}
+	c.observed = observed
+}
+
+// isAlive returns whether the lease of another member was renewed within its
+// lease duration, as observed by this member.
+func (c *Coordinator) isAlive(observed observedLease, now time.Time) bool {
+	var leaseDuration time.Duration
+	if observed.lease.Spec.LeaseDurationSeconds != nil {
+		leaseDuration = time.Duration(*observed.lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+	return observed.observedTime.Add(leaseDuration).After(now)
+}
+
+// deleteExpired deletes the lease of a member which stopped without leaving
+// the group, unless it was renewed in the meantime.
+func (c *Coordinator) deleteExpired(ctx context.Context, lease *coordinationv1.Lease) {
+	logger := klog.FromContext(ctx)
+	logger.V(2).Info("Member lease expired", "lease", klog.KObj(lease), "member", *lease.Spec.HolderIdentity)
+	err := c.config.Client.Leases(lease.Namespace).Delete(ctx, lease.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &lease.ResourceVersion},
+	})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		logger.V(2).Info("Failed to delete expired member lease", "lease", klog.KObj(lease), "err", err)
+	}
+}
+
+// leave gives up the partitions of the member and deletes its lease.
+func (c *Coordinator) leave(logger klog.Logger) {
+	c.assign(logger, nil)
+	if !c.joined {
+		return
+	}
+	ctx, cancel := context.WithTimeout(klog.NewContext(context.Background(), logger), c.config.RenewDeadline)
+	defer cancel()
+	err := c.config.Client.Leases(c.config.Namespace).Delete(ctx, c.lock.LeaseMeta.Name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "Failed to delete member lease", "lease", klog.KRef(c.config.Namespace, c.lock.LeaseMeta.Name))
+		return
+	}
+	c.joined = false
+	logger.Info("Left sharding group", "group", c.config.Group)
+}
+
+// assign assigns the partitions to the members and notifies
+// OnAssignmentChanged if the partitions of this member changed.
+func (c *Coordinator) assign(logger klog.Logger, members []string) {
+	slices.Sort(members)
+	owned := make([]bool, c.config.Partitions)
+	for partition := range owned {
+		owned[partition] = len(members) > 0 && ownerOf(members, partition) == c.config.Identity
+	}
+
+	c.assignmentLock.Lock()
+	changed := !slices.Equal(c.owned, owned)
+	if !slices.Equal(c.members, members) {
+		logger.V(2).Info("Sharding group membership changed", "group", c.config.Group, "members", members)
+	}
+	c.members = members
+	c.owned = owned
+	c.assignmentLock.Unlock()
+
+	if changed {
+		partitions := ownedPartitions(owned)
+		logger.V(2).Info("Owned partitions changed", "group", c.config.Group, "partitions", len(partitions))
+		if c.config.OnAssignmentChanged != nil {
+			c.config.OnAssignmentChanged(partitions)
+		}
+	}
+}
+
+// ownerOf returns the member with the highest weight for the partition, which
+// is the same on all members that observed the same members.
+func ownerOf(members []string, partition int) string {
+	var owner string
+	var ownerWeight uint64
+	for _, member := range members {
+		// Members are sorted, so ties go to the first one.
+		if w := weight(member, partition); owner == "" || w > ownerWeight {
+			owner, ownerWeight = member, w
+		}
+	}
+	return owner
+}
+
+// weight is the rendezvous hash of a member and a partition.
+func weight(member string, partition int) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(member))
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(partition))
+	h.Write(b[:])
+	// FNV doesn't spread similar inputs well, so finalize it like
+	// MurmurHash3.
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+func ownedPartitions(owned []bool) []int {
+	partitions := []int{}
+	for partition, ok := range owned {
+		if ok {
+			partitions = append(partitions, partition)
+		}
+	}
+	return partitions
+}
+
+func renewTime(lease *coordinationv1.Lease) time.Time {
+	if lease.Spec.RenewTime == nil {
+		return time.Time{}
+	}
+	return lease.Spec.RenewTime.Time
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/klog/v2/ktesting"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+const testPartitions = 64
+
+func newTestConfig(client *fake.Clientset, identity string) Config {
+	return Config{
+		Client:        client.CoordinationV1(),
+		Namespace:     "kube-system",
+		Group:         "controller",
+		Identity:      identity,
+		Partitions:    testPartitions,
+		LeaseDuration: 15 * time.Second,
+		RenewDeadline: 10 * time.Second,
+		RetryPeriod:   2 * time.Second,
+	}
+}
+
+// newTestCoordinators returns coordinators of the same group which share a
+// fake clock.
+func newTestCoordinators(t *testing.T, identities ...string) []*Coordinator {
+	t.Helper()
+	client := fake.NewClientset()
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	var coordinators []*Coordinator
+	for _, identity := range identities {
+		c, err := NewCoordinator(newTestConfig(client, identity))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.clock = fakeClock
+		coordinators = append(coordinators, c)
+	}
+	return coordinators
+}
+
+// expectAssignment checks that the coordinators agree on the members and that
+// each partition is owned by exactly one of them.
+func expectAssignment(t *testing.T, coordinators ...*Coordinator) {
+	t.Helper()
+	var members []string
+	for _, c := range coordinators {
+		members = append(members, c.config.Identity)
+	}
+	slices.Sort(members)
+	owners := make([]int, testPartitions)
+	for _, c := range coordinators {
+		if !slices.Equal(c.Members(), members) {
+			t.Errorf("expected %s to observe the members %v, got %v", c.config.Identity, members, c.Members())
+		}
+		partitions := c.OwnedPartitions()
+		if len(partitions) == 0 {
+			t.Errorf("expected %s to own partitions", c.config.Identity)
+		}
+		for _, partition := range partitions {
+			owners[partition]++
+		}
+	}
+	for partition, n := range owners {
+		if n != 1 {
+			t.Errorf("expected partition %d to have one owner, got %d", partition, n)
+		}
+	}
+}
+
+func TestCoordinator(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+	coordinators := newTestCoordinators(t, "a", "b", "c")
+	a, b, c := coordinators[0], coordinators[1], coordinators[2]
+	fakeClock := a.clock.(*testingclock.FakeClock)
+	var assignments [][]int
+	a.config.OnAssignmentChanged = func(partitions []int) {
+		assignments = append(assignments, partitions)
+	}
+
+	a.sync(ctx)
+	expectAssignment(t, a)
+	if len(assignments) != 1 || len(assignments[0]) != testPartitions {
+		t.Errorf("expected a to be notified about all partitions, got %v", assignments)
+	}
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("default/pod-%d", i)
+		if !a.Owns(key) {
+			t.Errorf("expected a to own %s", key)
+		}
+	}
+
+	b.sync(ctx)
+	a.sync(ctx)
+	expectAssignment(t, a, b)
+	before := a.OwnedPartitions()
+
+	// Only partitions of a and b move to c.
+	c.sync(ctx)
+	a.sync(ctx)
+	b.sync(ctx)
+	expectAssignment(t, a, b, c)
+	for _, partition := range a.OwnedPartitions() {
+		if !slices.Contains(before, partition) {
+			t.Errorf("expected partition %d not to move from b to a", partition)
+		}
+	}
+	if len(assignments) != 3 {
+		t.Errorf("expected a to be notified about three assignments, got %v", assignments)
+	}
+
+	// c stops renewing its lease.
+	fakeClock.Step(8 * time.Second)
+	a.sync(ctx)
+	b.sync(ctx)
+	expectAssignment(t, a, b, c)
+	fakeClock.Step(8 * time.Second)
+	a.sync(ctx)
+	b.sync(ctx)
+	expectAssignment(t, a, b)
+	if _, err := a.config.Client.Leases("kube-system").Get(ctx, "controller-c", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the expired lease of c to be deleted, got %v", err)
+	}
+
+	// b leaves the group.
+	b.leave(ktesting.NewLogger(t, ktesting.NewConfig()))
+	if len(b.OwnedPartitions()) != 0 {
+		t.Errorf("expected b to own no partitions after leaving, got %v", b.OwnedPartitions())
+	}
+	a.sync(ctx)
+	expectAssignment(t, a)
+}
+
+func TestCoordinatorOwnLease(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+	client := fake.NewClientset()
+	a, err := NewCoordinator(newTestConfig(client, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	a.clock = fakeClock
+
+	a.sync(ctx)
+	expectAssignment(t, a)
+	lease, err := client.CoordinationV1().Leases("kube-system").Get(ctx, "controller-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease.Labels[GroupLabel] != "controller" || *lease.Spec.HolderIdentity != "a" || *lease.Spec.LeaseDurationSeconds != 15 {
+		t.Errorf("unexpected member lease: %+v", lease)
+	}
+
+	// The lease gets deleted behind the back of the member.
+	if err := client.CoordinationV1().Leases("kube-system").Delete(ctx, "controller-a", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	fakeClock.Step(2 * time.Second)
+	a.sync(ctx)
+	if _, err := client.CoordinationV1().Leases("kube-system").Get(ctx, "controller-a", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the lease to be recreated, got %v", err)
+	}
+	expectAssignment(t, a)
+
+	// A member which can't renew its lease gives its partitions up after
+	// RenewDeadline, before the others take them over.
+	client.PrependReactor("*", "leases", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("apiserver unavailable")
+	})
+	for i := 0; i < 4; i++ {
+		fakeClock.Step(2 * time.Second)
+		a.sync(ctx)
+		expectAssignment(t, a)
+	}
+	fakeClock.Step(2 * time.Second)
+	a.sync(ctx)
+	if len(a.OwnedPartitions()) != 0 || len(a.Members()) != 0 {
+		t.Errorf("expected no members and partitions, got %v and %v", a.Members(), a.OwnedPartitions())
+	}
+}
+
+func TestNewCoordinator(t *testing.T) {
+	client := fake.NewClientset()
+	for name, modify := range map[string]func(*Config){
+		"no client":          func(c *Config) { c.Client = nil },
+		"no identity":        func(c *Config) { c.Identity = "" },
+		"invalid lease name": func(c *Config) { c.Identity = "Host_1" },
+		"no partitions":      func(c *Config) { c.Partitions = 0 },
+		"short lease":        func(c *Config) { c.LeaseDuration = c.RenewDeadline },
+		"short renewal":      func(c *Config) { c.RenewDeadline = c.RetryPeriod },
+		"no retry period":    func(c *Config) { c.RetryPeriod = 0 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := newTestConfig(client, "a")
+			modify(&config)
+			if _, err := NewCoordinator(config); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestCoordinatorRun(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	client := fake.NewClientset()
+	config := newTestConfig(client, "a")
+	config.RetryPeriod = 10 * time.Millisecond
+	assigned := make(chan []int, 10)
+	config.OnAssignmentChanged = func(partitions []int) {
+		assigned <- partitions
+	}
+	a, err := NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		a.Run(ctx)
+	}()
+	if partitions := <-assigned; len(partitions) != testPartitions {
+		t.Errorf("expected all partitions, got %v", partitions)
+	}
+	cancel()
+	<-stopped
+	if partitions := <-assigned; len(partitions) != 0 {
+		t.Errorf("expected no partitions after leaving, got %v", partitions)
+	}
+	if _, err := client.CoordinationV1().Leases("kube-system").Get(context.Background(), "controller-a", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the lease to be deleted after leaving, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"time"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// ResourceEventHandler returns a handler which passes the events of the
+// objects in the partitions the member owns to handler. Events of objects in
+// partitions which the member gains later are not replayed, which is what
+// Config.OnAssignmentChanged is for.
+func (c *Coordinator) ResourceEventHandler(handler cache.ResourceEventHandler) cache.ResourceEventHandler {
+	return cache.FilteringResourceEventHandler{
+		FilterFunc: c.OwnsObject,
+		Handler:    handler,
+	}
+}
+
+// NewFilteredQueue wraps queue so that only the items which owns returns true
+// for are added, for example with Coordinator.Owns for a queue of object keys.
+// Get skips and forgets the items which were added before their partition
+// moved to another member.
+func NewFilteredQueue[T comparable](queue workqueue.TypedRateLimitingInterface[T], owns func(T) bool) workqueue.TypedRateLimitingInterface[T] {
+	return &filteredQueue[T]{
+		TypedRateLimitingInterface: queue,
+		owns:                       owns,
+	}
+}
+
+type filteredQueue[T comparable] struct {
+	workqueue.TypedRateLimitingInterface[T]
+	owns func(T) bool
+}
+
+func (q *filteredQueue[T]) Add(item T) {
+	if q.owns(item) {
+		q.TypedRateLimitingInterface.Add(item)
+	}
+}
+
+func (q *filteredQueue[T]) AddAfter(item T, duration time.Duration) {
+	if q.owns(item) {
+		q.TypedRateLimitingInterface.AddAfter(item, duration)
+	}
+}
+
+func (q *filteredQueue[T]) AddRateLimited(item T) {
+	if q.owns(item) {
+		q.TypedRateLimitingInterface.AddRateLimited(item)
+	}
+}
+
+func (q *filteredQueue[T]) Get() (T, bool) {
+	for {
+		item, shutdown := q.TypedRateLimitingInterface.Get()
+		if shutdown || q.owns(item) {
+			return item, shutdown
+		}
+		q.Forget(item)
+		q.Done(item)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestFilteredQueue(t *testing.T) {
+	owned := sets.New("a", "b", "c")
+	queue := NewFilteredQueue(workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]()), owned.Has)
+	defer queue.ShutDown()
+
+	for _, item := range []string{"a", "x", "b", "y"} {
+		queue.Add(item)
+	}
+	queue.AddRateLimited("z")
+	queue.AddAfter("z", 0)
+	if queue.Len() != 2 {
+		t.Fatalf("expected only the owned items to be added, got %d items", queue.Len())
+	}
+
+	// The partition of a moves to another member while it is queued.
+	owned.Delete("a")
+	queue.Add("c")
+	item, shutdown := queue.Get()
+	if shutdown || item != "b" {
+		t.Fatalf("expected b, got %q", item)
+	}
+	queue.Done(item)
+	if item, _ := queue.Get(); item != "c" {
+		t.Fatalf("expected c, got %q", item)
+	}
+	queue.Done("c")
+	if queue.Len() != 0 {
+		t.Errorf("expected an empty queue, got %d items", queue.Len())
+	}
+}
+
+func TestResourceEventHandler(t *testing.T) {
+	c := newTestCoordinators(t, "a")[0]
+	owned := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "owned"}}
+	other := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"}}
+	c.owned[c.Partition("default/owned")] = true
+	c.owned[c.Partition("default/other")] = false
+	if c.Partition("default/owned") == c.Partition("default/other") {
+		t.Fatal("expected the objects to be in different partitions")
+	}
+
+	var added, deleted []string
+	handler := c.ResourceEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			added = append(added, obj.(*v1.Pod).Name)
+		},
+		DeleteFunc: func(obj interface{}) {
+			deleted = append(deleted, obj.(cache.DeletedFinalStateUnknown).Key)
+		},
+	})
+	handler.OnAdd(owned, false)
+	handler.OnAdd(other, false)
+	handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/owned", Obj: owned})
+	handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/other", Obj: other})
+	if len(added) != 1 || added[0] != "owned" || len(deleted) != 1 || deleted[0] != "default/owned" {
+		t.Errorf("expected only the events of the owned pod, got added %v and deleted %v", added, deleted)
+	}
+}
@@ -104,6 +104,38 @@ type RetryMetric interface {
 	IncrementRetry(ctx context.Context, code string, method string, host string)
 }
 
+// HedgeMetric counts hedged requests partitioned by result, method, and host.
+// The result is "won" if the response of the hedged request was used and
+// "lost" otherwise.
+type HedgeMetric interface {
+	Increment(ctx context.Context, result string, method string, host string)
+}
+
+// TimeoutMetric observes the adaptive timeouts of requests partitioned by
+// method and host.
+type TimeoutMetric interface {
+	Observe(ctx context.Context, method string, host string, timeout time.Duration)
+}
+
+// CircuitBreakerStateMetric tracks the state of circuit breakers partitioned
+// by host and API group version. The state is one of "closed", "open" and
+// "half-open".
+type CircuitBreakerStateMetric interface {
+	Set(host string, groupVersion string, state string)
+}
+
+// CircuitBreakerRejectionsMetric counts the requests rejected by open circuit
+// breakers partitioned by host and API group version.
+type CircuitBreakerRejectionsMetric interface {
+	Increment(host string, groupVersion string)
+}
+
+// EventsMetric counts the events written to event sinks partitioned by
+// event type and reason.
+type EventsMetric interface {
+	Increment(eventType string, reason string)
+}
+
 // TransportCacheMetric shows the number of entries in the internal transport cache
 type TransportCacheMetric interface {
 	Observe(value int)
@@ -159,6 +191,19 @@ var (
 	// RequestRetry is the retry metric that tracks the number of
 	// retries sent to the server.
 	RequestRetry RetryMetric = noopRetry{}
+	// RequestHedges is the metric that counts the hedged requests sent to the server.
+	RequestHedges HedgeMetric = noopHedge{}
+	// RequestAdaptiveTimeout is the metric that observes the adaptive timeouts
+	// of requests.
+	RequestAdaptiveTimeout TimeoutMetric = noopTimeout{}
+	// CircuitBreakerState is the metric that tracks the state of circuit breakers.
+	CircuitBreakerState CircuitBreakerStateMetric = noopCircuitBreakerState{}
+	// CircuitBreakerRejections is the metric that counts the requests rejected
+	// by open circuit breakers.
+	CircuitBreakerRejections CircuitBreakerRejectionsMetric = noopCircuitBreakerRejections{}
+	// EventsWritten is the metric that counts the events written to the
+	// metrics sinks of k8s.io/client-go/tools/events and tools/record.
+	EventsWritten EventsMetric = noopEvents{}
 	// TransportCacheEntries is the metric that tracks the number of entries in the
 	// internal transport cache.
 	TransportCacheEntries TransportCacheMetric = noopTransportCache{}
@@ -188,6 +233,11 @@ type RegisterOpts struct {
 	ExecPluginCalls              CallsMetric
 	ExecPluginPolicyCalls        PolicyCallsMetric
 	RequestRetry                 RetryMetric
+	RequestHedges                HedgeMetric
+	RequestAdaptiveTimeout       TimeoutMetric
+	CircuitBreakerState          CircuitBreakerStateMetric
+	CircuitBreakerRejections     CircuitBreakerRejectionsMetric
+	EventsWritten                EventsMetric
 	TransportCacheEntries        TransportCacheMetric
 	TransportCreateCalls         TransportCreateCallsMetric
 	TransportCAReloads           TransportCAReloadsMetric
@@ -238,6 +288,21 @@ func Register(opts RegisterOpts) {
 		if opts.RequestRetry != nil {
 			RequestRetry = opts.RequestRetry
 		}
+		if opts.RequestHedges != nil {
+			RequestHedges = opts.RequestHedges
+		}
+		if opts.RequestAdaptiveTimeout != nil {
+			RequestAdaptiveTimeout = opts.RequestAdaptiveTimeout
+		}
+		if opts.CircuitBreakerState != nil {
+			CircuitBreakerState = opts.CircuitBreakerState
+		}
+		if opts.CircuitBreakerRejections != nil {
+			CircuitBreakerRejections = opts.CircuitBreakerRejections
+		}
+		if opts.EventsWritten != nil {
+			EventsWritten = opts.EventsWritten
+		}
 		if opts.TransportCacheEntries != nil {
 			TransportCacheEntries = opts.TransportCacheEntries
 		}
@@ -296,6 +361,26 @@ type noopRetry struct{}
 
 func (noopRetry) IncrementRetry(context.Context, string, string, string) {}
 
+type noopHedge struct{}
+
+func (noopHedge) Increment(context.Context, string, string, string) {}
+
+type noopTimeout struct{}
+
+func (noopTimeout) Observe(context.Context, string, string, time.Duration) {}
+
+type noopCircuitBreakerState struct{}
+
+func (noopCircuitBreakerState) Set(string, string, string) {}
+
+type noopCircuitBreakerRejections struct{}
+
+func (noopCircuitBreakerRejections) Increment(string, string) {}
+
+type noopEvents struct{}
+
+func (noopEvents) Increment(string, string) {}
+
 type noopTransportCache struct{}
 
 func (noopTransportCache) Observe(int) {}
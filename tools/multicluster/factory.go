@@ -0,0 +1,335 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package multicluster provides a shared informer factory which watches the
+// same resources in many clusters and presents them as one.
+//
+// The informers of each cluster come from a per-cluster ClusterFactory, for
+// example an informers.SharedInformerFactory wrapped like this:
+//
+//	type typedFactory struct {
+//		informers.SharedInformerFactory
+//	}
+//
+//	func (f typedFactory) InformerForResource(resource schema.GroupVersionResource) (cache.SharedIndexInformer, error) {
+//		informer, err := f.ForResource(resource)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return informer.Informer(), nil
+//	}
+//
+//	factory := multicluster.NewSharedInformerFactory(func(cluster string, config *rest.Config) (multicluster.ClusterFactory, error) {
+//		client, err := kubernetes.NewForConfig(config)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return typedFactory{informers.NewSharedInformerFactory(client, resync)}, nil
+//	})
+package multicluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ClusterFactory provides the informers of a single cluster.
+type ClusterFactory interface {
+	// Start initializes all requested informers. It can be called again to
+	// start informers which were requested later.
+	Start(stopCh <-chan struct{})
+
+	// Shutdown blocks until all goroutines started by Start have terminated,
+	// which happens once the channel passed to Start is closed.
+	Shutdown()
+
+	// InformerForResource returns the shared informer of the resource,
+	// creating it if needed.
+	InformerForResource(resource schema.GroupVersionResource) (cache.SharedIndexInformer, error)
+}
+
+// NewClusterFactoryFunc creates the ClusterFactory for a cluster.
+type NewClusterFactoryFunc func(cluster string, config *rest.Config) (ClusterFactory, error)
+
+// SharedInformerFactory provides shared informers which span all of its
+// clusters. Clusters can be added and removed at any time; the informers
+// requested so far are created and, once the factory was started, also
+// started for added clusters.
+type SharedInformerFactory interface {
+	// AddCluster creates the informers of a new cluster.
+	AddCluster(cluster string, config *rest.Config) error
+
+	// RemoveCluster stops the informers of a cluster. Event handlers get a
+	// delete notification with a cache.DeletedFinalStateUnknown for each
+	// object which was known in the cluster.
+	RemoveCluster(cluster string)
+
+	// Clusters returns the names of all clusters in sorted order.
+	Clusters() []string
+
+	// Start initializes all requested informers of all clusters. Informers
+	// of clusters which are added later get started with the same channel.
+	Start(stopCh <-chan struct{})
+
+	// WaitForCacheSync blocks until the informers of all clusters are synced
+	// or the stop channel gets closed. It returns per cluster whether its
+	// informers have synced.
+	WaitForCacheSync(stopCh <-chan struct{}) map[string]bool
+
+	// Shutdown stops the informers of all clusters and blocks until all of
+	// their goroutines have terminated.
+	Shutdown()
+
+	// ForResource returns the multi-cluster informer of the resource.
+	ForResource(resource schema.GroupVersionResource) (Informer, error)
+}
+
+// NewSharedInformerFactory constructs a SharedInformerFactory without any
+// clusters. newFactory is called for each added cluster.
+func NewSharedInformerFactory(newFactory NewClusterFactoryFunc) SharedInformerFactory {
+	return &sharedInformerFactory{
+		newFactory: newFactory,
+		clusters:   map[string]*cluster{},
+		informers:  map[schema.GroupVersionResource]*informer{},
+	}
+}
+
+// NewSharedInformerFactoryFromKubeconfig constructs a SharedInformerFactory
+// with one cluster per context of the kubeconfig. The clusters are named
+// after the contexts.
+func NewSharedInformerFactoryFromKubeconfig(config clientcmdapi.Config, newFactory NewClusterFactoryFunc) (SharedInformerFactory, error) {
+	f := NewSharedInformerFactory(newFactory)
+	contexts := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+	for _, name := range contexts {
+		restConfig, err := clientcmd.NewNonInteractiveClientConfig(config, name, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("context %q: %w", name, err)
+		}
+		if err := f.AddCluster(name, restConfig); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+type sharedInformerFactory struct {
+	newFactory NewClusterFactoryFunc
+
+	lock      sync.Mutex
+	clusters  map[string]*cluster
+	informers map[schema.GroupVersionResource]*informer
+	// stopCh is the channel passed to Start, nil until then.
+	stopCh <-chan struct{}
+	// shuttingDown is set by Shutdown, clusters are not started anymore afterwards.
+	shuttingDown bool
+}
+
+// cluster is a member cluster of a sharedInformerFactory.
+type cluster struct {
+	name    string
+	factory ClusterFactory
+	// ctx is canceled when the cluster is removed or the factory is stopped.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// started is set once the informers of the cluster got started.
+	started bool
+}
+
+var _ SharedInformerFactory = &sharedInformerFactory{}
+
+func (f *sharedInformerFactory) AddCluster(name string, config *rest.Config) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if _, exists := f.clusters[name]; exists {
+		return fmt.Errorf("cluster %q already exists", name)
+	}
+	factory, err := f.newFactory(name, config)
+	if err != nil {
+		return fmt.Errorf("cluster %q: %w", name, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &cluster{
+		name:    name,
+		factory: factory,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	clusterInformers := make(map[*informer]cache.SharedIndexInformer, len(f.informers))
+	for resource, i := range f.informers {
+		clusterInformer, err := factory.InformerForResource(resource)
+		if err != nil {
+			cancel()
+			return fmt.Errorf("cluster %q: %w", name, err)
+		}
+		clusterInformers[i] = clusterInformer
+	}
+	for i, clusterInformer := range clusterInformers {
+		i.addCluster(ctx, name, clusterInformer)
+	}
+	f.clusters[name] = c
+	f.startClusterLocked(c)
+	return nil
+}
+
+func (f *sharedInformerFactory) RemoveCluster(name string) {
+	f.lock.Lock()
+	c, exists := f.clusters[name]
+	if !exists {
+		f.lock.Unlock()
+		return
+	}
+	delete(f.clusters, name)
+	informers := make([]*informer, 0, len(f.informers))
+	for _, i := range f.informers {
+		informers = append(informers, i)
+	}
+	f.lock.Unlock()
+
+	// Nothing gets delivered anymore once the informers are shut down, so
+	// the final deletes cannot race with events from the cluster.
+	c.cancel()
+	c.factory.Shutdown()
+	for _, i := range informers {
+		i.removeCluster(c.ctx, name)
+	}
+}
+
+func (f *sharedInformerFactory) Clusters() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	names := make([]string, 0, len(f.clusters))
+	for name := range f.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (f *sharedInformerFactory) Start(stopCh <-chan struct{}) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.shuttingDown || f.stopCh != nil {
+		return
+	}
+	f.stopCh = stopCh
+	for _, c := range f.clusters {
+		f.startClusterLocked(c)
+	}
+}
+
+// startClusterLocked starts the informers of a cluster once the factory is
+// started. The caller must hold the factory lock.
+func (f *sharedInformerFactory) startClusterLocked(c *cluster) {
+	if f.shuttingDown || f.stopCh == nil {
+		return
+	}
+	if !c.started {
+		c.started = true
+		stopCh := f.stopCh
+		go func() {
+			select {
+			case <-stopCh:
+				c.cancel()
+			case <-c.ctx.Done():
+			}
+		}()
+	}
+	// Starting again is a no-op for informers which are already running.
+	c.factory.Start(c.ctx.Done())
+}
+
+func (f *sharedInformerFactory) WaitForCacheSync(stopCh <-chan struct{}) map[string]bool {
+	f.lock.Lock()
+	hasSynced := map[string][]cache.InformerSynced{}
+	for name := range f.clusters {
+		hasSynced[name] = nil
+	}
+	for _, i := range f.informers {
+		for name, clusterInformer := range i.clusterInformers() {
+			hasSynced[name] = append(hasSynced[name], clusterInformer.HasSynced)
+		}
+	}
+	f.lock.Unlock()
+
+	res := map[string]bool{}
+	for name, synced := range hasSynced {
+		res[name] = cache.WaitForCacheSync(stopCh, synced...)
+	}
+	return res
+}
+
+func (f *sharedInformerFactory) Shutdown() {
+	f.lock.Lock()
+	f.shuttingDown = true
+	clusters := make([]*cluster, 0, len(f.clusters))
+	for _, c := range f.clusters {
+		clusters = append(clusters, c)
+	}
+	f.lock.Unlock()
+
+	for _, c := range clusters {
+		c.cancel()
+	}
+	for _, c := range clusters {
+		c.factory.Shutdown()
+	}
+}
+
+func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (Informer, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if i, exists := f.informers[resource]; exists {
+		return i, nil
+	}
+	i := newInformer(resource)
+	for name, c := range f.clusters {
+		clusterInformer, err := c.factory.InformerForResource(resource)
+		if err != nil {
+			return nil, fmt.Errorf("cluster %q: %w", name, err)
+		}
+		i.addCluster(c.ctx, name, clusterInformer)
+	}
+	f.informers[resource] = i
+	for _, c := range f.clusters {
+		if c.started {
+			f.startClusterLocked(c)
+		}
+	}
+	return i, nil
+}
+
+// handleError reports errors of a cluster which cannot be returned. ctx is
+// the context of the cluster.
+func handleError(ctx context.Context, cluster string, err error, msg string) {
+	utilruntime.HandleErrorWithContext(ctx, err, msg, "cluster", cluster)
+}
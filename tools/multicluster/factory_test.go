@@ -0,0 +1,307 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	clientfeatures "k8s.io/client-go/features"
+	clientfeaturestesting "k8s.io/client-go/features/testing"
+	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+var (
+	widgetGVR = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	widgetGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+)
+
+func newWidget(namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(widgetGVK)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+// fakeClusterFactory serves informers from an ObjectTracker.
+type fakeClusterFactory struct {
+	tracker clienttesting.ObjectTracker
+
+	lock      sync.Mutex
+	informers map[schema.GroupVersionResource]cache.SharedIndexInformer
+	started   map[schema.GroupVersionResource]bool
+	wg        sync.WaitGroup
+}
+
+func newFakeClusterFactory(objects ...runtime.Object) (*fakeClusterFactory, error) {
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(widgetGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(widgetGVK.GroupVersion().WithKind("WidgetList"), &unstructured.UnstructuredList{})
+	tracker := clienttesting.NewObjectTracker(scheme, serializer.NewCodecFactory(scheme).UniversalDecoder())
+	for _, obj := range objects {
+		if err := tracker.Add(obj); err != nil {
+			return nil, err
+		}
+	}
+	return &fakeClusterFactory{
+		tracker:   tracker,
+		informers: map[schema.GroupVersionResource]cache.SharedIndexInformer{},
+		started:   map[schema.GroupVersionResource]bool{},
+	}, nil
+}
+
+func (f *fakeClusterFactory) Start(stopCh <-chan struct{}) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for resource, informer := range f.informers {
+		if !f.started[resource] {
+			f.started[resource] = true
+			f.wg.Add(1)
+			go func() {
+				defer f.wg.Done()
+				informer.Run(stopCh)
+			}()
+		}
+	}
+}
+
+func (f *fakeClusterFactory) Shutdown() {
+	f.wg.Wait()
+}
+
+func (f *fakeClusterFactory) InformerForResource(resource schema.GroupVersionResource) (cache.SharedIndexInformer, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if resource != widgetGVR {
+		return nil, fmt.Errorf("unknown resource %v", resource)
+	}
+	if informer, ok := f.informers[resource]; ok {
+		return informer, nil
+	}
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			return f.tracker.List(widgetGVR, widgetGVK, "", options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			return f.tracker.Watch(widgetGVR, "", options)
+		},
+	}, &unstructured.Unstructured{}, 0, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	f.informers[resource] = informer
+	return informer, nil
+}
+
+type recordedEvent struct {
+	cluster   string
+	eventType watch.EventType
+	key       string
+}
+
+type eventRecorder struct {
+	lock   sync.Mutex
+	events []recordedEvent
+}
+
+func (r *eventRecorder) record(cluster string, eventType watch.EventType, obj interface{}) {
+	key, _ := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = append(r.events, recordedEvent{cluster: cluster, eventType: eventType, key: key})
+}
+
+func (r *eventRecorder) handler() ResourceEventHandler {
+	return ResourceEventHandlerFuncs{
+		AddFunc: func(cluster string, obj interface{}, isInInitialList bool) {
+			r.record(cluster, watch.Added, obj)
+		},
+		UpdateFunc: func(cluster string, oldObj, newObj interface{}) {
+			r.record(cluster, watch.Modified, newObj)
+		},
+		DeleteFunc: func(cluster string, obj interface{}) {
+			r.record(cluster, watch.Deleted, obj)
+		},
+	}
+}
+
+func (r *eventRecorder) waitFor(t *testing.T, expected ...recordedEvent) {
+	t.Helper()
+	contains := func() bool {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		for _, e := range expected {
+			found := false
+			for _, a := range r.events {
+				if e == a {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+	err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 10*time.Second, true, func(context.Context) (bool, error) {
+		return contains(), nil
+	})
+	if err != nil {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		t.Fatalf("expected events %v, got %v", expected, r.events)
+	}
+}
+
+func TestSharedInformerFactory(t *testing.T) {
+	clientfeaturestesting.SetFeatureDuringTest(t, clientfeatures.WatchListClient, false)
+
+	clusterFactories := map[string]*fakeClusterFactory{}
+	objects := map[string][]runtime.Object{
+		"east": {newWidget("default", "a")},
+		"west": {newWidget("default", "a"), newWidget("other", "b")},
+		"late": {newWidget("default", "c")},
+	}
+	f := NewSharedInformerFactory(func(cluster string, config *rest.Config) (ClusterFactory, error) {
+		clusterFactory, err := newFakeClusterFactory(objects[cluster]...)
+		clusterFactories[cluster] = clusterFactory
+		return clusterFactory, err
+	})
+	for _, cluster := range []string{"east", "west"} {
+		if err := f.AddCluster(cluster, &rest.Config{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.AddCluster("east", &rest.Config{}); err == nil {
+		t.Errorf("expected an error when adding a cluster twice")
+	}
+
+	informer, err := f.ForResource(widgetGVR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := &eventRecorder{}
+	if err := informer.AddEventHandler(recorder.handler()); err != nil {
+		t.Fatal(err)
+	}
+
+	stopCh := make(chan struct{})
+	defer f.Shutdown()
+	defer close(stopCh)
+	f.Start(stopCh)
+	if e, a := map[string]bool{"east": true, "west": true}, f.WaitForCacheSync(stopCh); len(a) != 2 || !a["east"] || !a["west"] {
+		t.Fatalf("expected %v, got %v", e, a)
+	}
+	recorder.waitFor(t,
+		recordedEvent{"east", watch.Added, "default/a"},
+		recordedEvent{"west", watch.Added, "default/a"},
+		recordedEvent{"west", watch.Added, "other/b"},
+	)
+
+	// The merged view.
+	indexer := informer.GetIndexer()
+	keys := indexer.ListKeys()
+	sort.Strings(keys)
+	if e, a := []string{"east|default/a", "west|default/a", "west|other/b"}, keys; fmt.Sprint(e) != fmt.Sprint(a) {
+		t.Errorf("expected keys %v, got %v", e, a)
+	}
+	if _, exists, err := indexer.GetByKey(ClusterKey("west", "other/b")); err != nil || !exists {
+		t.Errorf("expected west|other/b to exist, got %v, %v", exists, err)
+	}
+	if items, err := indexer.ByIndex(ClusterIndexName, "west"); err != nil || len(items) != 2 {
+		t.Errorf("expected 2 objects in cluster west, got %v, %v", items, err)
+	}
+	if items, err := indexer.ByIndex(cache.NamespaceIndex, "default"); err != nil || len(items) != 2 {
+		t.Errorf("expected 2 objects in namespace default, got %v, %v", items, err)
+	}
+	if e, a := []string{"east", "west"}, indexer.ListIndexFuncValues(ClusterIndexName); fmt.Sprint(e) != fmt.Sprint(a) {
+		t.Errorf("expected clusters %v, got %v", e, a)
+	}
+	if err := indexer.Add(newWidget("default", "x")); err == nil {
+		t.Errorf("expected the merged indexer to be read-only")
+	}
+	if objs, err := informer.Lister().List(labels.Everything()); err != nil || len(objs) != 3 {
+		t.Errorf("expected 3 objects, got %v, %v", objs, err)
+	}
+	if _, err := informer.Lister().Cluster("west").ByNamespace("other").Get("b"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Events are tagged with their cluster.
+	if err := clusterFactories["west"].tracker.Create(widgetGVR, newWidget("default", "d"), "default"); err != nil {
+		t.Fatal(err)
+	}
+	recorder.waitFor(t, recordedEvent{"west", watch.Added, "default/d"})
+
+	// Clusters can be added and removed while running.
+	if err := f.AddCluster("late", &rest.Config{}); err != nil {
+		t.Fatal(err)
+	}
+	recorder.waitFor(t, recordedEvent{"late", watch.Added, "default/c"})
+	f.RemoveCluster("east")
+	recorder.waitFor(t, recordedEvent{"east", watch.Deleted, "default/a"})
+	if e, a := []string{"late", "west"}, f.Clusters(); fmt.Sprint(e) != fmt.Sprint(a) {
+		t.Errorf("expected clusters %v, got %v", e, a)
+	}
+	if _, exists, _ := indexer.GetByKey(ClusterKey("east", "default/a")); exists {
+		t.Errorf("expected the objects of a removed cluster to be gone")
+	}
+}
+
+func TestNewSharedInformerFactoryFromKubeconfig(t *testing.T) {
+	config := clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"one": {Server: "https://one.example.com"},
+			"two": {Server: "https://two.example.com"},
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"user": {Token: "token"},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"ctx-one": {Cluster: "one", AuthInfo: "user"},
+			"ctx-two": {Cluster: "two", AuthInfo: "user"},
+		},
+	}
+	hosts := map[string]string{}
+	f, err := NewSharedInformerFactoryFromKubeconfig(config, func(cluster string, config *rest.Config) (ClusterFactory, error) {
+		hosts[cluster] = config.Host
+		return newFakeClusterFactory()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, a := []string{"ctx-one", "ctx-two"}, f.Clusters(); fmt.Sprint(e) != fmt.Sprint(a) {
+		t.Errorf("expected clusters %v, got %v", e, a)
+	}
+	if e, a := "https://two.example.com", hosts["ctx-two"]; e != a {
+		t.Errorf("expected host %q, got %q", e, a)
+	}
+}
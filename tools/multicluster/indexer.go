@@ -0,0 +1,228 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/tools/cache"
+)
+
+// ClusterIndexName is the name of the index over the cluster names in the
+// Indexer of an Informer. Its values are the cluster names.
+const ClusterIndexName = "cluster"
+
+// clusterKeySeparator separates the cluster name from the key of the object
+// within the cluster, which cannot contain it because object names and
+// namespaces cannot.
+const clusterKeySeparator = "|"
+
+var errReadOnly = errors.New("the multi-cluster indexer is read-only")
+
+// ClusterKey returns the key of an object in the Indexer of an Informer,
+// given the name of its cluster and its key within the cluster, as returned
+// by cache.MetaNamespaceKeyFunc.
+func ClusterKey(cluster, key string) string {
+	return cluster + clusterKeySeparator + key
+}
+
+// SplitClusterKey returns the cluster name and the key within the cluster
+// of a key created by ClusterKey.
+func SplitClusterKey(clusterKey string) (cluster, key string, err error) {
+	i := strings.LastIndex(clusterKey, clusterKeySeparator)
+	if i < 0 {
+		return "", "", fmt.Errorf("unexpected cluster key format: %q", clusterKey)
+	}
+	return clusterKey[:i], clusterKey[i+len(clusterKeySeparator):], nil
+}
+
+// indexer is a read-only cache.Indexer which merges the indexers of the
+// informers of all clusters.
+type indexer struct {
+	informer *informer
+}
+
+var _ cache.Indexer = &indexer{}
+
+func (idx *indexer) Add(obj interface{}) error {
+	return errReadOnly
+}
+
+func (idx *indexer) Update(obj interface{}) error {
+	return errReadOnly
+}
+
+func (idx *indexer) Delete(obj interface{}) error {
+	return errReadOnly
+}
+
+func (idx *indexer) Replace(list []interface{}, resourceVersion string) error {
+	return errReadOnly
+}
+
+func (idx *indexer) Resync() error {
+	return nil
+}
+
+func (idx *indexer) AddIndexers(newIndexers cache.Indexers) error {
+	return errReadOnly
+}
+
+// LastStoreSyncResourceVersion returns an empty string because resource
+// versions of different clusters cannot be compared.
+func (idx *indexer) LastStoreSyncResourceVersion() string {
+	return ""
+}
+
+func (idx *indexer) Bookmark(rv string) {}
+
+func (idx *indexer) List() []interface{} {
+	var list []interface{}
+	for _, clusterInformer := range idx.informer.clusterInformers() {
+		list = append(list, clusterInformer.GetIndexer().List()...)
+	}
+	return list
+}
+
+func (idx *indexer) ListKeys() []string {
+	var keys []string
+	for name, clusterInformer := range idx.informer.clusterInformers() {
+		for _, key := range clusterInformer.GetIndexer().ListKeys() {
+			keys = append(keys, ClusterKey(name, key))
+		}
+	}
+	return keys
+}
+
+// Get is not supported because an object does not identify its cluster.
+// Use GetByKey with a key returned by ClusterKey instead.
+func (idx *indexer) Get(obj interface{}) (item interface{}, exists bool, err error) {
+	return nil, false, errors.New("the multi-cluster indexer cannot determine the cluster of an object, use GetByKey")
+}
+
+func (idx *indexer) GetByKey(clusterKey string) (item interface{}, exists bool, err error) {
+	cluster, key, err := SplitClusterKey(clusterKey)
+	if err != nil {
+		return nil, false, err
+	}
+	clusterInformer, ok := idx.informer.clusterInformers()[cluster]
+	if !ok {
+		return nil, false, nil
+	}
+	return clusterInformer.GetIndexer().GetByKey(key)
+}
+
+// Index returns the objects of all clusters which share an indexed value
+// with obj. It is not supported for ClusterIndexName.
+func (idx *indexer) Index(indexName string, obj interface{}) ([]interface{}, error) {
+	if indexName == ClusterIndexName {
+		return nil, errors.New("the multi-cluster indexer cannot determine the cluster of an object, use ByIndex")
+	}
+	var list []interface{}
+	for _, clusterInformer := range idx.informer.clusterInformers() {
+		items, err := clusterInformer.GetIndexer().Index(indexName, obj)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, items...)
+	}
+	return list, nil
+}
+
+func (idx *indexer) IndexKeys(indexName, indexedValue string) ([]string, error) {
+	clusters := idx.informer.clusterInformers()
+	var keys []string
+	if indexName == ClusterIndexName {
+		if clusterInformer, ok := clusters[indexedValue]; ok {
+			for _, key := range clusterInformer.GetIndexer().ListKeys() {
+				keys = append(keys, ClusterKey(indexedValue, key))
+			}
+		}
+		return keys, nil
+	}
+	for name, clusterInformer := range clusters {
+		clusterKeys, err := clusterInformer.GetIndexer().IndexKeys(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range clusterKeys {
+			keys = append(keys, ClusterKey(name, key))
+		}
+	}
+	return keys, nil
+}
+
+func (idx *indexer) ListIndexFuncValues(indexName string) []string {
+	clusters := idx.informer.clusterInformers()
+	if indexName == ClusterIndexName {
+		names := make([]string, 0, len(clusters))
+		for name := range clusters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+	values := map[string]struct{}{}
+	for _, clusterInformer := range clusters {
+		for _, value := range clusterInformer.GetIndexer().ListIndexFuncValues(indexName) {
+			values[value] = struct{}{}
+		}
+	}
+	ret := make([]string, 0, len(values))
+	for value := range values {
+		ret = append(ret, value)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func (idx *indexer) ByIndex(indexName, indexedValue string) ([]interface{}, error) {
+	clusters := idx.informer.clusterInformers()
+	if indexName == ClusterIndexName {
+		if clusterInformer, ok := clusters[indexedValue]; ok {
+			return clusterInformer.GetIndexer().List(), nil
+		}
+		return nil, nil
+	}
+	var list []interface{}
+	for _, clusterInformer := range clusters {
+		items, err := clusterInformer.GetIndexer().ByIndex(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, items...)
+	}
+	return list, nil
+}
+
+// GetIndexers returns the indexers of the clusters, plus ClusterIndexName.
+// The cluster index is implicit, its IndexFunc cannot be called.
+func (idx *indexer) GetIndexers() cache.Indexers {
+	indexers := cache.Indexers{}
+	for _, clusterInformer := range idx.informer.clusterInformers() {
+		for name, indexFunc := range clusterInformer.GetIndexer().GetIndexers() {
+			indexers[name] = indexFunc
+		}
+	}
+	indexers[ClusterIndexName] = func(obj interface{}) ([]string, error) {
+		return nil, errors.New("the cluster of an object is only known to the multi-cluster indexer")
+	}
+	return indexers
+}
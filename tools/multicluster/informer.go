@@ -0,0 +1,233 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicluster
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ResourceEventHandler is like cache.ResourceEventHandler, with the name of
+// the cluster which the object belongs to.
+type ResourceEventHandler interface {
+	OnAdd(cluster string, obj interface{}, isInInitialList bool)
+	OnUpdate(cluster string, oldObj, newObj interface{})
+	OnDelete(cluster string, obj interface{})
+}
+
+// ResourceEventHandlerFuncs is an adaptor to let you easily specify as many or
+// as few of the notification functions as you want while still implementing
+// ResourceEventHandler.
+type ResourceEventHandlerFuncs struct {
+	AddFunc    func(cluster string, obj interface{}, isInInitialList bool)
+	UpdateFunc func(cluster string, oldObj, newObj interface{})
+	DeleteFunc func(cluster string, obj interface{})
+}
+
+// OnAdd calls AddFunc if it's not nil.
+func (r ResourceEventHandlerFuncs) OnAdd(cluster string, obj interface{}, isInInitialList bool) {
+	if r.AddFunc != nil {
+		r.AddFunc(cluster, obj, isInInitialList)
+	}
+}
+
+// OnUpdate calls UpdateFunc if it's not nil.
+func (r ResourceEventHandlerFuncs) OnUpdate(cluster string, oldObj, newObj interface{}) {
+	if r.UpdateFunc != nil {
+		r.UpdateFunc(cluster, oldObj, newObj)
+	}
+}
+
+// OnDelete calls DeleteFunc if it's not nil.
+func (r ResourceEventHandlerFuncs) OnDelete(cluster string, obj interface{}) {
+	if r.DeleteFunc != nil {
+		r.DeleteFunc(cluster, obj)
+	}
+}
+
+// Informer is a shared informer for a resource in all clusters of a
+// SharedInformerFactory.
+type Informer interface {
+	// AddEventHandler adds a handler to the informers of all current and
+	// future clusters.
+	AddEventHandler(handler ResourceEventHandler) error
+
+	// HasSynced returns true if the informers of all current clusters have synced.
+	HasSynced() bool
+
+	// GetIndexer returns a read-only Indexer over the objects of all clusters.
+	// Its keys are built by ClusterKey and it has an index named
+	// ClusterIndexName over the names of the clusters.
+	GetIndexer() cache.Indexer
+
+	// Lister returns a lister over the objects of all clusters.
+	Lister() Lister
+}
+
+// Lister lists objects of all clusters of an Informer.
+type Lister interface {
+	// List lists the objects of all clusters matching the selector.
+	List(selector labels.Selector) ([]runtime.Object, error)
+
+	// Cluster returns a lister for the objects of a single cluster. It is
+	// empty if there is no such cluster.
+	Cluster(cluster string) cache.GenericLister
+}
+
+type informer struct {
+	resource schema.GroupVersionResource
+
+	lock     sync.RWMutex
+	clusters map[string]cache.SharedIndexInformer
+	handlers []ResourceEventHandler
+}
+
+var _ Informer = &informer{}
+
+func newInformer(resource schema.GroupVersionResource) *informer {
+	return &informer{
+		resource: resource,
+		clusters: map[string]cache.SharedIndexInformer{},
+	}
+}
+
+func (i *informer) AddEventHandler(handler ResourceEventHandler) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	for name, clusterInformer := range i.clusters {
+		if _, err := clusterInformer.AddEventHandler(&clusterHandler{cluster: name, handler: handler}); err != nil {
+			return err
+		}
+	}
+	i.handlers = append(i.handlers, handler)
+	return nil
+}
+
+func (i *informer) HasSynced() bool {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	for _, clusterInformer := range i.clusters {
+		if !clusterInformer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+func (i *informer) GetIndexer() cache.Indexer {
+	return &indexer{informer: i}
+}
+
+func (i *informer) Lister() Lister {
+	return &lister{informer: i}
+}
+
+// addCluster adds the informer of a cluster and registers all handlers with it.
+// ctx is the context of the cluster.
+func (i *informer) addCluster(ctx context.Context, name string, clusterInformer cache.SharedIndexInformer) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	for _, handler := range i.handlers {
+		if _, err := clusterInformer.AddEventHandler(&clusterHandler{cluster: name, handler: handler}); err != nil {
+			handleError(ctx, name, err, "Failed to add an event handler to the informer of a cluster")
+		}
+	}
+	i.clusters[name] = clusterInformer
+}
+
+// removeCluster removes the informer of a cluster, which must have been
+// stopped, and notifies the handlers that all of its objects are gone.
+// ctx is the context of the cluster.
+func (i *informer) removeCluster(ctx context.Context, name string) {
+	i.lock.Lock()
+	clusterInformer, exists := i.clusters[name]
+	delete(i.clusters, name)
+	handlers := i.handlers
+	i.lock.Unlock()
+	if !exists {
+		return
+	}
+
+	for _, obj := range clusterInformer.GetStore().List() {
+		key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		if err != nil {
+			handleError(ctx, name, err, "Failed to get the key of an object of a removed cluster")
+			continue
+		}
+		for _, handler := range handlers {
+			handler.OnDelete(name, cache.DeletedFinalStateUnknown{Key: key, Obj: obj})
+		}
+	}
+}
+
+// clusterInformers returns a copy of the informers per cluster.
+func (i *informer) clusterInformers() map[string]cache.SharedIndexInformer {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	clusters := make(map[string]cache.SharedIndexInformer, len(i.clusters))
+	for name, clusterInformer := range i.clusters {
+		clusters[name] = clusterInformer
+	}
+	return clusters
+}
+
+// clusterHandler adds the name of the cluster to the events of its informer.
+type clusterHandler struct {
+	cluster string
+	handler ResourceEventHandler
+}
+
+func (h *clusterHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	h.handler.OnAdd(h.cluster, obj, isInInitialList)
+}
+
+func (h *clusterHandler) OnUpdate(oldObj, newObj interface{}) {
+	h.handler.OnUpdate(h.cluster, oldObj, newObj)
+}
+
+func (h *clusterHandler) OnDelete(obj interface{}) {
+	h.handler.OnDelete(h.cluster, obj)
+}
+
+type lister struct {
+	informer *informer
+}
+
+func (l *lister) List(selector labels.Selector) ([]runtime.Object, error) {
+	var ret []runtime.Object
+	err := cache.ListAll(l.informer.GetIndexer(), selector, func(obj interface{}) {
+		ret = append(ret, obj.(runtime.Object))
+	})
+	return ret, err
+}
+
+func (l *lister) Cluster(cluster string) cache.GenericLister {
+	clusterInformer, exists := l.informer.clusterInformers()[cluster]
+	if !exists {
+		return cache.NewGenericLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}), l.informer.resource.GroupResource())
+	}
+	return cache.NewGenericLister(clusterInformer.GetIndexer(), l.informer.resource.GroupResource())
+}
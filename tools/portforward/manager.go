@@ -0,0 +1,630 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portforward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/transport/spdy"
+	"k8s.io/klog/v2"
+	streamhttp "k8s.io/streaming/pkg/httpstream"
+)
+
+// TargetKind is the kind of object whose pods a Manager forwards to.
+type TargetKind string
+
+const (
+	TargetKindPod        TargetKind = "Pod"
+	TargetKindService    TargetKind = "Service"
+	TargetKindDeployment TargetKind = "Deployment"
+)
+
+// Target identifies the object whose pods a Manager forwards to.
+type Target struct {
+	Kind      TargetKind
+	Namespace string
+	Name      string
+}
+
+func (t Target) String() string {
+	return fmt.Sprintf("%s/%s in namespace %s", strings.ToLower(string(t.Kind)), t.Name, t.Namespace)
+}
+
+// ManagerOptions holds the optional settings of a Manager.
+type ManagerOptions struct {
+	// Addresses are the local addresses to listen on. They default to localhost.
+	Addresses []string
+	// HealthCheckInterval is how often the Manager checks that the current pod
+	// is still ready and accepts TCP connections on the forwarded ports. Zero
+	// disables health checks, the Manager then only fails over when the
+	// connection to the pod is lost.
+	HealthCheckInterval time.Duration
+	// Out and ErrOut receive progress and error messages, like with PortForwarder.
+	Out    io.Writer
+	ErrOut io.Writer
+}
+
+// managerBackoff is the backoff between failed attempts to connect to a pod.
+var managerBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2.0,
+	Jitter:   0.1,
+	Steps:    5,
+	Cap:      30 * time.Second,
+}
+
+// healthCheckProbeTimeout is how long a health check waits for an error about
+// a forwarded port. Without an error within that time, the port is healthy.
+var healthCheckProbeTimeout = time.Second
+
+// ErrManagerStopped is returned for forwards added after Run returned.
+var ErrManagerStopped = errors.New("port forward manager stopped")
+
+// Manager forwards local ports to a ready pod of a pod, service or deployment.
+// Forwards can be added and removed while it runs, and it fails over to another
+// ready pod when the connection to the current one is lost or the pod fails
+// its health checks. The local ports stay the same across fail overs, but
+// connections to the previous pod are closed.
+//
+// For services, the remote ports of forwards are service ports, which are
+// mapped to the target ports of the selected pod.
+type Manager struct {
+	client    kubernetes.Interface
+	target    Target
+	addresses []listenAddress
+	// dialerFor returns the dialer for the port forward subresource of a pod.
+	dialerFor           func(pod *v1.Pod) (httpstream.Dialer, error)
+	healthCheckInterval time.Duration
+	out                 io.Writer
+	errOut              io.Writer
+
+	lock     sync.Mutex
+	logger   klog.Logger
+	running  bool
+	stopped  chan struct{}
+	forwards map[uint16]*managedForward
+	// forwarder is connected to pod, it is nil while (re)connecting.
+	forwarder *PortForwarder
+	pod       *v1.Pod
+	service   *v1.Service
+	// connected is closed and replaced when forwarder is set.
+	connected chan struct{}
+}
+
+// managedForward is a forward of a Manager and its listeners.
+type managedForward struct {
+	port      ForwardedPort
+	listeners []net.Listener
+}
+
+// NewManager creates a Manager for the target. It uses the clientset to find
+// ready pods and negotiates port forward connections to them over websockets,
+// falling back to SPDY if the server does not support the tunneling protocol.
+func NewManager(config *restclient.Config, client kubernetes.Interface, target Target, options ManagerOptions) (*Manager, error) {
+	switch target.Kind {
+	case TargetKindPod, TargetKindService, TargetKindDeployment:
+	default:
+		return nil, fmt.Errorf("unsupported port forward target kind %q", target.Kind)
+	}
+	if len(target.Name) == 0 {
+		return nil, errors.New("port forward target name must be set")
+	}
+	if len(options.Addresses) == 0 {
+		options.Addresses = []string{"localhost"}
+	}
+	addresses, err := parseAddresses(options.Addresses)
+	if err != nil {
+		return nil, err
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Manager{
+		client:    client,
+		target:    target,
+		addresses: addresses,
+		dialerFor: func(pod *v1.Pod) (httpstream.Dialer, error) {
+			url := client.CoreV1().RESTClient().Post().
+				Resource("pods").
+				Namespace(pod.Namespace).
+				Name(pod.Name).
+				SubResource("portforward").
+				URL()
+			tunnelingDialer, err := NewSPDYOverWebsocketDialer(url, config)
+			if err != nil {
+				return nil, err
+			}
+			spdyDialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+			return NewFallbackDialer(tunnelingDialer, spdyDialer, func(err error) bool {
+				return streamhttp.IsUpgradeFailure(err) || streamhttp.IsHTTPSProxyError(err)
+			}), nil
+		},
+		healthCheckInterval: options.HealthCheckInterval,
+		out:                 options.Out,
+		errOut:              options.ErrOut,
+		logger:              klog.Background(),
+		stopped:             make(chan struct{}),
+		forwards:            map[uint16]*managedForward{},
+		connected:           make(chan struct{}),
+	}, nil
+}
+
+// AddForward starts listening for a forward in the format accepted by New,
+// like "8080:80" or ":80". It returns the forwarded ports with the local port
+// which was bound. Local connections are forwarded as soon as the Manager is
+// connected to a pod.
+func (m *Manager) AddForward(port string) (ForwardedPort, error) {
+	ports, err := parsePorts([]string{port})
+	if err != nil {
+		return ForwardedPort{}, err
+	}
+	forward := &managedForward{port: ports[0]}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	select {
+	case <-m.stopped:
+		return ForwardedPort{}, ErrManagerStopped
+	default:
+	}
+	if _, exists := m.forwards[forward.port.Local]; exists && forward.port.Local != 0 {
+		return ForwardedPort{}, fmt.Errorf("local port %d is already forwarded", forward.port.Local)
+	}
+
+	// Listen like PortForwarder.listenOnPort does.
+	listenerCreator := &PortForwarder{out: m.out}
+	var errs []error
+	failCounters := make(map[string]int, 2)
+	successCounters := make(map[string]int, 2)
+	for _, addr := range m.addresses {
+		listener, err := listenerCreator.getListener(addr.protocol, addr.address, &forward.port)
+		if err != nil {
+			errs = append(errs, err)
+			failCounters[addr.failureMode]++
+			continue
+		}
+		successCounters[addr.failureMode]++
+		forward.listeners = append(forward.listeners, listener)
+	}
+	if (successCounters["all"] == 0 && failCounters["all"] > 0) || failCounters["any"] > 0 {
+		forward.close(m.logger)
+		return ForwardedPort{}, fmt.Errorf("listeners failed to create with the following errors: %v", errs)
+	}
+	m.forwards[forward.port.Local] = forward
+	for _, listener := range forward.listeners {
+		go m.waitForConnection(listener, forward.port.Local)
+	}
+	return forward.port, nil
+}
+
+// RemoveForward stops listening on a local port. Connections which were
+// already accepted on it are not interrupted.
+func (m *Manager) RemoveForward(localPort uint16) error {
+	m.lock.Lock()
+	forward, exists := m.forwards[localPort]
+	delete(m.forwards, localPort)
+	m.lock.Unlock()
+
+	if !exists {
+		return fmt.Errorf("local port %d is not forwarded", localPort)
+	}
+	forward.close(m.logger)
+	return nil
+}
+
+// Forwards returns the current forwards, sorted by local port.
+func (m *Manager) Forwards() []ForwardedPort {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	ports := make([]ForwardedPort, 0, len(m.forwards))
+	for _, forward := range m.forwards {
+		ports = append(ports, forward.port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Local < ports[j].Local })
+	return ports
+}
+
+// Pod returns the name of the pod which the Manager is connected to, or an
+// empty string while it is not connected.
+func (m *Manager) Pod() string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.forwarder == nil {
+		return ""
+	}
+	return m.pod.Name
+}
+
+// Run connects to a ready pod of the target and keeps forwarding to one until
+// the context is canceled. It then stops listening on all forwarded ports and
+// returns nil. It returns an error if a pod target does not exist.
+func (m *Manager) Run(ctx context.Context) error {
+	m.lock.Lock()
+	if m.running {
+		m.lock.Unlock()
+		return errors.New("port forward manager is already running")
+	}
+	m.running = true
+	m.lock.Unlock()
+	logger := klog.FromContext(ctx)
+	defer m.stop()
+
+	backoff := managerBackoff
+	var lost string
+	for {
+		forwarder, pod, service, err := m.connect(ctx, lost)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if m.target.Kind == TargetKindPod && apierrors.IsNotFound(err) {
+				return err
+			}
+			m.printErr("Unable to forward to %s: %v\n", m.target, err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(backoff.Step()):
+			}
+			continue
+		}
+		backoff = managerBackoff
+
+		if m.out != nil {
+			fmt.Fprintf(m.out, "Forwarding to pod %s\n", pod.Name)
+		}
+		m.setForwarder(forwarder, pod, service)
+		err = m.monitor(ctx, forwarder, pod, service)
+		m.setForwarder(nil, nil, nil)
+		_ = forwarder.streamConn.Close()
+		if ctx.Err() != nil {
+			return nil
+		}
+		logger.V(2).Info("Port forward failing over", "pod", klog.KObj(pod), "err", err)
+		m.printErr("Lost connection to pod %s: %v\n", pod.Name, err)
+		lost = pod.Name
+	}
+}
+
+// connect resolves a ready pod, preferring another one than the pod named
+// lost, and dials it.
+func (m *Manager) connect(ctx context.Context, lost string) (*PortForwarder, *v1.Pod, *v1.Service, error) {
+	pod, service, err := m.resolvePod(ctx, lost)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	dialer, err := m.dialerFor(pod)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	forwarder := &PortForwarder{
+		logger: klog.FromContext(ctx),
+		dialer: dialer,
+		out:    m.out,
+		errOut: m.errOut,
+	}
+	if err := forwarder.dial(); err != nil {
+		return nil, nil, nil, err
+	}
+	return forwarder, pod, service, nil
+}
+
+// resolvePod returns a ready pod of the target, and the service if the target
+// is a service.
+func (m *Manager) resolvePod(ctx context.Context, lost string) (*v1.Pod, *v1.Service, error) {
+	namespace, name := m.target.Namespace, m.target.Name
+	var selector labels.Selector
+	var service *v1.Service
+	switch m.target.Kind {
+	case TargetKindPod:
+		pod, err := m.client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if !isPodReady(pod) {
+			return nil, nil, fmt.Errorf("pod %s is not ready", name)
+		}
+		return pod, nil, nil
+	case TargetKindService:
+		svc, err := m.client.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(svc.Spec.Selector) == 0 {
+			return nil, nil, fmt.Errorf("service %s has no selector", name)
+		}
+		selector = labels.SelectorFromSet(svc.Spec.Selector)
+		service = svc
+	case TargetKindDeployment:
+		deployment, err := m.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		selector, err = metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	pods, err := m.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, nil, err
+	}
+	var ready []*v1.Pod
+	for i := range pods.Items {
+		if isPodReady(&pods.Items[i]) {
+			ready = append(ready, &pods.Items[i])
+		}
+	}
+	if len(ready) == 0 {
+		return nil, nil, fmt.Errorf("no ready pods for %s", m.target)
+	}
+	sort.Slice(ready, func(i, j int) bool { return ready[i].Name < ready[j].Name })
+	for _, pod := range ready {
+		if pod.Name != lost {
+			return pod, service, nil
+		}
+	}
+	return ready[0], service, nil
+}
+
+// monitor waits until the context is canceled, the connection is lost or a
+// health check fails.
+func (m *Manager) monitor(ctx context.Context, forwarder *PortForwarder, pod *v1.Pod, service *v1.Service) error {
+	var healthChecks <-chan time.Time
+	if m.healthCheckInterval > 0 {
+		ticker := time.NewTicker(m.healthCheckInterval)
+		defer ticker.Stop()
+		healthChecks = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-forwarder.streamConn.CloseChan():
+			return ErrLostConnectionToPod
+		case <-healthChecks:
+			if err := m.checkHealth(ctx, forwarder, pod, service); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// checkHealth checks that the pod is still ready and accepts TCP connections
+// on all forwarded ports.
+func (m *Manager) checkHealth(ctx context.Context, forwarder *PortForwarder, pod *v1.Pod, service *v1.Service) error {
+	current, err := m.client.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("pod %s was deleted", pod.Name)
+	case err != nil:
+		// Failing over does not help if the apiserver is unavailable.
+		klog.FromContext(ctx).V(4).Info("Unable to check the pod of a port forward", "pod", klog.KObj(pod), "err", err)
+	case current.UID != pod.UID || !isPodReady(current):
+		return fmt.Errorf("pod %s is not ready", pod.Name)
+	}
+
+	for _, port := range m.Forwards() {
+		remote, err := podPort(service, pod, port.Remote)
+		if err != nil {
+			return err
+		}
+		if err := forwarder.probe(remote, healthCheckProbeTimeout); err != nil {
+			return fmt.Errorf("health check of port %d failed: %w", remote, err)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) setForwarder(forwarder *PortForwarder, pod *v1.Pod, service *v1.Service) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.forwarder, m.pod, m.service = forwarder, pod, service
+	if forwarder != nil {
+		close(m.connected)
+		m.connected = make(chan struct{})
+	}
+}
+
+// stop closes the listeners of all forwards.
+func (m *Manager) stop() {
+	m.lock.Lock()
+	forwards := m.forwards
+	m.forwards = map[uint16]*managedForward{}
+	close(m.stopped)
+	m.lock.Unlock()
+
+	for _, forward := range forwards {
+		forward.close(m.logger)
+	}
+}
+
+func (m *Manager) printErr(format string, args ...any) {
+	if m.errOut != nil {
+		fmt.Fprintf(m.errOut, format, args...)
+	}
+}
+
+// waitForConnection accepts connections to a forwarded local port until its
+// listener is closed.
+func (m *Manager) waitForConnection(listener net.Listener, localPort uint16) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !strings.Contains(strings.ToLower(err.Error()), networkClosedError) {
+				runtime.HandleErrorWithLogger(m.logger, err, "Error accepting connection", "localPort", localPort)
+			}
+			return
+		}
+		go m.handleConnection(conn, localPort)
+	}
+}
+
+// handleConnection waits until the Manager is connected to a pod and forwards
+// the connection to it.
+func (m *Manager) handleConnection(conn net.Conn, localPort uint16) {
+	for {
+		m.lock.Lock()
+		forward, exists := m.forwards[localPort]
+		forwarder, pod, service := m.forwarder, m.pod, m.service
+		connected := m.connected
+		m.lock.Unlock()
+
+		if !exists {
+			_ = conn.Close()
+			return
+		}
+		if forwarder != nil {
+			remote, err := podPort(service, pod, forward.port.Remote)
+			if err != nil {
+				runtime.HandleErrorWithLogger(m.logger, err, "Error forwarding connection", "localPort", localPort)
+				_ = conn.Close()
+				return
+			}
+			forwarder.handleConnection(conn, ForwardedPort{Local: localPort, Remote: remote})
+			return
+		}
+		select {
+		case <-connected:
+		case <-m.stopped:
+			_ = conn.Close()
+			return
+		}
+	}
+}
+
+func (f *managedForward) close(logger klog.Logger) {
+	for _, listener := range f.listeners {
+		if err := listener.Close(); err != nil {
+			runtime.HandleErrorWithLogger(logger, err, "Error closing listener")
+		}
+	}
+}
+
+// probe checks that the pod accepts TCP connections on a port. The kubelet
+// reports failed connections on the error stream, so the port is considered
+// healthy if no error arrives within the timeout.
+func (pf *PortForwarder) probe(port uint16, timeout time.Duration) error {
+	headers := http.Header{}
+	headers.Set(v1.StreamType, v1.StreamTypeError)
+	headers.Set(v1.PortHeader, strconv.Itoa(int(port)))
+	headers.Set(v1.PortForwardRequestIDHeader, strconv.Itoa(pf.nextRequestID()))
+	errorStream, err := pf.streamConn.CreateStream(headers)
+	if err != nil {
+		return err
+	}
+	// we're not writing to this stream
+	errorStream.Close()
+	defer pf.streamConn.RemoveStreams(errorStream)
+
+	headers.Set(v1.StreamType, v1.StreamTypeData)
+	dataStream, err := pf.streamConn.CreateStream(headers)
+	if err != nil {
+		return err
+	}
+	defer pf.streamConn.RemoveStreams(dataStream)
+	// nothing is sent to the port
+	dataStream.Close()
+
+	errorChan := make(chan error, 1)
+	go func() {
+		message, err := io.ReadAll(errorStream)
+		switch {
+		case err != nil:
+			errorChan <- err
+		case len(message) > 0:
+			errorChan <- errors.New(string(message))
+		default:
+			errorChan <- nil
+		}
+	}()
+	defer func() { _ = dataStream.Reset() }()
+
+	select {
+	case err := <-errorChan:
+		return err
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+// podPort maps a remote port of a forward to the port of the pod. For
+// services, it is the target port of the service port.
+func podPort(service *v1.Service, pod *v1.Pod, port uint16) (uint16, error) {
+	if service == nil {
+		return port, nil
+	}
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Port != int32(port) || (servicePort.Protocol != "" && servicePort.Protocol != v1.ProtocolTCP) {
+			continue
+		}
+		switch {
+		case servicePort.TargetPort.Type == intstr.String:
+			for _, container := range pod.Spec.Containers {
+				for _, containerPort := range container.Ports {
+					if containerPort.Name == servicePort.TargetPort.StrVal && (containerPort.Protocol == "" || containerPort.Protocol == v1.ProtocolTCP) {
+						return uint16(containerPort.ContainerPort), nil
+					}
+				}
+			}
+			return 0, fmt.Errorf("pod %s has no port named %q", pod.Name, servicePort.TargetPort.StrVal)
+		case servicePort.TargetPort.IntVal != 0:
+			return uint16(servicePort.TargetPort.IntVal), nil
+		default:
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("service %s does not have TCP port %d", service.Name, port)
+}
+
+// isPodReady returns true if a pod is running, ready and not being deleted.
+func isPodReady(pod *v1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
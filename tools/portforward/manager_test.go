@@ -0,0 +1,252 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portforward
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+)
+
+func newTestPod(name string, ready bool) *v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "web"}},
+		Spec: v1.PodSpec{Containers: []v1.Container{{
+			Name:  "web",
+			Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+		}}},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+		},
+	}
+}
+
+func newTestService() *v1.Service {
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: v1.ServiceSpec{
+			Selector: map[string]string{"app": "web"},
+			Ports: []v1.ServicePort{
+				{Port: 80, TargetPort: intstr.FromString("http")},
+				{Port: 81, TargetPort: intstr.FromInt32(9090)},
+				{Port: 82},
+				{Port: 83, Protocol: v1.ProtocolUDP},
+			},
+		},
+	}
+}
+
+func newTestManager(t *testing.T, target Target, objects ...runtime.Object) *Manager {
+	m, err := NewManager(&restclient.Config{Host: "localhost"}, fake.NewClientset(objects...), target, ManagerOptions{Addresses: []string{"127.0.0.1"}})
+	require.NoError(t, err)
+	return m
+}
+
+func TestManagerResolvePod(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+	}
+	objects := []runtime.Object{newTestService(), deployment, newTestPod("a", false), newTestPod("b", true), newTestPod("c", true)}
+
+	tests := []struct {
+		name        string
+		target      Target
+		lost        string
+		expectedPod string
+		expectedErr bool
+	}{
+		{name: "pod", target: Target{Kind: TargetKindPod, Namespace: "default", Name: "b"}, expectedPod: "b"},
+		{name: "unready pod", target: Target{Kind: TargetKindPod, Namespace: "default", Name: "a"}, expectedErr: true},
+		{name: "missing pod", target: Target{Kind: TargetKindPod, Namespace: "default", Name: "d"}, expectedErr: true},
+		{name: "service", target: Target{Kind: TargetKindService, Namespace: "default", Name: "web"}, expectedPod: "b"},
+		{name: "service after losing a pod", target: Target{Kind: TargetKindService, Namespace: "default", Name: "web"}, lost: "b", expectedPod: "c"},
+		{name: "deployment", target: Target{Kind: TargetKindDeployment, Namespace: "default", Name: "web"}, lost: "b", expectedPod: "c"},
+		{name: "no ready pods", target: Target{Kind: TargetKindService, Namespace: "other", Name: "web"}, expectedErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newTestManager(t, test.target, objects...)
+			pod, service, err := m.resolvePod(context.Background(), test.lost)
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedPod, pod.Name)
+			assert.Equal(t, test.target.Kind == TargetKindService, service != nil)
+		})
+	}
+}
+
+func TestPodPort(t *testing.T) {
+	service := newTestService()
+	pod := newTestPod("a", true)
+	for port, expected := range map[uint16]uint16{80: 8080, 81: 9090, 82: 82} {
+		actual, err := podPort(service, pod, port)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+	for _, port := range []uint16{83, 84} {
+		_, err := podPort(service, pod, port)
+		assert.Error(t, err, "port %d", port)
+	}
+	actual, err := podPort(nil, pod, 84)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(84), actual)
+}
+
+func TestManagerAddRemoveForward(t *testing.T) {
+	out := &bytes.Buffer{}
+	m := newTestManager(t, Target{Kind: TargetKindService, Namespace: "default", Name: "web"})
+	m.out = out
+
+	port, err := m.AddForward(":80")
+	require.NoError(t, err)
+	assert.NotZero(t, port.Local)
+	assert.Equal(t, uint16(80), port.Remote)
+	assert.Equal(t, []ForwardedPort{port}, m.Forwards())
+	assert.Contains(t, out.String(), "Forwarding from 127.0.0.1:"+strconv.Itoa(int(port.Local))+" -> 80")
+
+	_, err = m.AddForward(strconv.Itoa(int(port.Local)) + ":81")
+	assert.Error(t, err, "expected an error for a local port which is already forwarded")
+
+	require.NoError(t, m.RemoveForward(port.Local))
+	assert.Empty(t, m.Forwards())
+	_, err = net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port.Local))))
+	assert.Error(t, err, "expected the listener to be closed")
+	assert.Error(t, m.RemoveForward(port.Local))
+}
+
+// podDialer hands out a fake connection per pod.
+type podDialer struct {
+	lock  sync.Mutex
+	conns map[string]*fakeConnection
+}
+
+func (d *podDialer) dialerFor(pod *v1.Pod) (httpstream.Dialer, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	conn := newFakeConnection()
+	d.conns[pod.Name] = conn
+	return &fakeDialer{conn: conn, negotiatedProtocol: PortForwardProtocolV1Name}, nil
+}
+
+func (d *podDialer) conn(pod string) *fakeConnection {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.conns[pod]
+}
+
+func TestManagerFailsOver(t *testing.T) {
+	errOut := &bytes.Buffer{}
+	m := newTestManager(t, Target{Kind: TargetKindService, Namespace: "default", Name: "web"},
+		newTestService(), newTestPod("a", true), newTestPod("b", true))
+	m.errOut = errOut
+	dialer := &podDialer{conns: map[string]*fakeConnection{}}
+	m.dialerFor = dialer.dialerFor
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errChan := make(chan error)
+	go func() {
+		errChan <- m.Run(ctx)
+	}()
+
+	waitForPod := func(name string) {
+		t.Helper()
+		err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, wait.ForeverTestTimeout, true, func(context.Context) (bool, error) {
+			return m.Pod() == name, nil
+		})
+		require.NoError(t, err, "expected to forward to pod %s, got %q", name, m.Pod())
+	}
+	waitForPod("a")
+
+	// Losing the connection fails over to the other pod.
+	require.NoError(t, dialer.conn("a").Close())
+	waitForPod("b")
+	assert.Contains(t, errOut.String(), "Lost connection to pod a")
+
+	_, err := m.AddForward(":80")
+	require.NoError(t, err)
+
+	cancel()
+	require.NoError(t, <-errChan)
+	assert.True(t, dialer.conn("b").closed)
+	assert.Empty(t, m.Forwards())
+	_, err = m.AddForward(":80")
+	assert.ErrorIs(t, err, ErrManagerStopped)
+}
+
+func TestProbe(t *testing.T) {
+	tests := map[string]struct {
+		errorStream string
+		expectErr   bool
+	}{
+		"port accepts connections": {},
+		"connection refused":       {errorStream: "connection refused", expectErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			conn := newFakeConnection()
+			conn.errorStream.readFunc = bytes.NewBufferString(test.errorStream).Read
+			pf := &PortForwarder{streamConn: conn}
+
+			err := pf.probe(8080, wait.ForeverTestTimeout)
+			if test.expectErr {
+				assert.ErrorContains(t, err, test.errorStream)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, 0, conn.streamCount, "stream count should be zero")
+		})
+	}
+
+	// A port is healthy if no error arrives in time.
+	conn := newFakeConnection()
+	block := make(chan struct{})
+	defer close(block)
+	conn.errorStream.readFunc = func(p []byte) (int, error) {
+		<-block
+		return 0, io.EOF
+	}
+	pf := &PortForwarder{streamConn: conn}
+	assert.NoError(t, pf.probe(8080, 10*time.Millisecond))
+}
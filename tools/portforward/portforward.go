@@ -315,18 +315,27 @@ func (s *compatStreamAdapter) Identifier() uint32 {
 func (pf *PortForwarder) ForwardPorts() error {
 	defer pf.Close()
 
+	if err := pf.dial(); err != nil {
+		return err
+	}
+	defer pf.streamConn.Close()
+
+	return pf.forward()
+}
+
+// dial upgrades the connection to the remote pod.
+func (pf *PortForwarder) dial() error {
 	var err error
 	var protocol string
 	pf.streamConn, protocol, err = pf.dialer.Dial(PortForwardProtocolV1Name)
 	if err != nil {
 		return fmt.Errorf("error upgrading connection: %s", err)
 	}
-	defer pf.streamConn.Close()
 	if protocol != PortForwardProtocolV1Name {
+		pf.streamConn.Close()
 		return fmt.Errorf("unable to negotiate protocol: client supports %q, server returned %q", PortForwardProtocolV1Name, protocol)
 	}
-
-	return pf.forward()
+	return nil
 }
 
 // forward dials the remote host specific in req, upgrades the request, starts
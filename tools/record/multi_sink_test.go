@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/metrics"
+)
+
+type fakeEventsMetric struct {
+	lock   sync.Mutex
+	counts map[string]int
+}
+
+func (m *fakeEventsMetric) Increment(eventType, reason string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.counts[eventType+"/"+reason]++
+}
+
+func makeMultiSinkTestEvent(kind, apiVersion, eventType, reason string) *v1.Event {
+	return &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "foo." + reason},
+		InvolvedObject: v1.ObjectReference{Kind: kind, APIVersion: apiVersion, Namespace: metav1.NamespaceDefault, Name: "foo"},
+		Type:           eventType,
+		Reason:         reason,
+	}
+}
+
+func TestMultiSink(t *testing.T) {
+	metric := &fakeEventsMetric{counts: map[string]int{}}
+	previous := metrics.EventsWritten
+	metrics.EventsWritten = metric
+	t.Cleanup(func() { metrics.EventsWritten = previous })
+
+	var primaryErr error
+	primary := &testEventSink{
+		OnCreate: func(e *v1.Event) (*v1.Event, error) {
+			if primaryErr != nil {
+				return nil, primaryErr
+			}
+			written := e.DeepCopy()
+			written.ResourceVersion = "1"
+			return written, nil
+		},
+	}
+	var file bytes.Buffer
+	sink := NewMultiSink(primary,
+		FilteredSink{Sink: NewJSONSink(&file)},
+		FilteredSink{Sink: NewMetricsSink()},
+	)
+
+	if _, err := sink.Create(makeMultiSinkTestEvent("Pod", "v1", v1.EventTypeWarning, "BackOff")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := sink.Patch(makeMultiSinkTestEvent("Pod", "v1", v1.EventTypeNormal, "Pulled"), []byte("{}")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	primaryErr = fmt.Errorf("apiserver unavailable")
+	if _, err := sink.Create(makeMultiSinkTestEvent("Pod", "v1", v1.EventTypeWarning, "Failed")); err != primaryErr {
+		t.Fatalf("expected the error of the primary sink, got %v", err)
+	}
+
+	// Only created events are counted, the event of the failed write is
+	// neither counted nor written to the file.
+	if metric.counts["Warning/BackOff"] != 1 || len(metric.counts) != 1 {
+		t.Errorf("unexpected counts: %v", metric.counts)
+	}
+	decoder := json.NewDecoder(&file)
+	var reasons []string
+	for decoder.More() {
+		var event v1.Event
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		reasons = append(reasons, event.Reason)
+	}
+	if len(reasons) != 2 || reasons[0] != "BackOff" || reasons[1] != "Pulled" {
+		t.Errorf("expected two events in the file, got %v", reasons)
+	}
+}
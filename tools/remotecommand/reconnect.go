@@ -0,0 +1,376 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotecommand
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	gwebsocket "github.com/gorilla/websocket"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/util/exec"
+	"k8s.io/klog/v2"
+	"k8s.io/streaming/pkg/httpstream"
+)
+
+// DefaultReconnectBackoff is the backoff used between reconnect attempts if
+// ReconnectOptions.Backoff is not set.
+var DefaultReconnectBackoff = wait.Backoff{
+	Duration: 250 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.1,
+	Steps:    8,
+	Cap:      10 * time.Second,
+}
+
+// defaultReconnectResetPeriod is the default of ReconnectOptions.ResetPeriod.
+const defaultReconnectResetPeriod = 30 * time.Second
+
+// ReconnectOptions configures the executor returned by NewReconnectingExecutor.
+type ReconnectOptions struct {
+	// Backoff is the backoff between reconnect attempts. Steps is the
+	// maximum number of consecutive attempts before the stream fails.
+	// It defaults to DefaultReconnectBackoff.
+	Backoff *wait.Backoff
+	// ResetPeriod is how long a stream must stay up for the backoff to
+	// start over at its next failure. It defaults to 30 seconds.
+	ResetPeriod time.Duration
+	// OnReconnect, if set, is called before every reconnect attempt.
+	OnReconnect func(ReconnectEvent)
+}
+
+// ReconnectEvent describes a reconnect attempt.
+type ReconnectEvent struct {
+	// Attempt counts the consecutive reconnect attempts, starting at 1.
+	Attempt int
+	// Delay is how long the executor waits before the attempt.
+	Delay time.Duration
+	// Err is the error which ended the previous stream.
+	Err error
+}
+
+// NotResumableError is returned by the executor of NewReconnectingExecutor
+// when the connection of a stream which cannot be resumed, like exec, is lost.
+// The remote command may or may not still be running.
+type NotResumableError struct {
+	Err error
+}
+
+func (e *NotResumableError) Error() string {
+	return fmt.Sprintf("connection to the remote command was lost, the stream cannot be resumed: %v", e.Err)
+}
+
+func (e *NotResumableError) Unwrap() error {
+	return e.Err
+}
+
+var _ Executor = &reconnectingExecutor{}
+
+// reconnectingExecutor re-establishes the stream of an idempotent request,
+// like attach, after the connection is lost.
+type reconnectingExecutor struct {
+	executor Executor
+	// resumable is false for requests which must not be repeated, like exec.
+	resumable   bool
+	backoff     wait.Backoff
+	resetPeriod time.Duration
+	onReconnect func(ReconnectEvent)
+}
+
+// NewReconnectingExecutor returns a websocket Executor which reconnects when
+// the connection is lost, for example during a network outage, until the
+// remote command ends or the backoff of the options is exhausted.
+//
+// Only attach requests are resumed, because attaching again is idempotent.
+// Output written while the client was disconnected is not replayed. The
+// stdin and terminal size queue of the StreamOptions are shared by the
+// successive streams, and the last terminal size is sent again after each
+// reconnect. For any other request, like exec, the lost connection is
+// reported as a NotResumableError.
+func NewReconnectingExecutor(config *restclient.Config, method, url string, options ReconnectOptions) (Executor, error) {
+	executor, err := NewWebSocketExecutor(config, method, url)
+	if err != nil {
+		return nil, err
+	}
+	resumable, err := isAttachURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return newReconnectingExecutor(executor, resumable, options), nil
+}
+
+func newReconnectingExecutor(executor Executor, resumable bool, options ReconnectOptions) *reconnectingExecutor {
+	e := &reconnectingExecutor{
+		executor:    executor,
+		resumable:   resumable,
+		backoff:     DefaultReconnectBackoff,
+		resetPeriod: options.ResetPeriod,
+		onReconnect: options.OnReconnect,
+	}
+	if options.Backoff != nil {
+		e.backoff = *options.Backoff
+	}
+	if e.resetPeriod <= 0 {
+		e.resetPeriod = defaultReconnectResetPeriod
+	}
+	return e
+}
+
+// isAttachURL returns true if the request URL is a pod attach request.
+func isAttachURL(rawURL string) (bool, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false, err
+	}
+	return path.Base(u.Path) == "attach", nil
+}
+
+// Stream is deprecated. Please use "StreamWithContext".
+func (e *reconnectingExecutor) Stream(options StreamOptions) error {
+	return e.StreamWithContext(context.Background(), options)
+}
+
+// StreamWithContext runs streams with the wrapped executor until one of them
+// ends for another reason than a lost connection.
+func (e *reconnectingExecutor) StreamWithContext(ctx context.Context, options StreamOptions) error {
+	logger := klog.FromContext(ctx)
+	// stop ends the goroutines of the buffers once the last stream ended.
+	stop := make(chan struct{})
+	defer close(stop)
+	var stdin *stdinBuffer
+	if options.Stdin != nil {
+		stdin = newStdinBuffer(options.Stdin, stop)
+	}
+	var sizes *terminalSizeBuffer
+	if options.TerminalSizeQueue != nil {
+		sizes = newTerminalSizeBuffer(options.TerminalSizeQueue, stop)
+	}
+
+	backoff := e.backoff
+	attempt := 0
+	for {
+		sessionOptions := options
+		done := make(chan struct{})
+		if stdin != nil {
+			sessionOptions.Stdin = &sessionStdin{buffer: stdin, done: done}
+		}
+		if sizes != nil {
+			sessionOptions.TerminalSizeQueue = &sessionTerminalSizeQueue{buffer: sizes, done: done}
+		}
+
+		start := time.Now()
+		err := e.executor.StreamWithContext(ctx, sessionOptions)
+		close(done)
+		if err == nil || ctx.Err() != nil || !isConnectionLost(err) {
+			return err
+		}
+		if !e.resumable {
+			return &NotResumableError{Err: err}
+		}
+
+		if time.Since(start) >= e.resetPeriod {
+			backoff = e.backoff
+			attempt = 0
+		}
+		if backoff.Steps < 1 {
+			return fmt.Errorf("giving up after %d reconnect attempts: %w", attempt, err)
+		}
+		attempt++
+		delay := backoff.Step()
+		logger.V(2).Info("Remote command connection lost, reconnecting", "attempt", attempt, "delay", delay, "err", err)
+		if e.onReconnect != nil {
+			e.onReconnect(ReconnectEvent{Attempt: attempt, Delay: delay, Err: err})
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// isConnectionLost returns true if a stream failed because its connection was
+// lost or could not be established, rather than because the remote command or
+// the server ended it. Only transport errors and websocket connections which
+// were closed abnormally count, a stream which just ended with io.EOF does not.
+func isConnectionLost(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var exitErr exec.ExitError
+	var statusErr apierrors.APIStatus
+	var upgradeErr *httpstream.UpgradeFailureError
+	if errors.As(err, &exitErr) || errors.As(err, &statusErr) || errors.As(err, &upgradeErr) {
+		return false
+	}
+	var netErr net.Error
+	var closeErr *gwebsocket.CloseError
+	if errors.As(err, &closeErr) {
+		return closeErr.Code != gwebsocket.CloseNormalClosure
+	}
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// stdinBuffer reads the stdin of a reconnecting stream, so that data which is
+// read while a connection is lost goes to the next stream. Its goroutine ends
+// with the first read which returns after stop is closed.
+type stdinBuffer struct {
+	chunks chan []byte
+	// err is the error which ended stdin; it is set before chunks is closed.
+	err error
+
+	lock    sync.Mutex
+	pending []byte
+}
+
+func newStdinBuffer(stdin io.Reader, stop <-chan struct{}) *stdinBuffer {
+	b := &stdinBuffer{chunks: make(chan []byte)}
+	go func() {
+		defer close(b.chunks)
+		for {
+			buf := make([]byte, 32*1024)
+			n, err := stdin.Read(buf)
+			if n > 0 {
+				select {
+				case b.chunks <- buf[:n]:
+				case <-stop:
+					return
+				}
+			}
+			if err != nil {
+				b.err = err
+				return
+			}
+		}
+	}()
+	return b
+}
+
+// sessionStdin is the stdin of a single stream. It returns io.EOF once the
+// stream ended and leaves the remaining data to the next stream.
+type sessionStdin struct {
+	buffer *stdinBuffer
+	done   <-chan struct{}
+}
+
+func (s *sessionStdin) Read(p []byte) (int, error) {
+	b := s.buffer
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if len(b.pending) == 0 {
+		select {
+		case <-s.done:
+			return 0, io.EOF
+		case chunk, ok := <-b.chunks:
+			if !ok {
+				return 0, b.err
+			}
+			b.pending = chunk
+		}
+		// Keep the data for the next stream if this one ended meanwhile.
+		select {
+		case <-s.done:
+			return 0, io.EOF
+		default:
+		}
+	}
+	n := copy(p, b.pending)
+	b.pending = b.pending[n:]
+	return n, nil
+}
+
+// terminalSizeBuffer remembers the last terminal size of a reconnecting
+// stream, so that it can be sent again after a reconnect. Its goroutine ends
+// with the first size which the queue returns after stop is closed.
+type terminalSizeBuffer struct {
+	lock       sync.Mutex
+	size       *TerminalSize
+	generation int
+	stopped    bool
+	// changed is closed and replaced whenever the size changes.
+	changed chan struct{}
+}
+
+func newTerminalSizeBuffer(queue TerminalSizeQueue, stop <-chan struct{}) *terminalSizeBuffer {
+	b := &terminalSizeBuffer{changed: make(chan struct{})}
+	go func() {
+		for {
+			size := queue.Next()
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			b.lock.Lock()
+			if size == nil {
+				b.stopped = true
+			} else {
+				b.size = &TerminalSize{Width: size.Width, Height: size.Height}
+				b.generation++
+			}
+			close(b.changed)
+			b.changed = make(chan struct{})
+			b.lock.Unlock()
+			if size == nil {
+				return
+			}
+		}
+	}()
+	return b
+}
+
+// sessionTerminalSizeQueue is the terminal size queue of a single stream.
+// It starts with the last known size and stops when the stream ends.
+type sessionTerminalSizeQueue struct {
+	buffer     *terminalSizeBuffer
+	done       <-chan struct{}
+	generation int
+}
+
+func (q *sessionTerminalSizeQueue) Next() *TerminalSize {
+	b := q.buffer
+	for {
+		b.lock.Lock()
+		if b.size != nil && b.generation != q.generation {
+			q.generation = b.generation
+			size := *b.size
+			b.lock.Unlock()
+			return &size
+		}
+		stopped, changed := b.stopped, b.changed
+		b.lock.Unlock()
+		if stopped {
+			return nil
+		}
+		select {
+		case <-q.done:
+			return nil
+		case <-changed:
+		}
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remotecommand
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	gwebsocket "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/exec"
+)
+
+// fakeSession is what the fakeExecutor saw during one stream.
+type fakeSession struct {
+	stdin string
+	size  *TerminalSize
+}
+
+// fakeExecutor reads one chunk of stdin and the first terminal size in every
+// stream and then fails with the next of its errors.
+type fakeExecutor struct {
+	errs     []error
+	sessions []fakeSession
+}
+
+func (e *fakeExecutor) Stream(options StreamOptions) error {
+	return e.StreamWithContext(context.Background(), options)
+}
+
+func (e *fakeExecutor) StreamWithContext(ctx context.Context, options StreamOptions) error {
+	var session fakeSession
+	if options.TerminalSizeQueue != nil {
+		session.size = options.TerminalSizeQueue.Next()
+	}
+	if options.Stdin != nil {
+		buf := make([]byte, 1)
+		n, _ := options.Stdin.Read(buf)
+		session.stdin = string(buf[:n])
+	}
+	e.sessions = append(e.sessions, session)
+	err := e.errs[0]
+	e.errs = e.errs[1:]
+	return err
+}
+
+type chanTerminalSizeQueue chan *TerminalSize
+
+func (q chanTerminalSizeQueue) Next() *TerminalSize {
+	return <-q
+}
+
+var connectionReset = &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+
+func testBackoff() *wait.Backoff {
+	return &wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3}
+}
+
+func TestReconnectingExecutorResumesAttach(t *testing.T) {
+	fake := &fakeExecutor{errs: []error{
+		fmt.Errorf("error reading from error stream: next reader: %w", connectionReset),
+		fmt.Errorf("error reading from error stream: next reader: %w", io.ErrUnexpectedEOF),
+		nil,
+	}}
+	var events []ReconnectEvent
+	e := newReconnectingExecutor(fake, true, ReconnectOptions{
+		Backoff:     testBackoff(),
+		OnReconnect: func(event ReconnectEvent) { events = append(events, event) },
+	})
+
+	sizes := make(chanTerminalSizeQueue, 1)
+	sizes <- &TerminalSize{Width: 80, Height: 24}
+	stdinReader, stdinWriter := io.Pipe()
+	go func() {
+		_, _ = stdinWriter.Write([]byte("abc"))
+	}()
+	err := e.StreamWithContext(context.Background(), StreamOptions{Stdin: stdinReader, Stdout: io.Discard, Tty: true, TerminalSizeQueue: sizes})
+	require.NoError(t, err)
+
+	// Every stream got the last terminal size and the remaining stdin.
+	expected := []fakeSession{
+		{stdin: "a", size: &TerminalSize{Width: 80, Height: 24}},
+		{stdin: "b", size: &TerminalSize{Width: 80, Height: 24}},
+		{stdin: "c", size: &TerminalSize{Width: 80, Height: 24}},
+	}
+	assert.Equal(t, expected, fake.sessions)
+	require.Len(t, events, 2)
+	for i, event := range events {
+		assert.Equal(t, i+1, event.Attempt)
+	}
+	assert.ErrorIs(t, events[0].Err, syscall.ECONNRESET)
+	assert.ErrorIs(t, events[1].Err, io.ErrUnexpectedEOF)
+}
+
+func TestReconnectingExecutorStopsBuffers(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	fake := &fakeExecutor{errs: []error{nil}}
+	e := newReconnectingExecutor(fake, true, ReconnectOptions{Backoff: testBackoff()})
+	sizes := make(chanTerminalSizeQueue, 1)
+	sizes <- &TerminalSize{Width: 80, Height: 24}
+	stdinReader, stdinWriter := io.Pipe()
+	go func() {
+		_, _ = stdinWriter.Write([]byte("a"))
+	}()
+	err := e.StreamWithContext(context.Background(), StreamOptions{Stdin: stdinReader, Stdout: io.Discard, Tty: true, TerminalSizeQueue: sizes})
+	require.NoError(t, err)
+
+	// The goroutines which read stdin and the terminal size end with the
+	// next input instead of waiting for another stream.
+	sizes <- &TerminalSize{Width: 100, Height: 40}
+	_, err = stdinWriter.Write([]byte("b"))
+	require.NoError(t, err)
+}
+
+func TestReconnectingExecutorGivesUp(t *testing.T) {
+	fake := &fakeExecutor{errs: []error{connectionReset, connectionReset, connectionReset, connectionReset}}
+	e := newReconnectingExecutor(fake, true, ReconnectOptions{Backoff: testBackoff()})
+
+	err := e.StreamWithContext(context.Background(), StreamOptions{})
+	require.ErrorIs(t, err, syscall.ECONNRESET)
+	assert.Len(t, fake.sessions, 4)
+}
+
+func TestReconnectingExecutorDoesNotResume(t *testing.T) {
+	exitErr := exec.CodeExitError{Err: errors.New("command terminated with non-zero exit code"), Code: 1}
+	tests := map[string]struct {
+		resumable bool
+		err       error
+		check     func(t *testing.T, err error)
+	}{
+		"exec": {
+			err: connectionReset,
+			check: func(t *testing.T, err error) {
+				var notResumable *NotResumableError
+				require.ErrorAs(t, err, &notResumable)
+				assert.ErrorIs(t, err, syscall.ECONNRESET)
+			},
+		},
+		"remote command exited": {
+			resumable: true,
+			err:       exitErr,
+			check: func(t *testing.T, err error) {
+				assert.Equal(t, exitErr, err)
+			},
+		},
+		"stream ended": {
+			resumable: true,
+			err:       fmt.Errorf("error reading from error stream: %w", io.EOF),
+			check: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, io.EOF)
+			},
+		},
+		"websocket closed normally": {
+			resumable: true,
+			err:       &gwebsocket.CloseError{Code: gwebsocket.CloseNormalClosure},
+			check: func(t *testing.T, err error) {
+				var closeErr *gwebsocket.CloseError
+				assert.ErrorAs(t, err, &closeErr)
+			},
+		},
+		"canceled": {
+			resumable: true,
+			err:       context.Canceled,
+			check: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, context.Canceled)
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := &fakeExecutor{errs: []error{test.err}}
+			e := newReconnectingExecutor(fake, test.resumable, ReconnectOptions{Backoff: testBackoff()})
+			err := e.StreamWithContext(context.Background(), StreamOptions{})
+			test.check(t, err)
+			assert.Len(t, fake.sessions, 1)
+		})
+	}
+}
+
+func TestIsAttachURL(t *testing.T) {
+	for url, expected := range map[string]bool{
+		"https://host/api/v1/namespaces/ns/pods/foo/attach?stdin=true": true,
+		"https://host/api/v1/namespaces/ns/pods/foo/exec?command=sh":   false,
+	} {
+		attach, err := isAttachURL(url)
+		require.NoError(t, err)
+		assert.Equal(t, expected, attach, url)
+	}
+}
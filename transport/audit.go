@@ -0,0 +1,401 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+const (
+	defaultAuditMaxBodyBytes = 16 * 1024
+
+	// RedactedAuditBody replaces request bodies which must not be recorded.
+	RedactedAuditBody = "--- REDACTED ---"
+)
+
+// AuditConfig configures a client-side audit log, which records an
+// AuditEvent for every request of a transport.
+type AuditConfig struct {
+	// Sink receives the audit events. The audit log is disabled if it is nil.
+	Sink AuditSink
+	// IncludeRequestBody records the JSON and YAML bodies of requests,
+	// after redacting them with RedactBody.
+	IncludeRequestBody bool
+	// MaxBodyBytes is the maximum length of a recorded body, longer bodies are
+	// truncated. If zero, 16KiB is used.
+	MaxBodyBytes int
+	// RedactBody returns the body to record for a request. If nil,
+	// DefaultAuditBodyRedactor is used.
+	RedactBody func(event *AuditEvent, body []byte) []byte
+}
+
+// AuditEvent describes a request of a client and its outcome.
+type AuditEvent struct {
+	// Timestamp is when the request was sent.
+	Timestamp time.Time `json:"timestamp"`
+	// Verb is the Kubernetes verb of the request, like get, list, watch,
+	// create, update, patch, delete and deletecollection, or the lower case
+	// HTTP method for requests outside of the resource paths.
+	Verb   string `json:"verb"`
+	Method string `json:"method"`
+	URL    string `json:"url"`
+
+	// The resource of the request. They are empty for requests outside of
+	// the resource paths. The group of the legacy core API is empty.
+	APIGroup    string `json:"apiGroup,omitempty"`
+	APIVersion  string `json:"apiVersion,omitempty"`
+	Resource    string `json:"resource,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+
+	// User is the user name of basic authentication. The identity behind
+	// other credentials is only known to the server.
+	User string `json:"user,omitempty"`
+	// Impersonate is the identity the request impersonates, if any.
+	Impersonate *AuditImpersonation `json:"impersonate,omitempty"`
+	UserAgent   string              `json:"userAgent,omitempty"`
+
+	// ResponseCode is the HTTP status of the response, zero if the request failed.
+	ResponseCode int `json:"responseCode,omitempty"`
+	// Error is the error of a failed request.
+	Error string `json:"error,omitempty"`
+	// Latency is the time until the response headers arrived.
+	Latency time.Duration `json:"latency"`
+	// RequestBody is the redacted body of the request, if
+	// AuditConfig.IncludeRequestBody is set.
+	RequestBody string `json:"requestBody,omitempty"`
+}
+
+// AuditImpersonation is the identity a request impersonates.
+type AuditImpersonation struct {
+	User   string              `json:"user,omitempty"`
+	UID    string              `json:"uid,omitempty"`
+	Groups []string            `json:"groups,omitempty"`
+	Extra  map[string][]string `json:"extra,omitempty"`
+}
+
+// AuditSink is the backend of a client-side audit log.
+type AuditSink interface {
+	// Audit records an event. Errors are logged, they do not fail the request.
+	Audit(ctx context.Context, event *AuditEvent) error
+}
+
+// AuditSinkFunc is an AuditSink which calls a function.
+type AuditSinkFunc func(ctx context.Context, event *AuditEvent) error
+
+// Audit calls f.
+func (f AuditSinkFunc) Audit(ctx context.Context, event *AuditEvent) error {
+	return f(ctx, event)
+}
+
+// JSONLinesAuditSink writes audit events as JSON, one per line.
+type JSONLinesAuditSink struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+var _ AuditSink = &JSONLinesAuditSink{}
+
+// NewJSONLinesAuditSink returns an AuditSink which writes to w.
+func NewJSONLinesAuditSink(w io.Writer) *JSONLinesAuditSink {
+	return &JSONLinesAuditSink{w: w}
+}
+
+// OpenAuditLogFile returns an AuditSink which appends to a file. The file is
+// created with mode 0600 if it does not exist. The caller must close the sink.
+func OpenAuditLogFile(path string) (*JSONLinesAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return NewJSONLinesAuditSink(f), nil
+}
+
+// Audit writes an event as a single line.
+func (s *JSONLinesAuditSink) Audit(ctx context.Context, event *AuditEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, err = s.w.Write(line)
+	return err
+}
+
+// Close closes the underlying writer, if it is an io.Closer.
+func (s *JSONLinesAuditSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if closer, ok := s.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// auditRedactedResource is a resource whose request bodies carry credentials.
+// An empty subresource matches the resource and all of its subresources.
+type auditRedactedResource struct {
+	group, resource, subresource string
+}
+
+// auditRedactedResources are the resources redacted by DefaultAuditBodyRedactor.
+var auditRedactedResources = []auditRedactedResource{
+	{resource: "secrets"},
+	{resource: "serviceaccounts", subresource: "token"},
+	{group: "authentication.k8s.io", resource: "tokenreviews"},
+}
+
+// DefaultAuditBodyRedactor redacts the bodies of requests for secrets, service
+// account tokens and token reviews, and records all other bodies as they are.
+func DefaultAuditBodyRedactor(event *AuditEvent, body []byte) []byte {
+	for _, r := range auditRedactedResources {
+		if event.APIGroup == r.group && event.Resource == r.resource &&
+			(r.subresource == "" || event.Subresource == r.subresource) {
+			return []byte(RedactedAuditBody)
+		}
+	}
+	return body
+}
+
+type auditRoundTripper struct {
+	config AuditConfig
+	clock  clock.PassiveClock
+	rt     http.RoundTripper
+}
+
+var _ utilnet.RoundTripperWrapper = &auditRoundTripper{}
+
+// NewAuditRoundTripper records an AuditEvent for every request of rt.
+func NewAuditRoundTripper(config AuditConfig, rt http.RoundTripper) http.RoundTripper {
+	return newAuditRoundTripper(config, clock.RealClock{}, rt)
+}
+
+func newAuditRoundTripper(config AuditConfig, clock clock.PassiveClock, rt http.RoundTripper) *auditRoundTripper {
+	if config.MaxBodyBytes == 0 {
+		config.MaxBodyBytes = defaultAuditMaxBodyBytes
+	}
+	if config.RedactBody == nil {
+		config.RedactBody = DefaultAuditBodyRedactor
+	}
+	return &auditRoundTripper{config: config, clock: clock, rt: rt}
+}
+
+func (rt *auditRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	event := newAuditEvent(req)
+	if rt.config.IncludeRequestBody {
+		body, bodyReq, err := rt.requestBody(req, event)
+		if err != nil {
+			return nil, err
+		}
+		event.RequestBody = body
+		req = bodyReq
+	}
+
+	start := rt.clock.Now()
+	event.Timestamp = start
+	resp, err := rt.rt.RoundTrip(req)
+	event.Latency = rt.clock.Since(start)
+	if err != nil {
+		event.Error = err.Error()
+	} else {
+		event.ResponseCode = resp.StatusCode
+	}
+
+	if auditErr := rt.config.Sink.Audit(req.Context(), event); auditErr != nil {
+		klog.FromContext(req.Context()).Error(auditErr, "Failed to record client audit event", "verb", event.Verb, "url", event.URL)
+	}
+	return resp, err
+}
+
+// requestBody returns the redacted body of a request, and the request to send
+// in its place if the body could only be read once.
+func (rt *auditRoundTripper) requestBody(req *http.Request, event *AuditEvent) (string, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody || !isTextContentType(req.Header.Get("Content-Type")) {
+		return "", req, nil
+	}
+	var body []byte
+	var err error
+	if req.GetBody != nil {
+		var bodyCopy io.ReadCloser
+		bodyCopy, err = req.GetBody()
+		if err != nil {
+			return "", nil, err
+		}
+		body, err = io.ReadAll(bodyCopy)
+		bodyCopy.Close()
+	} else {
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		req = utilnet.CloneRequest(req)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	body = rt.config.RedactBody(event, body)
+	if len(body) > rt.config.MaxBodyBytes {
+		body = append(body[:rt.config.MaxBodyBytes:rt.config.MaxBodyBytes], "..."...)
+	}
+	return string(body), req, nil
+}
+
+// isTextContentType returns true for the JSON and YAML content types of
+// requests and patches.
+func isTextContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(mediaType, "json") || strings.HasSuffix(mediaType, "yaml")
+}
+
+func (rt *auditRoundTripper) CancelRequest(req *http.Request) {
+	tryCancelRequest(rt.WrappedRoundTripper(), req)
+}
+
+func (rt *auditRoundTripper) WrappedRoundTripper() http.RoundTripper { return rt.rt }
+
+// newAuditEvent describes a request like the API server would, see
+// k8s.io/apiserver/pkg/endpoints/request.RequestInfoFactory.
+func newAuditEvent(req *http.Request) *AuditEvent {
+	event := &AuditEvent{
+		Verb:      strings.ToLower(req.Method),
+		Method:    req.Method,
+		URL:       req.URL.String(),
+		UserAgent: req.Header.Get("User-Agent"),
+	}
+	if user, _, ok := req.BasicAuth(); ok {
+		event.User = user
+	}
+	event.Impersonate = impersonationFromHeaders(req.Header)
+
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	var parts []string
+	for i, segment := range segments {
+		if segment == "api" && i+1 < len(segments) {
+			event.APIVersion = segments[i+1]
+			parts = segments[i+2:]
+			break
+		}
+		if segment == "apis" && i+2 < len(segments) {
+			event.APIGroup = segments[i+1]
+			event.APIVersion = segments[i+2]
+			parts = segments[i+3:]
+			break
+		}
+	}
+	if len(parts) == 0 {
+		return event
+	}
+
+	watch := false
+	if parts[0] == "watch" {
+		watch = true
+		parts = parts[1:]
+	}
+	if len(parts) > 1 && parts[0] == "namespaces" {
+		event.Namespace = parts[1]
+		// Namespaces have subresources of their own.
+		if len(parts) > 2 && parts[2] != "status" && parts[2] != "finalize" {
+			parts = parts[2:]
+		}
+	}
+	if len(parts) > 0 {
+		event.Resource = parts[0]
+	}
+	if len(parts) > 1 {
+		event.Name = parts[1]
+	}
+	if len(parts) > 2 {
+		event.Subresource = parts[2]
+	}
+	if event.Resource == "namespaces" {
+		event.Namespace = ""
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		switch {
+		case watch || isWatchQuery(req.URL.Query()):
+			event.Verb = "watch"
+		case event.Name == "":
+			event.Verb = "list"
+		default:
+			event.Verb = "get"
+		}
+	case http.MethodPost:
+		event.Verb = "create"
+	case http.MethodPut:
+		event.Verb = "update"
+	case http.MethodPatch:
+		event.Verb = "patch"
+	case http.MethodDelete:
+		if event.Name == "" {
+			event.Verb = "deletecollection"
+		} else {
+			event.Verb = "delete"
+		}
+	}
+	return event
+}
+
+func isWatchQuery(query url.Values) bool {
+	watch := strings.ToLower(query.Get("watch"))
+	return watch == "true" || watch == "1"
+}
+
+func impersonationFromHeaders(header http.Header) *AuditImpersonation {
+	impersonate := AuditImpersonation{
+		User:   header.Get(ImpersonateUserHeader),
+		UID:    header.Get(ImpersonateUIDHeader),
+		Groups: header.Values(ImpersonateGroupHeader),
+	}
+	for key, values := range header {
+		if !strings.HasPrefix(key, ImpersonateUserExtraHeaderPrefix) {
+			continue
+		}
+		extraKey := strings.TrimPrefix(key, ImpersonateUserExtraHeaderPrefix)
+		if unescaped, err := url.PathUnescape(extraKey); err == nil {
+			extraKey = unescaped
+		}
+		if impersonate.Extra == nil {
+			impersonate.Extra = map[string][]string{}
+		}
+		impersonate.Extra[strings.ToLower(extraKey)] = values
+	}
+	if len(impersonate.User) == 0 && len(impersonate.UID) == 0 && len(impersonate.Groups) == 0 && len(impersonate.Extra) == 0 {
+		return nil
+	}
+	return &impersonate
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestNewAuditEvent(t *testing.T) {
+	testCases := []struct {
+		method   string
+		path     string
+		expected AuditEvent
+	}{
+		{
+			method:   "GET",
+			path:     "/api/v1/namespaces/default/pods/foo",
+			expected: AuditEvent{Verb: "get", APIVersion: "v1", Resource: "pods", Namespace: "default", Name: "foo"},
+		},
+		{
+			method:   "GET",
+			path:     "/api/v1/namespaces/default/pods?labelSelector=app",
+			expected: AuditEvent{Verb: "list", APIVersion: "v1", Resource: "pods", Namespace: "default"},
+		},
+		{
+			method:   "GET",
+			path:     "/apis/apps/v1/deployments?watch=true",
+			expected: AuditEvent{Verb: "watch", APIGroup: "apps", APIVersion: "v1", Resource: "deployments"},
+		},
+		{
+			method:   "GET",
+			path:     "/api/v1/watch/namespaces/default/pods/foo",
+			expected: AuditEvent{Verb: "watch", APIVersion: "v1", Resource: "pods", Namespace: "default", Name: "foo"},
+		},
+		{
+			method:   "POST",
+			path:     "/apis/apps/v1/namespaces/default/deployments",
+			expected: AuditEvent{Verb: "create", APIGroup: "apps", APIVersion: "v1", Resource: "deployments", Namespace: "default"},
+		},
+		{
+			method:   "PUT",
+			path:     "/apis/apps/v1/namespaces/default/deployments/foo/scale",
+			expected: AuditEvent{Verb: "update", APIGroup: "apps", APIVersion: "v1", Resource: "deployments", Subresource: "scale", Namespace: "default", Name: "foo"},
+		},
+		{
+			method:   "PATCH",
+			path:     "/api/v1/nodes/foo/status",
+			expected: AuditEvent{Verb: "patch", APIVersion: "v1", Resource: "nodes", Subresource: "status", Name: "foo"},
+		},
+		{
+			method:   "DELETE",
+			path:     "/api/v1/namespaces/default/pods/foo",
+			expected: AuditEvent{Verb: "delete", APIVersion: "v1", Resource: "pods", Namespace: "default", Name: "foo"},
+		},
+		{
+			method:   "DELETE",
+			path:     "/api/v1/namespaces/default/pods",
+			expected: AuditEvent{Verb: "deletecollection", APIVersion: "v1", Resource: "pods", Namespace: "default"},
+		},
+		{
+			method:   "GET",
+			path:     "/api/v1/namespaces/default",
+			expected: AuditEvent{Verb: "get", APIVersion: "v1", Resource: "namespaces", Name: "default"},
+		},
+		{
+			method:   "PUT",
+			path:     "/api/v1/namespaces/default/finalize",
+			expected: AuditEvent{Verb: "update", APIVersion: "v1", Resource: "namespaces", Subresource: "finalize", Name: "default"},
+		},
+		{
+			method:   "POST",
+			path:     "/prefix/api/v1/namespaces/default/serviceaccounts/foo/token",
+			expected: AuditEvent{Verb: "create", APIVersion: "v1", Resource: "serviceaccounts", Subresource: "token", Namespace: "default", Name: "foo"},
+		},
+		{
+			method:   "GET",
+			path:     "/apis/apps/v1",
+			expected: AuditEvent{Verb: "get", APIGroup: "apps", APIVersion: "v1"},
+		},
+		{
+			method:   "GET",
+			path:     "/version",
+			expected: AuditEvent{Verb: "get"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, "https://127.0.0.1"+tc.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.expected.Method = tc.method
+			tc.expected.URL = req.URL.String()
+			if event := newAuditEvent(req); !reflect.DeepEqual(*event, tc.expected) {
+				t.Errorf("expected\n%#v\ngot\n%#v", tc.expected, *event)
+			}
+		})
+	}
+}
+
+func TestAuditRoundTripper(t *testing.T) {
+	var events []*AuditEvent
+	sink := AuditSinkFunc(func(ctx context.Context, event *AuditEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	base := &testRoundTripper{Response: &http.Response{StatusCode: http.StatusCreated}}
+	rt, err := HTTPWrappersForConfig(&Config{
+		UserAgent: "test-agent",
+		Username:  "user",
+		Password:  "secret",
+		Impersonate: ImpersonationConfig{
+			UserName: "alice",
+			Groups:   []string{"devs"},
+			Extra:    map[string][]string{"reason": {"debugging"}},
+		},
+		Audit: AuditConfig{Sink: sink, IncludeRequestBody: true},
+	}, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"kind":"ConfigMap","apiVersion":"v1"}`
+	req, err := http.NewRequest("POST", "https://127.0.0.1/api/v1/namespaces/default/configmaps", io.NopCloser(strings.NewReader(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected one event, got %d", len(events))
+	}
+	event := events[0]
+	if event.User != "user" || event.UserAgent != "test-agent" || event.ResponseCode != http.StatusCreated {
+		t.Errorf("unexpected event %#v", event)
+	}
+	expectedImpersonation := &AuditImpersonation{
+		User:   "alice",
+		Groups: []string{"devs"},
+		Extra:  map[string][]string{"reason": {"debugging"}},
+	}
+	if !reflect.DeepEqual(event.Impersonate, expectedImpersonation) {
+		t.Errorf("expected impersonation %#v, got %#v", expectedImpersonation, event.Impersonate)
+	}
+	if event.RequestBody != body {
+		t.Errorf("expected the request body %q, got %q", body, event.RequestBody)
+	}
+	// The body is still sent.
+	sent, err := io.ReadAll(base.Request.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(sent) != body {
+		t.Errorf("expected the body %q to be sent, got %q", body, sent)
+	}
+
+	// Failed requests are recorded too.
+	base.Response, base.Err = nil, errors.New("connection refused")
+	req, err = http.NewRequest("GET", "https://127.0.0.1/api/v1/namespaces/default/configmaps/foo", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rt.RoundTrip(req); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected two events, got %d", len(events))
+	}
+	if event := events[1]; event.Verb != "get" || event.ResponseCode != 0 || event.Error != "connection refused" {
+		t.Errorf("unexpected event %#v", event)
+	}
+}
+
+func TestAuditRoundTripperRequestBody(t *testing.T) {
+	var event *AuditEvent
+	sink := AuditSinkFunc(func(ctx context.Context, e *AuditEvent) error {
+		event = e
+		return errors.New("sink errors don't fail requests")
+	})
+	testCases := []struct {
+		name        string
+		config      AuditConfig
+		path        string
+		contentType string
+		body        string
+		expected    string
+	}{
+		{
+			name:        "secret",
+			path:        "/api/v1/namespaces/default/secrets",
+			contentType: "application/json",
+			body:        `{"data":{"password":"c2VjcmV0"}}`,
+			expected:    RedactedAuditBody,
+		},
+		{
+			name:        "token request",
+			path:        "/api/v1/namespaces/default/serviceaccounts/foo/token",
+			contentType: "application/json",
+			body:        `{"spec":{}}`,
+			expected:    RedactedAuditBody,
+		},
+		{
+			name:        "token review",
+			path:        "/apis/authentication.k8s.io/v1/tokenreviews",
+			contentType: "application/json",
+			body:        `{"spec":{"token":"abc"}}`,
+			expected:    RedactedAuditBody,
+		},
+		{
+			name:        "secrets of another group",
+			path:        "/apis/example.com/v1/namespaces/default/secrets",
+			contentType: "application/json",
+			body:        `{"spec":{}}`,
+			expected:    `{"spec":{}}`,
+		},
+		{
+			name:        "token subresource of another resource",
+			path:        "/apis/example.com/v1/namespaces/default/widgets/foo/token",
+			contentType: "application/json",
+			body:        `{"spec":{}}`,
+			expected:    `{"spec":{}}`,
+		},
+		{
+			name:        "patch",
+			path:        "/api/v1/namespaces/default/configmaps/foo",
+			contentType: "application/merge-patch+json",
+			body:        `{"data":{"foo":"bar"}}`,
+			expected:    `{"data":{"foo":"bar"}}`,
+		},
+		{
+			name:        "protobuf",
+			path:        "/api/v1/namespaces/default/configmaps",
+			contentType: "application/vnd.kubernetes.protobuf",
+			body:        "k8s\x00",
+		},
+		{
+			name:        "truncated",
+			config:      AuditConfig{MaxBodyBytes: 4},
+			path:        "/api/v1/namespaces/default/configmaps",
+			contentType: "application/yaml",
+			body:        "kind: ConfigMap",
+			expected:    "kind...",
+		},
+		{
+			name: "custom redactor",
+			config: AuditConfig{RedactBody: func(event *AuditEvent, body []byte) []byte {
+				return bytes.ToUpper(body)
+			}},
+			path:        "/api/v1/namespaces/default/secrets",
+			contentType: "application/json",
+			body:        `{"kind":"Secret"}`,
+			expected:    `{"KIND":"SECRET"}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.config.Sink = sink
+			tc.config.IncludeRequestBody = true
+			base := &testRoundTripper{Response: &http.Response{StatusCode: http.StatusOK}}
+			rt := NewAuditRoundTripper(tc.config, base)
+			req, err := http.NewRequest("POST", "https://127.0.0.1"+tc.path, strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", tc.contentType)
+			if _, err := rt.RoundTrip(req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if event.RequestBody != tc.expected {
+				t.Errorf("expected the request body %q, got %q", tc.expected, event.RequestBody)
+			}
+			sent, err := io.ReadAll(base.Request.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(sent) != tc.body {
+				t.Errorf("expected the body %q to be sent, got %q", tc.body, sent)
+			}
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestAuditRoundTripperLatency(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	var event *AuditEvent
+	rt := newAuditRoundTripper(AuditConfig{Sink: AuditSinkFunc(func(ctx context.Context, e *AuditEvent) error {
+		event = e
+		return nil
+	})}, clock, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		clock.Step(time.Second)
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}))
+	start := clock.Now()
+	req, err := http.NewRequest("GET", "https://127.0.0.1/version", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if !event.Timestamp.Equal(start) || event.Latency != time.Second {
+		t.Errorf("expected the request at %v to take a second, got %v at %v", start, event.Latency, event.Timestamp)
+	}
+}
+
+func TestJSONLinesAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := OpenAuditLogFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"foo", "bar"} {
+		if err := sink.Audit(context.Background(), &AuditEvent{Verb: "get", Resource: "pods", Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two lines, got %q", data)
+	}
+	for i, name := range []string{"foo", "bar"} {
+		var event AuditEvent
+		if err := json.Unmarshal([]byte(lines[i]), &event); err != nil {
+			t.Fatalf("line %d: %v", i, err)
+		}
+		if event.Verb != "get" || event.Resource != "pods" || event.Name != name {
+			t.Errorf("line %d: unexpected event %#v", i, event)
+		}
+	}
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("expected mode 0600 for the audit log, got %v", info.Mode().Perm())
+		}
+	}
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/tools/metrics"
+	"k8s.io/utils/clock"
+)
+
+const (
+	defaultCircuitBreakerOpenDuration = 30 * time.Second
+
+	circuitClosed   = "closed"
+	circuitOpen     = "open"
+	circuitHalfOpen = "half-open"
+)
+
+// CircuitBreakerConfig configures a circuit breaker which fails requests fast
+// while a server is unavailable. The circuit breaker keeps a separate state
+// per host and API group version, so that an unavailable aggregated API does
+// not affect requests for other APIs.
+//
+// After FailureThreshold consecutive failures the circuit opens and requests
+// fail with a *CircuitOpenError. Once OpenDuration has passed, the circuit is
+// half-open and up to HalfOpenMaxRequests probe requests are let through. The
+// circuit closes once a probe succeeds and opens again if one fails.
+//
+// Transport errors and responses with status 502, 503 and 504 count as failures.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures which open the
+	// circuit. Zero disables the circuit breaker.
+	FailureThreshold int
+	// OpenDuration is the time the circuit stays open before probe requests
+	// are let through. If zero, 30s is used.
+	OpenDuration time.Duration
+	// HalfOpenMaxRequests is the maximum number of concurrent probe requests
+	// while the circuit is half-open. If zero, one probe request is sent at a time.
+	HalfOpenMaxRequests int
+}
+
+// CircuitOpenError is returned for requests which are rejected because the
+// circuit of their host and group version is open.
+type CircuitOpenError struct {
+	// Host is the host of the rejected request.
+	Host string
+	// GroupVersion is the API group version of the rejected request, empty
+	// for requests outside of the API paths. The legacy core group version
+	// is "v1".
+	GroupVersion string
+}
+
+func (e *CircuitOpenError) Error() string {
+	if e.GroupVersion == "" {
+		return fmt.Sprintf("circuit breaker is open for host %s", e.Host)
+	}
+	return fmt.Sprintf("circuit breaker is open for %s on host %s", e.GroupVersion, e.Host)
+}
+
+// IsCircuitOpenError returns true if err was caused by an open circuit breaker.
+func IsCircuitOpenError(err error) bool {
+	var circuitOpenErr *CircuitOpenError
+	return errors.As(err, &circuitOpenErr)
+}
+
+type circuitKey struct {
+	host         string
+	groupVersion string
+}
+
+type circuit struct {
+	state string
+	// failures is the number of consecutive failures while closed.
+	failures int
+	// openedAt is the time the circuit opened last.
+	openedAt time.Time
+	// probes is the number of probe requests in flight while half-open.
+	probes int
+}
+
+type circuitBreakerRoundTripper struct {
+	config CircuitBreakerConfig
+	clock  clock.PassiveClock
+	rt     http.RoundTripper
+
+	lock     sync.Mutex
+	circuits map[circuitKey]*circuit
+}
+
+var _ utilnet.RoundTripperWrapper = &circuitBreakerRoundTripper{}
+
+// NewCircuitBreakerRoundTripper wraps rt with a circuit breaker.
+func NewCircuitBreakerRoundTripper(config CircuitBreakerConfig, rt http.RoundTripper) http.RoundTripper {
+	return newCircuitBreakerRoundTripper(config, clock.RealClock{}, rt)
+}
+
+func newCircuitBreakerRoundTripper(config CircuitBreakerConfig, clock clock.PassiveClock, rt http.RoundTripper) *circuitBreakerRoundTripper {
+	if config.OpenDuration == 0 {
+		config.OpenDuration = defaultCircuitBreakerOpenDuration
+	}
+	if config.HalfOpenMaxRequests == 0 {
+		config.HalfOpenMaxRequests = 1
+	}
+	return &circuitBreakerRoundTripper{
+		config:   config,
+		clock:    clock,
+		rt:       rt,
+		circuits: map[circuitKey]*circuit{},
+	}
+}
+
+func (rt *circuitBreakerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := circuitKey{host: req.URL.Host, groupVersion: groupVersionFromPath(req.URL.Path)}
+	probe, ok := rt.allow(key)
+	if !ok {
+		metrics.CircuitBreakerRejections.Increment(key.host, key.groupVersion)
+		return nil, &CircuitOpenError{Host: key.host, GroupVersion: key.groupVersion}
+	}
+
+	resp, err := rt.rt.RoundTrip(req)
+	switch {
+	case err != nil && errors.Is(req.Context().Err(), context.Canceled):
+		// The caller gave up, which says nothing about the server.
+		rt.release(key, probe)
+	case err != nil:
+		rt.recordFailure(key, probe)
+	case resp.StatusCode == http.StatusBadGateway ||
+		resp.StatusCode == http.StatusServiceUnavailable ||
+		resp.StatusCode == http.StatusGatewayTimeout:
+		rt.recordFailure(key, probe)
+	default:
+		rt.recordSuccess(key, probe)
+	}
+	return resp, err
+}
+
+// allow returns whether a request may be sent and whether it is a probe of
+// a half-open circuit.
+func (rt *circuitBreakerRoundTripper) allow(key circuitKey) (probe bool, ok bool) {
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+
+	c, exists := rt.circuits[key]
+	if !exists {
+		c = &circuit{state: circuitClosed}
+		rt.circuits[key] = c
+	}
+	switch c.state {
+	case circuitOpen:
+		if rt.clock.Since(c.openedAt) < rt.config.OpenDuration {
+			return false, false
+		}
+		rt.setStateLocked(key, c, circuitHalfOpen)
+		fallthrough
+	case circuitHalfOpen:
+		if c.probes >= rt.config.HalfOpenMaxRequests {
+			return false, false
+		}
+		c.probes++
+		return true, true
+	}
+	return false, true
+}
+
+func (rt *circuitBreakerRoundTripper) recordSuccess(key circuitKey, probe bool) {
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+
+	c := rt.circuits[key]
+	if probe {
+		c.probes--
+	}
+	c.failures = 0
+	if c.state != circuitClosed {
+		rt.setStateLocked(key, c, circuitClosed)
+	}
+}
+
+func (rt *circuitBreakerRoundTripper) recordFailure(key circuitKey, probe bool) {
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+
+	c := rt.circuits[key]
+	if probe {
+		c.probes--
+	}
+	switch c.state {
+	case circuitClosed:
+		c.failures++
+		if c.failures < rt.config.FailureThreshold {
+			return
+		}
+	case circuitOpen:
+		// A request which was sent before the circuit opened.
+		return
+	}
+	c.failures = 0
+	c.openedAt = rt.clock.Now()
+	rt.setStateLocked(key, c, circuitOpen)
+}
+
+func (rt *circuitBreakerRoundTripper) release(key circuitKey, probe bool) {
+	if !probe {
+		return
+	}
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+	rt.circuits[key].probes--
+}
+
+func (rt *circuitBreakerRoundTripper) setStateLocked(key circuitKey, c *circuit, state string) {
+	c.state = state
+	metrics.CircuitBreakerState.Set(key.host, key.groupVersion, state)
+}
+
+func (rt *circuitBreakerRoundTripper) CancelRequest(req *http.Request) {
+	tryCancelRequest(rt.WrappedRoundTripper(), req)
+}
+
+func (rt *circuitBreakerRoundTripper) WrappedRoundTripper() http.RoundTripper { return rt.rt }
+
+// groupVersionFromPath returns the API group version of a request path, "v1"
+// for the legacy core group and an empty string for paths outside of the API.
+func groupVersionFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		switch {
+		case segment == "api" && i+1 < len(segments):
+			return segments[i+1]
+		case segment == "apis" && i+2 < len(segments):
+			return segments[i+1] + "/" + segments[i+2]
+		}
+	}
+	return ""
+}
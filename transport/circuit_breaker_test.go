@@ -0,0 +1,226 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/metrics"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+type fakeCircuitBreakerStateMetric struct {
+	lock   sync.Mutex
+	states []string
+}
+
+func (m *fakeCircuitBreakerStateMetric) Set(host, groupVersion, state string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.states = append(m.states, groupVersion+" "+state)
+}
+
+func newCircuitBreakerTestRequest(t *testing.T, ctx context.Context, path string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com"+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestCircuitBreakerRoundTripper(t *testing.T) {
+	stateMetric := &fakeCircuitBreakerStateMetric{}
+	oldCircuitBreakerState := metrics.CircuitBreakerState
+	metrics.CircuitBreakerState = stateMetric
+	defer func() {
+		metrics.CircuitBreakerState = oldCircuitBreakerState
+	}()
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	base := &testRoundTripper{Response: &http.Response{StatusCode: http.StatusServiceUnavailable}}
+	rt := newCircuitBreakerRoundTripper(CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: 10 * time.Second}, fakeClock, base)
+	ctx := context.Background()
+	metricsPath := "/apis/metrics.k8s.io/v1beta1/pods"
+
+	roundTrip := func(path string) error {
+		base.Request = nil
+		_, err := rt.RoundTrip(newCircuitBreakerTestRequest(t, ctx, path))
+		return err
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := roundTrip(metricsPath); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	err := roundTrip(metricsPath)
+	var circuitOpenErr *CircuitOpenError
+	if !errors.As(err, &circuitOpenErr) || !IsCircuitOpenError(err) {
+		t.Fatalf("expected a CircuitOpenError, got %v", err)
+	}
+	if circuitOpenErr.Host != "example.com" || circuitOpenErr.GroupVersion != "metrics.k8s.io/v1beta1" {
+		t.Errorf("unexpected error details %+v", circuitOpenErr)
+	}
+	if base.Request != nil {
+		t.Errorf("expected the request to fail without being sent")
+	}
+
+	// Other group versions are not affected.
+	base.Response = &http.Response{StatusCode: http.StatusOK}
+	if err := roundTrip("/api/v1/pods"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// A failing probe opens the circuit again.
+	fakeClock.Step(10 * time.Second)
+	base.Response = &http.Response{StatusCode: http.StatusGatewayTimeout}
+	if err := roundTrip(metricsPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := roundTrip(metricsPath); !IsCircuitOpenError(err) {
+		t.Fatalf("expected a CircuitOpenError, got %v", err)
+	}
+
+	// A successful probe closes it.
+	fakeClock.Step(10 * time.Second)
+	base.Response = &http.Response{StatusCode: http.StatusOK}
+	for i := 0; i < 3; i++ {
+		if err := roundTrip(metricsPath); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	stateMetric.lock.Lock()
+	defer stateMetric.lock.Unlock()
+	expectedStates := []string{
+		"metrics.k8s.io/v1beta1 open",
+		"metrics.k8s.io/v1beta1 half-open",
+		"metrics.k8s.io/v1beta1 open",
+		"metrics.k8s.io/v1beta1 half-open",
+		"metrics.k8s.io/v1beta1 closed",
+	}
+	if len(stateMetric.states) != len(expectedStates) {
+		t.Fatalf("expected states %v, got %v", expectedStates, stateMetric.states)
+	}
+	for i := range expectedStates {
+		if expectedStates[i] != stateMetric.states[i] {
+			t.Fatalf("expected states %v, got %v", expectedStates, stateMetric.states)
+		}
+	}
+}
+
+type blockingRoundTripper struct {
+	started chan struct{}
+	unblock chan struct{}
+}
+
+func (rt *blockingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.started <- struct{}{}
+	<-rt.unblock
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func TestCircuitBreakerHalfOpenProbes(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	base := &blockingRoundTripper{started: make(chan struct{}, 1), unblock: make(chan struct{})}
+	rt := newCircuitBreakerRoundTripper(CircuitBreakerConfig{FailureThreshold: 1}, fakeClock, &testRoundTripper{Err: errors.New("connection refused")})
+	ctx := context.Background()
+
+	// Open the circuit.
+	if _, err := rt.RoundTrip(newCircuitBreakerTestRequest(t, ctx, "/api/v1/pods")); err == nil {
+		t.Fatalf("expected an error")
+	}
+	rt.rt = base
+	fakeClock.Step(defaultCircuitBreakerOpenDuration)
+
+	done := make(chan error)
+	go func() {
+		_, err := rt.RoundTrip(newCircuitBreakerTestRequest(t, ctx, "/api/v1/pods"))
+		done <- err
+	}()
+	<-base.started
+
+	// Only one probe is in flight at a time.
+	if _, err := rt.RoundTrip(newCircuitBreakerTestRequest(t, ctx, "/api/v1/nodes")); !IsCircuitOpenError(err) {
+		t.Errorf("expected a CircuitOpenError while a probe is in flight, got %v", err)
+	}
+	close(base.unblock)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	go func() { <-base.started }()
+	if _, err := rt.RoundTrip(newCircuitBreakerTestRequest(t, ctx, "/api/v1/nodes")); err != nil {
+		t.Errorf("expected the circuit to be closed, got %v", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresCanceledRequests(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	base := &testRoundTripper{Err: context.Canceled}
+	rt := NewCircuitBreakerRoundTripper(CircuitBreakerConfig{FailureThreshold: 1}, base)
+
+	for i := 0; i < 2; i++ {
+		if _, err := rt.RoundTrip(newCircuitBreakerTestRequest(t, ctx, "/api/v1/pods")); IsCircuitOpenError(err) {
+			t.Fatalf("expected canceled requests not to open the circuit")
+		}
+	}
+
+	base.Err = errors.New("connection refused")
+	if _, err := rt.RoundTrip(newCircuitBreakerTestRequest(t, context.Background(), "/api/v1/pods")); IsCircuitOpenError(err) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := rt.RoundTrip(newCircuitBreakerTestRequest(t, context.Background(), "/api/v1/pods")); !IsCircuitOpenError(err) {
+		t.Fatalf("expected a CircuitOpenError after a transport error, got %v", err)
+	}
+}
+
+func TestCircuitBreakerFromConfig(t *testing.T) {
+	base := &testRoundTripper{Response: &http.Response{StatusCode: http.StatusBadGateway}}
+	rt, err := HTTPWrappersForConfig(&Config{CircuitBreaker: CircuitBreakerConfig{FailureThreshold: 1}}, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rt.RoundTrip(newCircuitBreakerTestRequest(t, context.Background(), "/version")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := rt.RoundTrip(newCircuitBreakerTestRequest(t, context.Background(), "/version")); !IsCircuitOpenError(err) {
+		t.Fatalf("expected a CircuitOpenError, got %v", err)
+	}
+}
+
+func TestGroupVersionFromPath(t *testing.T) {
+	testCases := map[string]string{
+		"/api/v1/namespaces/default/pods":     "v1",
+		"/apis/apps/v1/deployments":           "apps/v1",
+		"/prefix/apis/metrics.k8s.io/v1beta1": "metrics.k8s.io/v1beta1",
+		"/apis/apps":                          "",
+		"/version":                            "",
+		"/":                                   "",
+	}
+	for path, expected := range testCases {
+		if actual := groupVersionFromPath(path); actual != expected {
+			t.Errorf("%s: expected %q, got %q", path, expected, actual)
+		}
+	}
+}
@@ -77,6 +77,14 @@ type Config struct {
 	//
 	// socks5 proxying does not currently support spdy streaming endpoints.
 	Proxy func(*http.Request) (*url.URL, error)
+
+	// CircuitBreaker configures a circuit breaker for the requests of this
+	// transport. It is disabled by default.
+	CircuitBreaker CircuitBreakerConfig
+
+	// Audit configures a client-side audit log of the requests of this
+	// transport. It is disabled by default.
+	Audit AuditConfig
 }
 
 // DialHolder is used to make the wrapped function comparable so that it can be used as a map key.
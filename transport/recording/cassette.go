@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package recording provides round trippers which record the HTTP
+// interactions of a client with an apiserver to a cassette and replay them
+// later without network access, for example in air-gapped integration tests.
+//
+// To record, wrap the transport of a rest.Config:
+//
+//	recorder := recording.NewRecorder()
+//	config.Wrap(recorder.Wrap)
+//	// ... use clients created from config ...
+//	err := recorder.Cassette().Save("testdata/cassette.json")
+//
+// To replay, load the cassette and wrap the transport the same way:
+//
+//	cassette, err := recording.LoadCassette("testdata/cassette.json")
+//	config.Wrap(recording.NewReplayer(cassette).Wrap)
+//
+// Response bodies are stored as they went over the wire, so JSON, protobuf
+// and CBOR responses as well as watch streams are replayed unchanged.
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// Cassette is a sequence of recorded HTTP interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response it received.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method string `json:"method"`
+	// URI is the path and query of the request. The host is not recorded,
+	// so that cassettes can be replayed against any server address.
+	URI    string      `json:"uri"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+	// StreamOpen is true if the client closed the response body before the
+	// server ended it, as it happens with watches. When replayed, reading the
+	// body blocks after Body until the request is canceled or the body closed.
+	StreamOpen bool `json:"streamOpen,omitempty"`
+}
+
+// LoadCassette reads a cassette from a file written by Cassette.Save.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cassette := &Cassette{}
+	if err := json.Unmarshal(data, cassette); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette %s: %w", path, err)
+	}
+	return cassette, nil
+}
+
+// Save writes the cassette to a file. The file is created with mode 0600,
+// because recorded bodies may still contain sensitive data.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
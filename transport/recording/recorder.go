@@ -0,0 +1,144 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recording
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+// redactedRequestHeaders and redactedResponseHeaders are headers which are not
+// recorded because they carry credentials or challenges for them.
+var (
+	redactedRequestHeaders  = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+	redactedResponseHeaders = []string{"Set-Cookie", "WWW-Authenticate", "Proxy-Authenticate"}
+)
+
+// Recorder records the interactions of the round trippers which it wraps.
+type Recorder struct {
+	lock         sync.Mutex
+	interactions []*Interaction
+}
+
+// NewRecorder returns a Recorder with an empty cassette.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Wrap returns a round tripper which sends requests with rt and records them.
+// It can be passed to rest.Config.Wrap.
+func (r *Recorder) Wrap(rt http.RoundTripper) http.RoundTripper {
+	return &recordingRoundTripper{recorder: r, rt: rt}
+}
+
+// Cassette returns the interactions recorded so far. Response bodies which
+// are still being read are included up to the data read so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	cassette := &Cassette{Interactions: make([]Interaction, 0, len(r.interactions))}
+	for _, interaction := range r.interactions {
+		i := *interaction
+		i.Request.Body = slices.Clone(i.Request.Body)
+		i.Response.Body = slices.Clone(i.Response.Body)
+		cassette.Interactions = append(cassette.Interactions, i)
+	}
+	return cassette
+}
+
+type recordingRoundTripper struct {
+	recorder *Recorder
+	rt       http.RoundTripper
+}
+
+var _ utilnet.RoundTripperWrapper = &recordingRoundTripper{}
+
+func (rt *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := rt.rt.RoundTrip(req)
+	if err != nil {
+		// Transport errors are not recorded, replaying them could not
+		// reproduce the actual error anyway.
+		return resp, err
+	}
+
+	header := req.Header.Clone()
+	for _, name := range redactedRequestHeaders {
+		header.Del(name)
+	}
+	respHeader := resp.Header.Clone()
+	for _, name := range redactedResponseHeaders {
+		respHeader.Del(name)
+	}
+	interaction := &Interaction{
+		Request: Request{
+			Method: req.Method,
+			URI:    req.URL.RequestURI(),
+			Header: header,
+			Body:   body,
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     respHeader,
+			StreamOpen: true,
+		},
+	}
+	rt.recorder.lock.Lock()
+	rt.recorder.interactions = append(rt.recorder.interactions, interaction)
+	rt.recorder.lock.Unlock()
+
+	resp.Body = &recordingBody{ReadCloser: resp.Body, recorder: rt.recorder, interaction: interaction}
+	return resp, nil
+}
+
+func (rt *recordingRoundTripper) WrappedRoundTripper() http.RoundTripper { return rt.rt }
+
+// recordingBody records the data read from a response body.
+type recordingBody struct {
+	io.ReadCloser
+	recorder    *Recorder
+	interaction *Interaction
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.recorder.lock.Lock()
+	defer b.recorder.lock.Unlock()
+	b.interaction.Response.Body = append(b.interaction.Response.Body, p[:n]...)
+	if errors.Is(err, io.EOF) {
+		b.interaction.Response.StreamOpen = false
+	}
+	return n, err
+}
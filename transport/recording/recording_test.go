@@ -0,0 +1,200 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recording
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	goruntime "runtime"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+)
+
+var widgets = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+
+const widgetList = `{"apiVersion":"example.com/v1","kind":"WidgetList","metadata":{"resourceVersion":"10"},"items":[{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"a","namespace":"default","resourceVersion":"9"}}]}`
+
+// protobufBody is not valid UTF-8, like protobuf and CBOR encoded bodies.
+var protobufBody = []byte{0x6b, 0x38, 0x73, 0x00, 0x0a, 0xff, 0xfe, 0x01}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.URL.Path == "/binary":
+			w.Header().Set("Content-Type", "application/vnd.kubernetes.protobuf")
+			_, _ = w.Write(protobufBody)
+		case req.Method == http.MethodPost:
+			body, _ := io.ReadAll(req.Body)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(body)
+		case req.URL.Query().Get("watch") == "true":
+			w.Header().Set("Content-Type", "application/json")
+			for i, name := range []string{"b", "c"} {
+				fmt.Fprintf(w, `{"type":"ADDED","object":{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":%q,"namespace":"default","resourceVersion":"%d"}}}`+"\n", name, 11+i)
+			}
+			w.(http.Flusher).Flush()
+			<-req.Context().Done()
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Set-Cookie", "session=secret")
+			_, _ = w.Write([]byte(widgetList))
+		}
+	}))
+}
+
+// exercise runs the same calls against a recording and a replaying client.
+func exercise(t *testing.T, config *rest.Config, timeoutSeconds int64) []string {
+	t.Helper()
+	ctx := context.Background()
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []string
+
+	list, err := client.Resource(widgets).Namespace("default").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results = append(results, "list "+list.GetResourceVersion()+" "+list.Items[0].GetName())
+
+	w, err := client.Resource(widgets).Namespace("default").Watch(ctx, metav1.ListOptions{ResourceVersion: "10", TimeoutSeconds: ptr.To(timeoutSeconds)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		event := <-w.ResultChan()
+		if event.Type != watch.Added {
+			t.Fatalf("unexpected event %+v", event)
+		}
+		results = append(results, "watch "+event.Object.(*unstructured.Unstructured).GetName())
+	}
+	w.Stop()
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("example.com/v1")
+	obj.SetKind("Widget")
+	obj.SetName("d")
+	created, err := client.Resource(widgets).Namespace("default").Create(ctx, obj, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results = append(results, "create "+created.GetName())
+
+	restClient, err := rest.UnversionedRESTClientFor(&rest.Config{
+		Host:          config.Host,
+		WrapTransport: config.WrapTransport,
+		ContentConfig: rest.ContentConfig{NegotiatedSerializer: serializer.NewCodecFactory(runtime.NewScheme())},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := restClient.Get().AbsPath("/binary").DoRaw(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results = append(results, fmt.Sprintf("binary %x", body))
+	return results
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := newTestServer(t)
+	recorder := NewRecorder()
+	config := &rest.Config{Host: server.URL, BearerToken: "secret"}
+	config.Wrap(recorder.Wrap)
+	recorded := exercise(t, config, 100)
+	server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := recorder.Cassette().Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if goruntime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("expected mode 0600 for the cassette, got %v", info.Mode().Perm())
+		}
+	}
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, a := 4, len(cassette.Interactions); e != a {
+		t.Fatalf("expected %d interactions, got %d", e, a)
+	}
+	for _, interaction := range cassette.Interactions {
+		if interaction.Request.Header.Get("Authorization") != "" {
+			t.Errorf("expected credentials not to be recorded: %v", interaction.Request.Header)
+		}
+		if interaction.Response.Header.Get("Set-Cookie") != "" {
+			t.Errorf("expected cookies not to be recorded: %v", interaction.Response.Header)
+		}
+		if e, a := interaction.Request.URI == "/apis/example.com/v1/namespaces/default/widgets?resourceVersion=10&timeoutSeconds=100&watch=true", interaction.Response.StreamOpen; e != a {
+			t.Errorf("%s: expected the stream to be open: %v, got %v", interaction.Request.URI, e, a)
+		}
+	}
+
+	// The server is gone and the timeout differs, the replay still matches.
+	replayer := NewReplayer(cassette)
+	config = &rest.Config{Host: "http://127.0.0.1:1"}
+	config.Wrap(replayer.Wrap)
+	replayed := exercise(t, config, 200)
+
+	if !reflect.DeepEqual(recorded, replayed) {
+		t.Errorf("expected the replay to return %v, got %v", recorded, replayed)
+	}
+	if unplayed := replayer.Unplayed(); len(unplayed) != 0 {
+		t.Errorf("expected all interactions to be replayed, got %v", unplayed)
+	}
+}
+
+func TestReplayUnknownRequest(t *testing.T) {
+	replayer := NewReplayer(&Cassette{Interactions: []Interaction{{
+		Request:  Request{Method: http.MethodGet, URI: "/version"},
+		Response: Response{StatusCode: http.StatusOK, Body: []byte("{}")},
+	}}})
+	client := &http.Client{Transport: replayer}
+
+	resp, err := client.Get("http://localhost/version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	// Each interaction is replayed once.
+	if _, err := client.Get("http://localhost/version"); err == nil {
+		t.Errorf("expected an error for a request which was not recorded")
+	}
+}
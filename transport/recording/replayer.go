@@ -0,0 +1,166 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recording
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// DefaultIgnoredQueryParameters are the query parameters which are ignored
+// when requests are matched against the cassette. Clients like reflectors
+// choose them randomly.
+var DefaultIgnoredQueryParameters = []string{"timeoutSeconds"}
+
+// Replayer serves the responses of a cassette without network access.
+//
+// Each request is answered with the first interaction of the cassette which
+// has not been replayed yet and whose request has the same method, path,
+// query and body. Requests without such an interaction fail.
+type Replayer struct {
+	// IgnoredQueryParameters are not considered when matching requests.
+	// It defaults to DefaultIgnoredQueryParameters.
+	IgnoredQueryParameters []string
+
+	cassette *Cassette
+
+	lock     sync.Mutex
+	replayed []bool
+}
+
+// NewReplayer returns a Replayer for the cassette.
+func NewReplayer(cassette *Cassette) *Replayer {
+	return &Replayer{
+		IgnoredQueryParameters: DefaultIgnoredQueryParameters,
+		cassette:               cassette,
+		replayed:               make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// Wrap returns the Replayer itself, which does not send any requests. It can
+// be passed to rest.Config.Wrap.
+func (r *Replayer) Wrap(http.RoundTripper) http.RoundTripper {
+	return r
+}
+
+// Unplayed returns the interactions which have not been replayed yet.
+func (r *Replayer) Unplayed() []Interaction {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var unplayed []Interaction
+	for i, replayed := range r.replayed {
+		if !replayed {
+			unplayed = append(unplayed, r.cassette.Interactions[i])
+		}
+	}
+	return unplayed
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	uri := r.normalizeURI(req.URL.RequestURI())
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.replayed[i] ||
+			interaction.Request.Method != req.Method ||
+			r.normalizeURI(interaction.Request.URI) != uri ||
+			!bytes.Equal(interaction.Request.Body, body) {
+			continue
+		}
+		r.replayed[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          newReplayBody(req.Context(), interaction.Response),
+			ContentLength: -1,
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, req.URL.RequestURI())
+}
+
+// normalizeURI removes the ignored query parameters from a request URI.
+func (r *Replayer) normalizeURI(uri string) string {
+	u, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return uri
+	}
+	query := u.Query()
+	for _, param := range r.IgnoredQueryParameters {
+		query.Del(param)
+	}
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
+
+// replayBody returns the recorded body of a response and, if the stream was
+// still open when it was recorded, blocks afterwards until the request is
+// canceled or the body closed.
+type replayBody struct {
+	data   *bytes.Reader
+	open   bool
+	ctx    context.Context
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newReplayBody(ctx context.Context, resp Response) *replayBody {
+	return &replayBody{
+		data:   bytes.NewReader(resp.Body),
+		open:   resp.StreamOpen,
+		ctx:    ctx,
+		closed: make(chan struct{}),
+	}
+}
+
+func (b *replayBody) Read(p []byte) (int, error) {
+	if b.data.Len() > 0 || !b.open {
+		return b.data.Read(p)
+	}
+	select {
+	case <-b.ctx.Done():
+		return 0, b.ctx.Err()
+	case <-b.closed:
+		return 0, io.EOF
+	}
+}
+
+func (b *replayBody) Close() error {
+	b.once.Do(func() { close(b.closed) })
+	return nil
+}
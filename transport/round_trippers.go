@@ -39,12 +39,23 @@ import (
 // HTTP2 clients). Pure HTTP clients should use the RoundTripper returned from
 // New.
 func HTTPWrappersForConfig(config *Config, rt http.RoundTripper) (http.RoundTripper, error) {
+	// The circuit breaker wraps the underlying transport directly, so that
+	// only the responses of the server count.
+	if config.CircuitBreaker.FailureThreshold > 0 {
+		rt = NewCircuitBreakerRoundTripper(config.CircuitBreaker, rt)
+	}
 	if config.WrapTransport != nil {
 		rt = config.WrapTransport(rt)
 	}
 
 	rt = DebugWrappers(rt)
 
+	// The audit log sees the headers set by the wrappers below, like the
+	// impersonated identity.
+	if config.Audit.Sink != nil {
+		rt = NewAuditRoundTripper(config.Audit, rt)
+	}
+
 	// Set authentication wrappers
 	switch {
 	case config.HasBasicAuth() && config.HasTokenAuth():
@@ -0,0 +1,173 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applyplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+)
+
+// Ownership is the set of fields which a field manager owns.
+type Ownership struct {
+	// Manager is the name of the field manager.
+	Manager string
+	// Operation is Apply for fields which were applied and Update for
+	// fields which were written otherwise.
+	Operation metav1.ManagedFieldsOperationType
+	// Subresource is the subresource the fields were written to, if any.
+	Subresource string
+	// APIVersion is the version of the schema of the fields.
+	APIVersion string
+	// Time is when the fields were last changed, if known.
+	Time *metav1.Time
+	// Fields are the owned fields.
+	Fields *fieldpath.Set
+}
+
+// Ownerships returns the ownership of each entry of the managed fields of
+// obj, in the same order.
+func Ownerships(obj runtime.Object) ([]Ownership, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	var ownerships []Ownership
+	for _, entry := range accessor.GetManagedFields() {
+		fields, err := decodeFields(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the fields of manager %q: %w", entry.Manager, err)
+		}
+		ownerships = append(ownerships, Ownership{
+			Manager:     entry.Manager,
+			Operation:   entry.Operation,
+			Subresource: entry.Subresource,
+			APIVersion:  entry.APIVersion,
+			Time:        entry.Time,
+			Fields:      fields,
+		})
+	}
+	return ownerships, nil
+}
+
+// FieldsOwnedBy returns the fields of obj which the field manager owns,
+// regardless of the operation and subresource they were written with.
+func FieldsOwnedBy(obj runtime.Object, manager string) (*fieldpath.Set, error) {
+	ownerships, err := Ownerships(obj)
+	if err != nil {
+		return nil, err
+	}
+	fields := &fieldpath.Set{}
+	for _, ownership := range ownerships {
+		if ownership.Manager == manager {
+			fields = fields.Union(ownership.Fields)
+		}
+	}
+	return fields, nil
+}
+
+func decodeFields(entry metav1.ManagedFieldsEntry) (*fieldpath.Set, error) {
+	fields := &fieldpath.Set{}
+	if entry.FieldsV1 == nil {
+		return fields, nil
+	}
+	if err := fields.FromJSON(entry.FieldsV1.GetRawReader()); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// managerIdentifier identifies a managed fields entry like the apiserver
+// does: by all of its properties except fields and time, and for appliers
+// also except the API version.
+func managerIdentifier(entry metav1.ManagedFieldsEntry) (string, error) {
+	entry.FieldsType = ""
+	entry.FieldsV1 = nil
+	entry.Time = nil
+	if entry.Operation == metav1.ManagedFieldsOperationApply {
+		entry.APIVersion = ""
+	}
+	b, err := json.Marshal(&entry)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode manager identifier: %w", err)
+	}
+	return string(b), nil
+}
+
+// managerOf returns the entry identified by a managerIdentifier.
+func managerOf(identifier string) metav1.ManagedFieldsEntry {
+	var entry metav1.ManagedFieldsEntry
+	if err := json.Unmarshal([]byte(identifier), &entry); err != nil {
+		entry.Manager = identifier
+	}
+	return entry
+}
+
+// decodeManagedFields converts managed fields entries to the format of
+// structured-merge-diff. It also returns the times of the entries by
+// manager identifier.
+func decodeManagedFields(entries []metav1.ManagedFieldsEntry) (fieldpath.ManagedFields, map[string]*metav1.Time, error) {
+	managed := make(fieldpath.ManagedFields, len(entries))
+	times := make(map[string]*metav1.Time, len(entries))
+	for _, entry := range entries {
+		identifier, err := managerIdentifier(entry)
+		if err != nil {
+			return nil, nil, err
+		}
+		fields, err := decodeFields(entry)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode the fields of manager %q: %w", entry.Manager, err)
+		}
+		managed[identifier] = fieldpath.NewVersionedSet(fields, fieldpath.APIVersion(entry.APIVersion), entry.Operation == metav1.ManagedFieldsOperationApply)
+		times[identifier] = entry.Time
+	}
+	return managed, times, nil
+}
+
+// encodeManagedFields is the inverse of decodeManagedFields. The entries are
+// sorted by operation and manager.
+func encodeManagedFields(managed fieldpath.ManagedFields, times map[string]*metav1.Time) ([]metav1.ManagedFieldsEntry, error) {
+	var entries []metav1.ManagedFieldsEntry
+	for identifier, versionedSet := range managed {
+		fields, err := versionedSet.Set().ToJSON()
+		if err != nil {
+			return nil, err
+		}
+		entry := managerOf(identifier)
+		entry.APIVersion = string(versionedSet.APIVersion())
+		entry.Time = times[identifier]
+		entry.FieldsType = "FieldsV1"
+		entry.FieldsV1 = &metav1.FieldsV1{}
+		entry.FieldsV1.SetRawBytes(fields)
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Operation != entries[j].Operation {
+			return entries[i].Operation < entries[j].Operation
+		}
+		if entries[i].Manager != entries[j].Manager {
+			return entries[i].Manager < entries[j].Manager
+		}
+		return entries[i].Subresource < entries[j].Subresource
+	})
+	return entries, nil
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package applyplan computes locally what a server-side apply would do to an
+// object: the resulting object, which fields change, which field managers
+// own which fields afterwards and which fields conflict with other managers.
+//
+// The schemas of the types come from a managedfields.TypeConverter, which
+// for built-in types and CRDs of a cluster is created with
+// openapi.NewTypeConverter:
+//
+//	typeConverter, err := openapi.NewTypeConverter(clientset.Discovery().OpenAPIV3(), false)
+//	planner := applyplan.NewPlanner(typeConverter)
+//	plan, err := planner.Plan(liveDeployment, deploymentApplyConfiguration, "my-controller", applyplan.Options{})
+//
+// The plan doesn't include what the apiserver adds on top of merging, like
+// defaulting, admission and version conversion.
+package applyplan
+
+import (
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v6/merge"
+	"sigs.k8s.io/structured-merge-diff/v6/typed"
+)
+
+// ignoredFields are the fields which are never owned by field managers. Their
+// children, like the labels in metadata, may be owned.
+var ignoredFields = fieldpath.NewSet(
+	fieldpath.MakePathOrDie("apiVersion"),
+	fieldpath.MakePathOrDie("kind"),
+	fieldpath.MakePathOrDie("metadata"),
+	fieldpath.MakePathOrDie("metadata", "name"),
+	fieldpath.MakePathOrDie("metadata", "namespace"),
+	fieldpath.MakePathOrDie("metadata", "creationTimestamp"),
+	fieldpath.MakePathOrDie("metadata", "selfLink"),
+	fieldpath.MakePathOrDie("metadata", "uid"),
+	fieldpath.MakePathOrDie("metadata", "clusterName"),
+	fieldpath.MakePathOrDie("metadata", "generation"),
+	fieldpath.MakePathOrDie("metadata", "managedFields"),
+	fieldpath.MakePathOrDie("metadata", "resourceVersion"),
+)
+
+// Options configures a plan.
+type Options struct {
+	// Force takes over all fields which conflict with other managers, like
+	// metav1.ApplyOptions.Force.
+	Force bool
+
+	// ForceManagers are the field managers whose fields are taken over if
+	// they conflict. Conflicts with other managers still fail the plan.
+	ForceManagers sets.Set[string]
+}
+
+// Plan is the outcome of a server-side apply.
+type Plan struct {
+	// Object is the object after the apply, including its managed fields.
+	Object *unstructured.Unstructured
+
+	// Diff are the fields which the apply adds, modifies and removes.
+	Diff *typed.Comparison
+
+	// TakenOver are the fields which the apply takes over from other
+	// managers. The managers are identified by their name.
+	TakenOver merge.Conflicts
+
+	// Force is whether the apply must be sent with
+	// metav1.ApplyOptions.Force, because it takes over fields.
+	Force bool
+}
+
+// Planner plans server-side applies of the types of a TypeConverter.
+type Planner struct {
+	typeConverter managedfields.TypeConverter
+}
+
+// NewPlanner returns a Planner for the types of typeConverter.
+func NewPlanner(typeConverter managedfields.TypeConverter) *Planner {
+	return &Planner{typeConverter: typeConverter}
+}
+
+// Plan computes the result of applying applyConfiguration as the field
+// manager to live, the current state of the object on the server. live may
+// be nil if the object doesn't exist yet. applyConfiguration is a generated
+// apply configuration or an *unstructured.Unstructured and must be of the
+// same API version and kind as live.
+//
+// If the apply conflicts with other managers which it isn't allowed to take
+// over from by opts, the error is a merge.Conflicts with these conflicts.
+//
+// Managers of other API versions are assumed to own the same paths in the
+// version of the apply, which is true unless the versions differ in the
+// affected fields.
+func (p *Planner) Plan(live runtime.Object, applyConfiguration interface{}, manager string, opts Options) (*Plan, error) {
+	configContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(applyConfiguration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the apply configuration: %w", err)
+	}
+	config := &unstructured.Unstructured{Object: configContent}
+	gvk := config.GroupVersionKind()
+	if gvk.Empty() {
+		return nil, errors.New("apply configuration must have apiVersion and kind")
+	}
+
+	liveObj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if live != nil {
+		liveObj.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert the live object: %w", err)
+		}
+	}
+	// Objects returned by typed clients don't have apiVersion and kind.
+	if liveGVK := liveObj.GroupVersionKind(); liveGVK.Empty() {
+		liveObj.SetGroupVersionKind(gvk)
+	} else if liveGVK != gvk {
+		return nil, fmt.Errorf("cannot plan the apply of a %v to a %v", gvk, liveGVK)
+	}
+	managed, times, err := decodeManagedFields(liveObj.GetManagedFields())
+	if err != nil {
+		return nil, err
+	}
+	liveObj.SetManagedFields(nil)
+
+	liveTyped, err := p.typeConverter.ObjectToTyped(liveObj, typed.AllowDuplicates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the live object: %w", err)
+	}
+	configTyped, err := p.typeConverter.ObjectToTyped(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the apply configuration: %w", err)
+	}
+	identifier, err := managerIdentifier(metav1.ManagedFieldsEntry{Manager: manager, Operation: metav1.ManagedFieldsOperationApply})
+	if err != nil {
+		return nil, err
+	}
+
+	version := fieldpath.APIVersion(gvk.GroupVersion().String())
+	updater := (&merge.UpdaterBuilder{
+		Converter:         sameVersionConverter{},
+		ReturnInputOnNoop: true,
+	}).BuildUpdater()
+
+	plan := &Plan{}
+	newTyped, newManaged, err := updater.Apply(liveTyped, configTyped, version, managed.Copy(), identifier, false)
+	var conflicts merge.Conflicts
+	if errors.As(err, &conflicts) {
+		var denied merge.Conflicts
+		for i := range conflicts {
+			conflicts[i].Manager = managerOf(conflicts[i].Manager).Manager
+			if !opts.Force && !opts.ForceManagers.Has(conflicts[i].Manager) {
+				denied = append(denied, conflicts[i])
+			}
+		}
+		if len(denied) > 0 {
+			return nil, denied
+		}
+		plan.TakenOver = conflicts
+		plan.Force = true
+		newTyped, newManaged, err = updater.Apply(liveTyped, configTyped, version, managed.Copy(), identifier, true)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Diff, err = liveTyped.Compare(newTyped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare the objects: %w", err)
+	}
+	plan.Diff.Added = plan.Diff.Added.Difference(ignoredFields)
+	plan.Diff.Modified = plan.Diff.Modified.Difference(ignoredFields)
+	plan.Diff.Removed = plan.Diff.Removed.Difference(ignoredFields)
+	obj, err := p.typeConverter.TypedToObject(newTyped)
+	if err != nil {
+		return nil, err
+	}
+	plan.Object = &unstructured.Unstructured{}
+	if plan.Object.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj); err != nil {
+		return nil, err
+	}
+	// Like the apiserver, strip the ignored fields, but not their children.
+	for id, versionedSet := range newManaged {
+		fields := versionedSet.Set().Difference(ignoredFields)
+		if fields.Empty() {
+			delete(newManaged, id)
+			continue
+		}
+		newManaged[id] = fieldpath.NewVersionedSet(fields, versionedSet.APIVersion(), versionedSet.Applied())
+	}
+	// The apiserver records the time of the apply.
+	delete(times, identifier)
+	entries, err := encodeManagedFields(newManaged, times)
+	if err != nil {
+		return nil, err
+	}
+	plan.Object.SetManagedFields(entries)
+	return plan, nil
+}
+
+// sameVersionConverter treats all versions as the version of the apply,
+// because conversions require the apiserver.
+type sameVersionConverter struct{}
+
+func (sameVersionConverter) Convert(object *typed.TypedValue, version fieldpath.APIVersion) (*typed.TypedValue, error) {
+	return object, nil
+}
+
+func (sameVersionConverter) IsMissingVersionError(error) bool {
+	return false
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applyplan
+
+import (
+	"errors"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+	"k8s.io/client-go/openapi"
+	"k8s.io/client-go/openapi/openapitest"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v6/merge"
+)
+
+var replicasPath = fieldpath.MakePathOrDie("spec", "replicas")
+
+func newTestPlanner(t *testing.T) *Planner {
+	t.Helper()
+	typeConverter, err := openapi.NewTypeConverter(openapitest.NewEmbeddedFileClient(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewPlanner(typeConverter)
+}
+
+func deploymentApplyConfiguration(replicas int32) *appsv1ac.DeploymentApplyConfiguration {
+	return appsv1ac.Deployment("web", "default").
+		WithLabels(map[string]string{"app": "web"}).
+		WithSpec(appsv1ac.DeploymentSpec().WithReplicas(replicas))
+}
+
+// createDeployment returns a deployment as the apiserver returns it after
+// kubectl created it with an apply.
+func createDeployment(t *testing.T, planner *Planner) *appsv1.Deployment {
+	t.Helper()
+	plan, err := planner.Plan(nil, deploymentApplyConfiguration(1), "kubectl", Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Force || len(plan.TakenOver) > 0 {
+		t.Errorf("expected no conflicts when creating, got %v", plan.TakenOver)
+	}
+	if !plan.Diff.Added.Has(replicasPath) {
+		t.Errorf("expected the replicas to be added, got %v", plan.Diff)
+	}
+	deployment := &appsv1.Deployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(plan.Object.Object, deployment); err != nil {
+		t.Fatal(err)
+	}
+	deployment.TypeMeta = metav1.TypeMeta{}
+	return deployment
+}
+
+func TestPlanOwnership(t *testing.T) {
+	planner := newTestPlanner(t)
+	live := createDeployment(t, planner)
+
+	ownerships, err := Ownerships(live)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ownerships) != 1 || ownerships[0].Manager != "kubectl" || ownerships[0].Operation != metav1.ManagedFieldsOperationApply || ownerships[0].APIVersion != "apps/v1" {
+		t.Fatalf("expected kubectl to own the applied fields, got %+v", ownerships)
+	}
+	fields, err := FieldsOwnedBy(live, "kubectl")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, path := range []fieldpath.Path{replicasPath, fieldpath.MakePathOrDie("metadata", "labels", "app")} {
+		if !fields.Has(path) {
+			t.Errorf("expected kubectl to own %v, got %v", path, fields)
+		}
+	}
+	if fields.Has(fieldpath.MakePathOrDie("metadata", "name")) {
+		t.Errorf("expected the name not to be owned, got %v", fields)
+	}
+
+	// Applying the same configuration again changes nothing.
+	plan, err := planner.Plan(live, deploymentApplyConfiguration(1), "kubectl", Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !plan.Diff.IsSame() {
+		t.Errorf("expected no changes, got %v", plan.Diff)
+	}
+	if replicas, _, _ := unstructured.NestedInt64(plan.Object.Object, "spec", "replicas"); replicas != 1 {
+		t.Errorf("expected 1 replica, got %d", replicas)
+	}
+}
+
+func TestPlanConflicts(t *testing.T) {
+	planner := newTestPlanner(t)
+	live := createDeployment(t, planner)
+
+	for name, opts := range map[string]Options{
+		"no force":            {},
+		"other force manager": {ForceManagers: sets.New("helm")},
+	} {
+		_, err := planner.Plan(live, deploymentApplyConfiguration(3), "autoscaler", opts)
+		var conflicts merge.Conflicts
+		if !errors.As(err, &conflicts) {
+			t.Fatalf("%s: expected conflicts, got %v", name, err)
+		}
+		if len(conflicts) != 1 || conflicts[0].Manager != "kubectl" || !conflicts[0].Path.Equals(replicasPath) {
+			t.Errorf("%s: expected a conflict with kubectl on the replicas, got %v", name, conflicts)
+		}
+	}
+
+	for name, opts := range map[string]Options{
+		"force":         {Force: true},
+		"force manager": {ForceManagers: sets.New("kubectl")},
+	} {
+		plan, err := planner.Plan(live, deploymentApplyConfiguration(3), "autoscaler", opts)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !plan.Force || len(plan.TakenOver) != 1 || plan.TakenOver[0].Manager != "kubectl" {
+			t.Errorf("%s: expected the replicas to be taken over from kubectl, got %v", name, plan.TakenOver)
+		}
+		if !plan.Diff.Modified.Has(replicasPath) || !plan.Diff.Added.Empty() || !plan.Diff.Removed.Empty() {
+			t.Errorf("%s: expected only the replicas to be modified, got %v", name, plan.Diff)
+		}
+		if replicas, _, _ := unstructured.NestedInt64(plan.Object.Object, "spec", "replicas"); replicas != 3 {
+			t.Errorf("%s: expected 3 replicas, got %d", name, replicas)
+		}
+		for manager, owns := range map[string]bool{"autoscaler": true, "kubectl": false} {
+			fields, err := FieldsOwnedBy(plan.Object, manager)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+			if fields.Has(replicasPath) != owns {
+				t.Errorf("%s: expected %s to own the replicas: %v, got %v", name, manager, owns, fields)
+			}
+		}
+	}
+}
+
+func TestPlanInvalid(t *testing.T) {
+	planner := newTestPlanner(t)
+	live := createDeployment(t, planner)
+	live.TypeMeta = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"}
+	if _, err := planner.Plan(live, deploymentApplyConfiguration(1), "kubectl", Options{}); err == nil {
+		t.Error("expected an error for an apply configuration of another kind")
+	}
+	if _, err := planner.Plan(nil, appsv1ac.DeploymentSpec(), "kubectl", Options{}); err == nil {
+		t.Error("expected an error for an apply configuration without kind")
+	}
+}
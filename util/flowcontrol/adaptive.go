@@ -0,0 +1,289 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flowcontrol
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/utils/clock"
+)
+
+// RequestFeedback describes the response of the server to a request.
+type RequestFeedback struct {
+	// PriorityLevelUID is the UID of the API Priority and Fairness priority
+	// level which the request was assigned to, as returned in the
+	// X-Kubernetes-PF-PriorityLevel-UID response header. It is empty if the
+	// server did not return it.
+	PriorityLevelUID string
+	// Throttled is true if the server rejected the request with
+	// 429 Too Many Requests.
+	Throttled bool
+	// RetryAfter is the delay which the server asked for in the Retry-After
+	// header of a throttled response. No requests of the priority level are
+	// allowed before it passed.
+	RetryAfter time.Duration
+	// Latency is the time it took the server to respond.
+	Latency time.Duration
+}
+
+// AdaptiveRateLimiter is a RateLimiter which adapts its rate to the feedback
+// of the server. Requests are identified by a key, for example their verb and
+// resource, which gets mapped to the priority level that the server assigned
+// the last request with the same key to. Requests with a key which has not
+// been seen yet share a default rate.
+type AdaptiveRateLimiter interface {
+	RateLimiter
+	// WaitFor returns nil if a token for the rate of the request key is taken
+	// before the Context is done.
+	WaitFor(ctx context.Context, key string) error
+	// Observe reports the response to a request with the given key.
+	Observe(key string, feedback RequestFeedback)
+}
+
+// AdaptiveRateLimiterConfig configures an AdaptiveRateLimiter.
+type AdaptiveRateLimiterConfig struct {
+	// InitialQPS is the rate of a priority level before any feedback
+	// arrived. If zero, MinQPS is used. It is bounded by MinQPS and MaxQPS.
+	InitialQPS float32
+	// MinQPS and MaxQPS bound the rate of each priority level. If MinQPS is
+	// not positive, 1 is used. If MaxQPS is not positive, the larger of
+	// InitialQPS and 50 is used. If MaxQPS is less than MinQPS, MinQPS is used.
+	MinQPS float32
+	MaxQPS float32
+	// Burst is the maximum burst of each priority level. If less than 1,
+	// 1 is used.
+	Burst int
+	// AdditiveIncrease is the rate increase per second while requests
+	// succeed. If zero, 1 is used.
+	AdditiveIncrease float32
+	// MultiplicativeDecrease is the factor which the rate is multiplied by
+	// when requests are throttled. If zero, 0.5 is used.
+	MultiplicativeDecrease float32
+	// LatencyThreshold is the latency above which responses are treated
+	// like throttled requests. Zero means latencies are ignored.
+	LatencyThreshold time.Duration
+	// DecreaseInterval is the minimum time between two rate decreases of a
+	// priority level, so that a burst of throttled requests only decreases
+	// the rate once. If zero, 1s is used.
+	DecreaseInterval time.Duration
+}
+
+// defaultAdaptiveMaxQPS is the upper bound of the rate of a priority level
+// if AdaptiveRateLimiterConfig.MaxQPS is not set.
+const defaultAdaptiveMaxQPS = 50
+
+// defaultPriorityLevel is the key of the rate used for requests whose
+// priority level is unknown.
+const defaultPriorityLevel = ""
+
+type adaptiveRateLimiter struct {
+	config AdaptiveRateLimiterConfig
+	clock  clock.Clock
+
+	lock sync.Mutex
+	// priorityLevels maps priority level UIDs to their rates.
+	priorityLevels map[string]*priorityLevelRate
+	// keys maps request keys to the priority level UID of their last response.
+	keys map[string]string
+}
+
+type priorityLevelRate struct {
+	limiter      *rate.Limiter
+	qps          float64
+	lastDecrease time.Time
+	// pausedUntil is the time until which the server asked to not send
+	// requests with Retry-After.
+	pausedUntil time.Time
+}
+
+// NewAdaptiveRateLimiter returns an AdaptiveRateLimiter which adapts the rate
+// of each API Priority and Fairness priority level with additive increase,
+// multiplicative decrease: the rate grows while requests succeed and gets
+// cut when the server throttles requests or exceeds the latency threshold.
+func NewAdaptiveRateLimiter(config AdaptiveRateLimiterConfig) AdaptiveRateLimiter {
+	return NewAdaptiveRateLimiterWithClock(config, clock.RealClock{})
+}
+
+// NewAdaptiveRateLimiterWithClock is identical to NewAdaptiveRateLimiter but
+// allows an injectable clock, for testing.
+func NewAdaptiveRateLimiterWithClock(config AdaptiveRateLimiterConfig, c clock.Clock) AdaptiveRateLimiter {
+	if config.MinQPS <= 0 {
+		config.MinQPS = 1
+	}
+	if config.MaxQPS <= 0 {
+		config.MaxQPS = max(config.InitialQPS, defaultAdaptiveMaxQPS)
+	}
+	config.MaxQPS = max(config.MaxQPS, config.MinQPS)
+	if config.InitialQPS == 0 {
+		config.InitialQPS = config.MinQPS
+	}
+	config.InitialQPS = min(max(config.InitialQPS, config.MinQPS), config.MaxQPS)
+	if config.Burst < 1 {
+		config.Burst = 1
+	}
+	if config.AdditiveIncrease == 0 {
+		config.AdditiveIncrease = 1
+	}
+	if config.MultiplicativeDecrease == 0 {
+		config.MultiplicativeDecrease = 0.5
+	}
+	if config.DecreaseInterval == 0 {
+		config.DecreaseInterval = time.Second
+	}
+	return &adaptiveRateLimiter{
+		config:         config,
+		clock:          c,
+		priorityLevels: map[string]*priorityLevelRate{},
+		keys:           map[string]string{},
+	}
+}
+
+// priorityLevelLocked returns the rate of a priority level, creating it if needed.
+func (a *adaptiveRateLimiter) priorityLevelLocked(uid string) *priorityLevelRate {
+	pl, ok := a.priorityLevels[uid]
+	if !ok {
+		qps := float64(a.config.InitialQPS)
+		pl = &priorityLevelRate{
+			limiter: rate.NewLimiter(rate.Limit(qps), a.config.Burst),
+			qps:     qps,
+		}
+		a.priorityLevels[uid] = pl
+	}
+	return pl
+}
+
+// limiterFor returns the limiter of the priority level of key and the time
+// until which the priority level is paused.
+func (a *adaptiveRateLimiter) limiterFor(key string) (*rate.Limiter, time.Time) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	pl := a.priorityLevelLocked(a.keys[key])
+	return pl.limiter, pl.pausedUntil
+}
+
+func (a *adaptiveRateLimiter) TryAccept() bool {
+	limiter, pausedUntil := a.limiterFor("")
+	now := a.clock.Now()
+	return !now.Before(pausedUntil) && limiter.AllowN(now, 1)
+}
+
+func (a *adaptiveRateLimiter) Stop() {}
+
+// QPS returns the current rate of requests whose priority level is unknown.
+func (a *adaptiveRateLimiter) QPS() float32 {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return float32(a.priorityLevelLocked(defaultPriorityLevel).qps)
+}
+
+func (a *adaptiveRateLimiter) Accept() {
+	limiter, pausedUntil := a.limiterFor("")
+	now := a.clock.Now()
+	if pausedUntil.After(now) {
+		a.clock.Sleep(pausedUntil.Sub(now))
+		now = a.clock.Now()
+	}
+	a.clock.Sleep(limiter.ReserveN(now, 1).DelayFrom(now))
+}
+
+func (a *adaptiveRateLimiter) Wait(ctx context.Context) error {
+	return a.WaitFor(ctx, "")
+}
+
+func (a *adaptiveRateLimiter) WaitFor(ctx context.Context, key string) error {
+	limiter, pausedUntil := a.limiterFor(key)
+	if delay := pausedUntil.Sub(a.clock.Now()); delay > 0 {
+		if err := a.sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+	now := a.clock.Now()
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return fmt.Errorf("rate: Wait(n=1) exceeds limiter's burst %d", limiter.Burst())
+	}
+	if err := a.sleep(ctx, reservation.DelayFrom(now)); err != nil {
+		reservation.CancelAt(a.clock.Now())
+		return err
+	}
+	return nil
+}
+
+// sleep waits for delay on the clock of the limiter, unless the Context is
+// done before or its deadline is earlier.
+func (a *adaptiveRateLimiter) sleep(ctx context.Context, delay time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Sub(a.clock.Now()) < delay {
+		return fmt.Errorf("rate: Wait(n=1) would exceed context deadline")
+	}
+	timer := a.clock.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (a *adaptiveRateLimiter) Observe(key string, feedback RequestFeedback) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	uid := a.keys[key]
+	if feedback.PriorityLevelUID != "" {
+		uid = feedback.PriorityLevelUID
+		a.keys[key] = uid
+	}
+	pl := a.priorityLevelLocked(uid)
+	now := a.clock.Now()
+
+	if feedback.Throttled && feedback.RetryAfter > 0 {
+		if pausedUntil := now.Add(feedback.RetryAfter); pausedUntil.After(pl.pausedUntil) {
+			pl.pausedUntil = pausedUntil
+		}
+	}
+
+	qps := pl.qps
+	if feedback.Throttled || (a.config.LatencyThreshold > 0 && feedback.Latency > a.config.LatencyThreshold) {
+		if now.Sub(pl.lastDecrease) < a.config.DecreaseInterval {
+			return
+		}
+		pl.lastDecrease = now
+		qps *= float64(a.config.MultiplicativeDecrease)
+	} else {
+		// Each success adds its share of the increase per second, so that
+		// the rate grows by AdditiveIncrease per second at full utilization.
+		qps += float64(a.config.AdditiveIncrease) / max(qps, 1)
+	}
+	qps = min(max(qps, float64(a.config.MinQPS)), float64(a.config.MaxQPS))
+	if qps != pl.qps {
+		pl.qps = qps
+		pl.limiter.SetLimitAt(now, rate.Limit(qps))
+	}
+}
+
+var _ AdaptiveRateLimiter = (*adaptiveRateLimiter)(nil)
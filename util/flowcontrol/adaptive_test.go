@@ -0,0 +1,217 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flowcontrol
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func priorityLevelQPS(limiter AdaptiveRateLimiter, uid string) float64 {
+	a := limiter.(*adaptiveRateLimiter)
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.priorityLevelLocked(uid).qps
+}
+
+func TestAdaptiveRateLimiterAIMD(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	limiter := NewAdaptiveRateLimiterWithClock(AdaptiveRateLimiterConfig{
+		InitialQPS: 10,
+		MinQPS:     2,
+		MaxQPS:     12,
+		Burst:      1,
+	}, clock)
+
+	// Ten successes at ten QPS add one QPS.
+	for i := 0; i < 10; i++ {
+		limiter.Observe("GET pods", RequestFeedback{})
+	}
+	if qps := limiter.QPS(); qps < 10.9 || qps > 11 {
+		t.Errorf("expected about 11 QPS, got %v", qps)
+	}
+	for i := 0; i < 100; i++ {
+		limiter.Observe("GET pods", RequestFeedback{})
+	}
+	if qps := limiter.QPS(); qps != 12 {
+		t.Errorf("expected the rate to be capped at 12 QPS, got %v", qps)
+	}
+
+	// A burst of throttled requests halves the rate once.
+	for i := 0; i < 5; i++ {
+		limiter.Observe("GET pods", RequestFeedback{Throttled: true})
+	}
+	if qps := limiter.QPS(); qps != 6 {
+		t.Errorf("expected 6 QPS, got %v", qps)
+	}
+	clock.Step(time.Second)
+	limiter.Observe("GET pods", RequestFeedback{Throttled: true})
+	clock.Step(time.Second)
+	limiter.Observe("GET pods", RequestFeedback{Throttled: true})
+	if qps := limiter.QPS(); qps != 2 {
+		t.Errorf("expected the rate to be bounded by 2 QPS, got %v", qps)
+	}
+}
+
+func TestAdaptiveRateLimiterLatencyThreshold(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	limiter := NewAdaptiveRateLimiterWithClock(AdaptiveRateLimiterConfig{
+		InitialQPS:       8,
+		MaxQPS:           20,
+		LatencyThreshold: time.Second,
+	}, clock)
+
+	limiter.Observe("LIST pods", RequestFeedback{Latency: 500 * time.Millisecond})
+	if qps := limiter.QPS(); qps <= 8 {
+		t.Errorf("expected the rate to grow, got %v", qps)
+	}
+	clock.Step(time.Second)
+	limiter.Observe("LIST pods", RequestFeedback{Latency: 2 * time.Second})
+	if qps := limiter.QPS(); qps >= 5 {
+		t.Errorf("expected the rate to be cut, got %v", qps)
+	}
+}
+
+func TestAdaptiveRateLimiterPriorityLevels(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	limiter := NewAdaptiveRateLimiterWithClock(AdaptiveRateLimiterConfig{
+		InitialQPS: 10,
+		MaxQPS:     100,
+		Burst:      1,
+	}, clock)
+
+	limiter.Observe("PUT leases", RequestFeedback{PriorityLevelUID: "leader-election"})
+	limiter.Observe("GET pods", RequestFeedback{PriorityLevelUID: "workload-low", Throttled: true})
+
+	if qps := priorityLevelQPS(limiter, "workload-low"); qps != 5 {
+		t.Errorf("expected workload-low to be cut to 5 QPS, got %v", qps)
+	}
+	if qps := priorityLevelQPS(limiter, "leader-election"); qps <= 10 {
+		t.Errorf("expected leader-election to be unaffected, got %v", qps)
+	}
+	if qps := limiter.QPS(); qps != 10 {
+		t.Errorf("expected the default rate to be unaffected, got %v", qps)
+	}
+
+	// The burst of each priority level is separate.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for _, key := range []string{"PUT leases", "GET pods", "LIST nodes"} {
+		if err := limiter.WaitFor(ctx, key); err != nil {
+			t.Errorf("%s: unexpected error: %v", key, err)
+		}
+	}
+	if limiter.TryAccept() {
+		t.Errorf("expected the burst of the default rate to be used up")
+	}
+}
+
+func TestAdaptiveRateLimiterDefaults(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	limiter := NewAdaptiveRateLimiterWithClock(AdaptiveRateLimiterConfig{}, clock)
+	if !limiter.TryAccept() {
+		t.Errorf("expected a burst of at least one request")
+	}
+	for i := 0; i < 100; i++ {
+		limiter.Observe("GET pods", RequestFeedback{})
+	}
+	if qps := limiter.QPS(); qps <= 1 {
+		t.Errorf("expected the rate to grow without MaxQPS, got %v", qps)
+	}
+	for i := 0; i < 10000; i++ {
+		limiter.Observe("GET pods", RequestFeedback{})
+	}
+	if qps := limiter.QPS(); qps != defaultAdaptiveMaxQPS {
+		t.Errorf("expected the rate to be bounded by %v without MaxQPS, got %v", defaultAdaptiveMaxQPS, qps)
+	}
+
+	limiter = NewAdaptiveRateLimiterWithClock(AdaptiveRateLimiterConfig{
+		InitialQPS: 20,
+		MinQPS:     5,
+		MaxQPS:     2,
+	}, clock)
+	if qps := limiter.QPS(); qps != 5 {
+		t.Errorf("expected MinQPS to bound the initial rate, got %v", qps)
+	}
+	limiter.Observe("GET pods", RequestFeedback{})
+	if qps := limiter.QPS(); qps != 5 {
+		t.Errorf("expected MaxQPS to be raised to MinQPS, got %v", qps)
+	}
+}
+
+func TestAdaptiveRateLimiterRetryAfter(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	limiter := NewAdaptiveRateLimiterWithClock(AdaptiveRateLimiterConfig{
+		InitialQPS: 100,
+		MaxQPS:     100,
+		Burst:      10,
+	}, clock)
+
+	limiter.Observe("GET pods", RequestFeedback{Throttled: true, RetryAfter: 2 * time.Second})
+	if limiter.TryAccept() {
+		t.Errorf("expected no requests before Retry-After passed")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := limiter.WaitFor(ctx, "GET pods"); err == nil {
+		t.Errorf("expected an error for a deadline before Retry-After passed")
+	}
+
+	clock.Step(2 * time.Second)
+	if !limiter.TryAccept() {
+		t.Errorf("expected requests after Retry-After passed")
+	}
+}
+
+func TestAdaptiveRateLimiterWaitForClock(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	limiter := NewAdaptiveRateLimiterWithClock(AdaptiveRateLimiterConfig{
+		InitialQPS: 1,
+		MaxQPS:     1,
+	}, clock)
+	ctx := context.Background()
+	waitFor := func(step time.Duration) {
+		t.Helper()
+		done := make(chan error)
+		go func() {
+			done <- limiter.WaitFor(ctx, "GET pods")
+		}()
+		for !clock.HasWaiters() {
+			time.Sleep(time.Millisecond)
+		}
+		select {
+		case err := <-done:
+			t.Fatalf("expected WaitFor to block, got %v", err)
+		default:
+		}
+		clock.Step(step)
+		if err := <-done; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Both the rate and Retry-After are waited for on the clock of the limiter.
+	if err := limiter.WaitFor(ctx, "GET pods"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitFor(time.Second)
+	limiter.Observe("GET pods", RequestFeedback{Throttled: true, RetryAfter: 5 * time.Second})
+	waitFor(5 * time.Second)
+}
@@ -16,7 +16,8 @@ limitations under the License.
 
 // Package workqueue provides a simple queue that supports the following
 // features:
-//   - Fair: items processed in the order in which they are added.
+//   - Fair: items processed in the order in which they are added, or by
+//     priority when using a priority queue.
 //   - Stingy: a single item will not be processed multiple times concurrently,
 //     and if an item is added multiple times before it can be processed, it
 //     will only be processed once.
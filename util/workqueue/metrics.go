@@ -253,3 +253,82 @@ func SetProvider(metricsProvider MetricsProvider) {
 		globalMetricsProvider = metricsProvider
 	})
 }
+
+// PriorityMetricsProvider can optionally be implemented by a MetricsProvider
+// to generate metrics per priority level for priority queues, see
+// NewTypedPriorityRateLimitingQueueWithConfig.
+type PriorityMetricsProvider interface {
+	NewPriorityDepthMetric(name string, priority int) GaugeMetric
+	NewPriorityLatencyMetric(name string, priority int) HistogramMetric
+}
+
+// priorityQueueMetrics expects the caller to lock before setting any metrics.
+type priorityQueueMetrics struct {
+	clock    clock.Clock
+	name     string
+	provider PriorityMetricsProvider
+
+	// current depth of a workqueue per priority level
+	depth map[int]GaugeMetric
+	// how long an item stays in a workqueue per priority level
+	latency map[int]HistogramMetric
+}
+
+func (m *priorityQueueMetrics) add(priority int) {
+	if m == nil {
+		return
+	}
+
+	m.depthMetric(priority).Inc()
+}
+
+func (m *priorityQueueMetrics) get(priority int, addedAt time.Time) {
+	if m == nil {
+		return
+	}
+
+	m.depthMetric(priority).Dec()
+	m.latencyMetric(priority).Observe(m.clock.Since(addedAt).Seconds())
+}
+
+func (m *priorityQueueMetrics) move(from, to int) {
+	if m == nil {
+		return
+	}
+
+	m.depthMetric(from).Dec()
+	m.depthMetric(to).Inc()
+}
+
+func (m *priorityQueueMetrics) depthMetric(priority int) GaugeMetric {
+	metric, ok := m.depth[priority]
+	if !ok {
+		metric = m.provider.NewPriorityDepthMetric(m.name, priority)
+		m.depth[priority] = metric
+	}
+	return metric
+}
+
+func (m *priorityQueueMetrics) latencyMetric(priority int) HistogramMetric {
+	metric, ok := m.latency[priority]
+	if !ok {
+		metric = m.provider.NewPriorityLatencyMetric(m.name, priority)
+		m.latency[priority] = metric
+	}
+	return metric
+}
+
+func newPriorityQueueMetrics(mp MetricsProvider, name string, clock clock.Clock) *priorityQueueMetrics {
+	var ret *priorityQueueMetrics
+	provider, ok := mp.(PriorityMetricsProvider)
+	if len(name) == 0 || !ok {
+		return ret
+	}
+	return &priorityQueueMetrics{
+		clock:    clock,
+		name:     name,
+		provider: provider,
+		depth:    map[int]GaugeMetric{},
+		latency:  map[int]HistogramMetric{},
+	}
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workqueue
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// DefaultPriorityAgingInterval is the aging interval used by priority queues
+// when TypedPriorityRateLimitingQueueConfig.AgingInterval is unset.
+const DefaultPriorityAgingInterval = 10 * time.Second
+
+// TypedPriorityRateLimitingInterface is a TypedRateLimitingInterface which hands
+// out items with a higher priority before items with a lower priority.
+//
+// The priority given to an item is remembered until Get hands the item out,
+// so an item which is added with a priority while it is processed is queued
+// with that priority when Done is called, even if Forget was called before.
+// Items that are added without a priority use the default priority of the
+// queue.
+type TypedPriorityRateLimitingInterface[T comparable] interface {
+	TypedRateLimitingInterface[T]
+
+	// AddWithPriority marks item as needing processing with the given priority.
+	// Higher values are processed first. If the item is already waiting to be
+	// processed, its priority is raised to the given priority but never lowered.
+	AddWithPriority(item T, priority int)
+}
+
+// TypedPriorityRateLimitingQueueConfig specifies optional configurations to customize a TypedPriorityRateLimitingInterface.
+type TypedPriorityRateLimitingQueueConfig[T comparable] struct {
+	// Name for the queue. If unnamed, the metrics will not be registered.
+	Name string
+
+	// MetricsProvider optionally allows specifying a metrics provider to use for the queue
+	// instead of the global provider. If it also implements PriorityMetricsProvider,
+	// depth and latency metrics are additionally reported per priority level.
+	MetricsProvider MetricsProvider
+
+	// Clock optionally allows injecting a real or fake clock for testing purposes.
+	Clock clock.WithTicker
+
+	// DefaultPriority is the priority of items which are added without one.
+	DefaultPriority int
+
+	// AgingInterval protects low priority items against starvation: for every
+	// AgingInterval an item spends waiting in the queue, it is treated as if its
+	// priority was one level higher. Defaults to DefaultPriorityAgingInterval,
+	// a negative value disables aging.
+	AgingInterval time.Duration
+}
+
+// NewTypedPriorityRateLimitingQueueWithConfig constructs a new workqueue with rateLimited queuing
+// ability which processes items by priority.
+// Remember to call Forget!  If you don't, you may end up tracking failures forever.
+func NewTypedPriorityRateLimitingQueueWithConfig[T comparable](rateLimiter TypedRateLimiter[T], config TypedPriorityRateLimitingQueueConfig[T]) TypedPriorityRateLimitingInterface[T] {
+	metricsProvider := globalMetricsProvider
+	if config.MetricsProvider != nil {
+		metricsProvider = config.MetricsProvider
+	}
+
+	if config.Clock == nil {
+		config.Clock = clock.RealClock{}
+	}
+
+	if config.AgingInterval == 0 {
+		config.AgingInterval = DefaultPriorityAgingInterval
+	}
+
+	priorities := &itemPriorities[T]{
+		defaultPriority: config.DefaultPriority,
+		priorities:      map[T]itemPriority{},
+	}
+	queue := newPriorityQueue(config.Clock, config.AgingInterval, priorities, newPriorityQueueMetrics(metricsProvider, config.Name, config.Clock))
+
+	return &priorityRateLimitingType[T]{
+		TypedRateLimitingInterface: NewTypedRateLimitingQueueWithConfig(rateLimiter, TypedRateLimitingQueueConfig[T]{
+			Clock: config.Clock,
+			DelayingQueue: NewTypedDelayingQueueWithConfig(TypedDelayingQueueConfig[T]{
+				Name:            config.Name,
+				MetricsProvider: config.MetricsProvider,
+				Clock:           config.Clock,
+				Queue: NewTypedWithConfig(TypedQueueConfig[T]{
+					Name:            config.Name,
+					MetricsProvider: config.MetricsProvider,
+					Clock:           config.Clock,
+					Queue:           queue,
+				}),
+			}),
+		}),
+		priorities: priorities,
+	}
+}
+
+// priorityRateLimitingType wraps a TypedRateLimitingInterface whose underlying
+// Queue is a priorityQueue and records the priorities that the queue consults.
+type priorityRateLimitingType[T comparable] struct {
+	TypedRateLimitingInterface[T]
+
+	priorities *itemPriorities[T]
+}
+
+func (q *priorityRateLimitingType[T]) AddWithPriority(item T, priority int) {
+	// The priority must be recorded before adding the item, the queue looks
+	// it up while pushing or touching the item.
+	q.priorities.set(item, priority)
+	q.Add(item)
+}
+
+// itemPriorities remembers the priorities assigned to items until the items
+// are handed out. It has its own lock because it is written outside of the
+// queue lock and read under it.
+type itemPriorities[T comparable] struct {
+	lock            sync.Mutex
+	defaultPriority int
+	priorities      map[T]itemPriority
+	// version is increased whenever a priority is set, so that handing out
+	// an item only forgets the priority it was queued with.
+	version uint64
+}
+
+type itemPriority struct {
+	priority int
+	version  uint64
+}
+
+// set records the priority of an item, unless it already has a higher one.
+func (p *itemPriorities[T]) set(item T, priority int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if existing, ok := p.priorities[item]; ok {
+		priority = max(existing.priority, priority)
+	}
+	p.version++
+	p.priorities[item] = itemPriority{priority: priority, version: p.version}
+}
+
+// get returns the priority of an item and the version of that priority,
+// which is zero for the default priority.
+func (p *itemPriorities[T]) get(item T) itemPriority {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if priority, ok := p.priorities[item]; ok {
+		return priority
+	}
+	return itemPriority{priority: p.defaultPriority}
+}
+
+// handedOut forgets the priority of an item when it is handed out, unless
+// the priority was set again since the item was queued with the given
+// version. That happens if AddWithPriority set the priority but did not add
+// the item yet.
+func (p *itemPriorities[T]) handedOut(item T, version uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if existing, ok := p.priorities[item]; ok && existing.version == version {
+		delete(p.priorities, item)
+	}
+}
+
+// prioritizedItem is an item waiting in a priorityQueue.
+type prioritizedItem[T any] struct {
+	data     T
+	priority int
+	// version of the priority in itemPriorities
+	version uint64
+	addedAt time.Time
+	// seq breaks ties in favour of the item which was pushed first
+	seq uint64
+	// index in the heap
+	index int
+}
+
+// priorityHeap implements heap.Interface for prioritizedItems. The item to be
+// handed out next is at the root (index 0).
+type priorityHeap[T any] struct {
+	items         []*prioritizedItem[T]
+	agingInterval time.Duration
+}
+
+func (h *priorityHeap[T]) Len() int {
+	return len(h.items)
+}
+
+// Less orders items by their effective priority. Because all waiting items
+// age at the same rate, comparing priority+waited/agingInterval of two items
+// is the same as comparing their addedAt shifted back by priority*agingInterval,
+// which does not change over time and can therefore be kept in a heap.
+func (h *priorityHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if h.agingInterval > 0 {
+		aDeadline := a.addedAt.Add(-time.Duration(a.priority) * h.agingInterval)
+		bDeadline := b.addedAt.Add(-time.Duration(b.priority) * h.agingInterval)
+		if !aDeadline.Equal(bDeadline) {
+			return aDeadline.Before(bDeadline)
+		}
+	} else if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	return a.seq < b.seq
+}
+
+func (h *priorityHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+// Push adds an item to the heap. Push should not be called directly; instead,
+// use `heap.Push`.
+func (h *priorityHeap[T]) Push(x interface{}) {
+	item := x.(*prioritizedItem[T])
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+// Pop removes an item from the heap. Pop should not be called directly;
+// instead, use `heap.Pop`.
+func (h *priorityHeap[T]) Pop() interface{} {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items[n-1] = nil
+	item.index = -1
+	h.items = h.items[0 : n-1]
+	return item
+}
+
+// priorityQueue is a Queue which pops items by their effective priority. Like
+// every Queue, it is only called with the lock of the owning Typed held.
+type priorityQueue[T comparable] struct {
+	clock clock.PassiveClock
+	heap  *priorityHeap[T]
+	items map[T]*prioritizedItem[T]
+	seq   uint64
+
+	// priorities are the priorities the items should be queued with.
+	priorities *itemPriorities[T]
+
+	metrics *priorityQueueMetrics
+}
+
+var _ Queue[string] = &priorityQueue[string]{}
+
+func newPriorityQueue[T comparable](c clock.PassiveClock, agingInterval time.Duration, priorities *itemPriorities[T], metrics *priorityQueueMetrics) *priorityQueue[T] {
+	return &priorityQueue[T]{
+		clock:      c,
+		heap:       &priorityHeap[T]{agingInterval: agingInterval},
+		items:      map[T]*prioritizedItem[T]{},
+		priorities: priorities,
+		metrics:    metrics,
+	}
+}
+
+// Touch raises the priority of a waiting item if it was re-added with a
+// higher priority. The time the item has already spent waiting is kept.
+func (q *priorityQueue[T]) Touch(item T) {
+	existing, ok := q.items[item]
+	if !ok {
+		return
+	}
+	priority := q.priorities.get(item)
+	existing.version = priority.version
+	if priority.priority <= existing.priority {
+		return
+	}
+	q.metrics.move(existing.priority, priority.priority)
+	existing.priority = priority.priority
+	heap.Fix(q.heap, existing.index)
+}
+
+func (q *priorityQueue[T]) Push(item T) {
+	priority := q.priorities.get(item)
+	entry := &prioritizedItem[T]{
+		data:     item,
+		priority: priority.priority,
+		version:  priority.version,
+		addedAt:  q.clock.Now(),
+		seq:      q.seq,
+	}
+	q.seq++
+	q.metrics.add(entry.priority)
+	heap.Push(q.heap, entry)
+	q.items[item] = entry
+}
+
+func (q *priorityQueue[T]) Len() int {
+	return q.heap.Len()
+}
+
+func (q *priorityQueue[T]) Pop() (item T) {
+	entry := heap.Pop(q.heap).(*prioritizedItem[T])
+	delete(q.items, entry.data)
+	q.priorities.handedOut(entry.data, entry.version)
+	q.metrics.get(entry.priority, entry.addedAt)
+	return entry.data
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workqueue
+
+import (
+	"testing"
+	"time"
+
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func getAll(t *testing.T, q TypedInterface[string], n int) []string {
+	t.Helper()
+	var items []string
+	for i := 0; i < n; i++ {
+		item, shutdown := q.Get()
+		if shutdown {
+			t.Fatalf("unexpected shutdown after %v", items)
+		}
+		items = append(items, item)
+		q.Done(item)
+	}
+	return items
+}
+
+func expectOrder(t *testing.T, expected, actual []string) {
+	t.Helper()
+	if len(expected) != len(actual) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Fatalf("expected %v, got %v", expected, actual)
+		}
+	}
+}
+
+func TestPriorityQueueOrder(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	q := NewTypedPriorityRateLimitingQueueWithConfig(DefaultTypedControllerRateLimiter[string](), TypedPriorityRateLimitingQueueConfig[string]{
+		Clock:         c,
+		AgingInterval: -1,
+	})
+	defer q.ShutDown()
+
+	q.Add("low-1")
+	q.AddWithPriority("high-1", 10)
+	q.Add("low-2")
+	q.AddWithPriority("medium", 5)
+	q.AddWithPriority("high-2", 10)
+
+	if e, a := 5, q.Len(); e != a {
+		t.Fatalf("expected %v, got %v", e, a)
+	}
+	expectOrder(t, []string{"high-1", "high-2", "medium", "low-1", "low-2"}, getAll(t, q, 5))
+}
+
+func TestPriorityQueueTouch(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	q := NewTypedPriorityRateLimitingQueueWithConfig(DefaultTypedControllerRateLimiter[string](), TypedPriorityRateLimitingQueueConfig[string]{
+		Clock:         c,
+		AgingInterval: -1,
+	})
+	defer q.ShutDown()
+
+	q.AddWithPriority("a", 1)
+	q.AddWithPriority("b", 2)
+	q.AddWithPriority("c", 3)
+	// raising the priority of a waiting item moves it ahead
+	q.AddWithPriority("a", 5)
+	// lowering the priority of a waiting item has no effect
+	q.AddWithPriority("c", 0)
+
+	if e, a := 3, q.Len(); e != a {
+		t.Fatalf("expected %v, got %v", e, a)
+	}
+	expectOrder(t, []string{"a", "c", "b"}, getAll(t, q, 3))
+}
+
+func TestPriorityQueueAging(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	q := NewTypedPriorityRateLimitingQueueWithConfig(DefaultTypedControllerRateLimiter[string](), TypedPriorityRateLimitingQueueConfig[string]{
+		Clock:         c,
+		AgingInterval: time.Second,
+	})
+	defer q.ShutDown()
+
+	q.Add("old")
+	c.Step(3 * time.Second)
+	q.AddWithPriority("higher", 2)
+	q.AddWithPriority("highest", 4)
+
+	// "old" has aged by three levels, which puts it ahead of "higher" but
+	// not ahead of "highest".
+	expectOrder(t, []string{"highest", "old", "higher"}, getAll(t, q, 3))
+}
+
+func TestPriorityQueueProcessing(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	q := NewTypedPriorityRateLimitingQueueWithConfig(DefaultTypedControllerRateLimiter[string](), TypedPriorityRateLimitingQueueConfig[string]{
+		Clock:         c,
+		AgingInterval: -1,
+	})
+	defer q.ShutDown()
+
+	q.Add("a")
+	item, _ := q.Get()
+
+	// re-adding an item while it is processed only queues it once Done is called
+	q.AddWithPriority(item, 3)
+	q.AddWithPriority(item, 3)
+	q.AddWithPriority("b", 1)
+	if e, a := 1, q.Len(); e != a {
+		t.Fatalf("expected %v, got %v", e, a)
+	}
+	q.Done(item)
+	if e, a := 2, q.Len(); e != a {
+		t.Fatalf("expected %v, got %v", e, a)
+	}
+	expectOrder(t, []string{"a", "b"}, getAll(t, q, 2))
+}
+
+func TestPriorityQueueHandOut(t *testing.T) {
+	c := testingclock.NewFakeClock(time.Now())
+	q := NewTypedPriorityRateLimitingQueueWithConfig(DefaultTypedControllerRateLimiter[string](), TypedPriorityRateLimitingQueueConfig[string]{
+		Clock:           c,
+		DefaultPriority: 1,
+		AgingInterval:   -1,
+	})
+	defer q.ShutDown()
+
+	q.AddWithPriority("a", 2)
+	q.Add("b")
+	expectOrder(t, []string{"a", "b"}, getAll(t, q, 2))
+
+	// the priority of "a" was forgotten when it was handed out
+	q.Add("b")
+	q.Add("a")
+	expectOrder(t, []string{"b", "a"}, getAll(t, q, 2))
+
+	// a priority raised while the item is processed survives Forget and
+	// applies when Done queues the item again
+	q.Add("a")
+	item, _ := q.Get()
+	q.AddWithPriority(item, 3)
+	q.AddWithPriority(item, 0)
+	q.Add("b")
+	q.Forget(item)
+	q.Done(item)
+	expectOrder(t, []string{"a", "b"}, getAll(t, q, 2))
+}
+
+type testPriorityMetricsProvider struct {
+	testMetricsProvider
+
+	priorityDepth   map[int]*testMetric
+	priorityLatency map[int]*testMetric
+}
+
+func (m *testPriorityMetricsProvider) NewPriorityDepthMetric(name string, priority int) GaugeMetric {
+	metric := &testMetric{}
+	m.priorityDepth[priority] = metric
+	return metric
+}
+
+func (m *testPriorityMetricsProvider) NewPriorityLatencyMetric(name string, priority int) HistogramMetric {
+	metric := &testMetric{}
+	m.priorityLatency[priority] = metric
+	return metric
+}
+
+func TestPriorityQueueMetrics(t *testing.T) {
+	mp := &testPriorityMetricsProvider{
+		priorityDepth:   map[int]*testMetric{},
+		priorityLatency: map[int]*testMetric{},
+	}
+	c := testingclock.NewFakeClock(time.Unix(0, 0))
+	q := NewTypedPriorityRateLimitingQueueWithConfig(DefaultTypedControllerRateLimiter[string](), TypedPriorityRateLimitingQueueConfig[string]{
+		Name:            "test",
+		MetricsProvider: mp,
+		Clock:           c,
+		AgingInterval:   -1,
+	})
+	defer q.ShutDown()
+
+	q.Add("a")
+	q.Add("b")
+	q.AddWithPriority("c", 1)
+	q.AddWithPriority("b", 2)
+	if e, a := 3.0, mp.depth.gaugeValue(); e != a {
+		t.Errorf("expected %v, got %v", e, a)
+	}
+	for priority, e := range map[int]float64{0: 1, 1: 1, 2: 1} {
+		if a := mp.priorityDepth[priority].gaugeValue(); e != a {
+			t.Errorf("priority %d: expected depth %v, got %v", priority, e, a)
+		}
+	}
+
+	c.Step(50 * time.Microsecond)
+	expectOrder(t, []string{"b"}, getAll(t, q, 1))
+	if e, a := 0.0, mp.priorityDepth[2].gaugeValue(); e != a {
+		t.Errorf("expected %v, got %v", e, a)
+	}
+	if e, a := 5e-05, mp.priorityLatency[2].observationValue(); e != a {
+		t.Errorf("expected %v, got %v", e, a)
+	}
+	if _, ok := mp.priorityLatency[0]; ok {
+		t.Errorf("unexpected latency metric for priority 0")
+	}
+}